@@ -6,10 +6,10 @@ import (
 	"cij_api/src/model"
 	vacancy "cij_api/src/model/vacancy"
 	"cij_api/src/router"
+	"flag"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"gorm.io/gorm"
 )
 
@@ -21,6 +21,10 @@ import (
 // @host conexao-inclusao.com
 // @BasePath /
 func main() {
+	migrate := flag.String("migrate", "", "apply the pending migrations (up) or revert the latest one (down) and exit")
+	seed := flag.Bool("seed", false, "populate the database with the disabilities catalog and demo data and exit")
+	flag.Parse()
+
 	loadConfig, err := config.LoadConfig(".")
 	if err != nil {
 		log.Fatal("cannot load enviroment variables", err)
@@ -28,11 +32,58 @@ func main() {
 
 	db := database.ConnectionDB(&loadConfig)
 
+	if *migrate != "" {
+		runMigrationCommand(db, *migrate)
+		return
+	}
+
+	if *seed {
+		seedDatabase(db)
+		return
+	}
+
 	migrateDb(db)
 
+	if err := database.RunMigrations(db); err != nil {
+		log.Fatal("cannot run the database migrations: ", err)
+	}
+
 	startServer(db)
 }
 
+// runMigrationCommand handles the -migrate flag, so the versioned migrations can be
+// applied or reverted without starting the server
+func runMigrationCommand(db *gorm.DB, direction string) {
+	switch direction {
+	case "up":
+		migrateDb(db)
+
+		if err := database.RunMigrations(db); err != nil {
+			log.Fatal("cannot run the database migrations: ", err)
+		}
+	case "down":
+		if err := database.RollbackMigration(db); err != nil {
+			log.Fatal("cannot revert the database migration: ", err)
+		}
+	default:
+		log.Fatalf("invalid -migrate value %q, use up or down", direction)
+	}
+}
+
+// seedDatabase handles the -seed flag, filling a new environment with the default
+// roles, the disabilities catalog and the demo data
+func seedDatabase(db *gorm.DB) {
+	migrateDb(db)
+
+	if err := createDefaultDisabilities(db); err != nil {
+		log.Fatal("cannot seed the disabilities: ", err)
+	}
+
+	if err := database.SeedDemoData(db); err != nil {
+		log.Fatal("cannot seed the demo data: ", err)
+	}
+}
+
 func migrateDb(db *gorm.DB) {
 	db.AutoMigrate(&model.User{})
 	db.AutoMigrate(&model.Address{})
@@ -43,13 +94,18 @@ func migrateDb(db *gorm.DB) {
 	db.AutoMigrate(&model.News{})
 	db.AutoMigrate(&model.Role{})
 	db.AutoMigrate(&model.Activity{})
+	db.AutoMigrate(&model.PasswordResetToken{})
+	db.AutoMigrate(&model.AuditLog{})
 
 	db.AutoMigrate(&vacancy.Vacancy{})
 	db.AutoMigrate(&vacancy.VacancyDisability{})
 	db.AutoMigrate(&vacancy.VacancySkill{})
 	db.AutoMigrate(&vacancy.VacancyRequirement{})
 	db.AutoMigrate(&vacancy.VacancyResponsability{})
+	db.AutoMigrate(&vacancy.VacancyAccommodation{})
 	db.AutoMigrate(&vacancy.VacancyApply{})
+	db.AutoMigrate(&vacancy.VacancyIdempotencyKey{})
+	db.AutoMigrate(&vacancy.JobAlert{})
 
 	createDefaultRoles(db)
 	createDefaultDisabilities(db)
@@ -100,13 +156,6 @@ func createDefaultDisabilities(db *gorm.DB) error {
 func startServer(db *gorm.DB) {
 	app := fiber.New()
 
-	app.Use(cors.New())
-
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Access-Control-Allow-Origin",
-	}))
-
 	routes := router.NewRouter(app, db)
 
 	err := routes.Listen(":3040")
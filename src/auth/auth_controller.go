@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"cij_api/src/enum"
 	"cij_api/src/model"
 	"cij_api/src/service"
+	"cij_api/src/utils"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
@@ -60,12 +62,16 @@ func (c *AuthController) Authenticate(ctx *fiber.Ctx) error {
 	}
 
 	user, err := c.authService.Authenticate(credentials)
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.LoginResponse{
 			Message: err.Error(),
 			Code:    err.Code,
 		}
 
+		if utils.HttpStatus(err) == http.StatusForbidden {
+			return ctx.Status(http.StatusForbidden).JSON(response)
+		}
+
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
@@ -74,7 +80,7 @@ func (c *AuthController) Authenticate(ctx *fiber.Ctx) error {
 
 	if user.ConfigUrl != "" {
 		userConfig, err = c.configService.GetUserConfig(user.ConfigUrl)
-		if err.Code != "" {
+		if err.IsError() {
 			response = model.LoginResponse{
 				Message: err.Error(),
 				Code:    err.Code,
@@ -85,7 +91,7 @@ func (c *AuthController) Authenticate(ctx *fiber.Ctx) error {
 	}
 
 	token, err := c.authService.GenerateToken(user)
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.LoginResponse{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -129,12 +135,16 @@ func (c *AuthController) GetUserData(ctx *fiber.Ctx) error {
 	}
 
 	user, err := c.authService.GetUserData(token.Token)
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.LoginResponse{
 			Message: err.Error(),
 			Code:    err.Code,
 		}
 
+		if IsTokenError(err) {
+			return ctx.Status(http.StatusUnauthorized).JSON(response)
+		}
+
 		return ctx.Status(http.StatusInternalServerError).JSON(response)
 	}
 
@@ -143,7 +153,7 @@ func (c *AuthController) GetUserData(ctx *fiber.Ctx) error {
 
 	if user.ConfigUrl != "" {
 		userConfig, err = c.configService.GetUserConfig(user.ConfigUrl)
-		if err.Code != "" {
+		if err.IsError() {
 			response = model.LoginResponse{
 				Message: err.Error(),
 				Code:    err.Code,
@@ -153,9 +163,9 @@ func (c *AuthController) GetUserData(ctx *fiber.Ctx) error {
 		}
 	}
 
-	if user.RoleId == 2 {
+	if user.HasRole(enum.CompanyRole) {
 		company, err := c.companyService.GetCompanyByUserId(user.Id)
-		if err.Code != "" {
+		if err.IsError() {
 			response = model.LoginResponse{
 				Message: err.Error(),
 				Code:    err.Code,
@@ -169,7 +179,7 @@ func (c *AuthController) GetUserData(ctx *fiber.Ctx) error {
 
 		if company.AddressId != nil {
 			address, err := c.addressService.GetAddressById(*company.AddressId)
-			if err.Code != "" {
+			if err.IsError() {
 				response = model.LoginResponse{
 					Message: err.Error(),
 					Code:    err.Code,
@@ -191,7 +201,7 @@ func (c *AuthController) GetUserData(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusOK).JSON(response)
 	} else {
 		person, err := c.personService.GetPersonByUserId(user.Id)
-		if err.Code != "" {
+		if err.IsError() {
 			response = model.LoginResponse{
 				Message: err.Error(),
 				Code:    err.Code,
@@ -205,7 +215,7 @@ func (c *AuthController) GetUserData(ctx *fiber.Ctx) error {
 
 		if person.AddressId != nil {
 			address, err := c.addressService.GetAddressById(*person.AddressId)
-			if err.Code != "" {
+			if err.IsError() {
 				response = model.LoginResponse{
 					Message: err.Error(),
 					Code:    err.Code,
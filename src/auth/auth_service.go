@@ -6,6 +6,8 @@ import (
 	"cij_api/src/repo"
 	"cij_api/src/service"
 	"cij_api/src/utils"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -29,7 +31,18 @@ func authServiceError(message string, code string) utils.Error {
 	return utils.NewError(message, errorCode)
 }
 
+func IsTokenError(err utils.Error) bool {
+	expiredCode := utils.NewErrorCode(utils.ServiceErrorCode, utils.UserErrorType, "07")
+	invalidCode := utils.NewErrorCode(utils.ServiceErrorCode, utils.UserErrorType, "08")
+
+	return err.Code == expiredCode || err.Code == invalidCode
+}
+
 func getSecretKey() ([]byte, utils.Error) {
+	if secretKey := os.Getenv("SECRET_KEY"); secretKey != "" {
+		return []byte(secretKey), utils.Error{}
+	}
+
 	loadConfig, err := config.LoadConfig("../")
 	if err != nil {
 		return nil, authServiceError("failed to load config", "01")
@@ -40,14 +53,22 @@ func getSecretKey() ([]byte, utils.Error) {
 
 func (s *AuthService) GenerateToken(user model.User) (string, utils.Error) {
 	secretKey, err := getSecretKey()
-	if err.Code != "" {
+	if err.IsError() {
 		return "", err
 	}
 
-	claims := &jwt.MapClaims{
-		"exp":   jwt.TimeFunc().Add(time.Hour * 24).Unix(),
-		"role":  user.Role.Name,
-		"email": user.Email,
+	var roleName string
+	if user.Role != nil {
+		roleName = user.Role.Name
+	}
+
+	claims := &model.UserClaims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: jwt.TimeFunc().Add(time.Hour * 24).Unix(),
+		},
+		Id:    user.Id,
+		Email: user.Email,
+		Role:  roleName,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -60,22 +81,40 @@ func (s *AuthService) GenerateToken(user model.User) (string, utils.Error) {
 	return tokenString, utils.Error{}
 }
 
-func ValidateToken(tokenString string) (*jwt.Token, error) {
+func ValidateToken(tokenString string) (model.UserClaims, utils.Error) {
+	var claims model.UserClaims
+
 	secret, err := getSecretKey()
-	if err.Code != "" {
-		return nil, err
+	if err.IsError() {
+		return claims, err
 	}
 
-	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
+	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+
+	token, tokenError := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+
+		return secret, nil
 	})
+
+	if validationError, ok := tokenError.(*jwt.ValidationError); ok && validationError.Errors&jwt.ValidationErrorExpired != 0 {
+		return claims, authServiceError("token expired", "07")
+	}
+
+	if tokenError != nil || !token.Valid {
+		return claims, authServiceError("token malformed or invalid", "08")
+	}
+
+	return claims, utils.Error{}
 }
 
 func (s *AuthService) Authenticate(credentials model.Credentials) (model.User, utils.Error) {
 	var user model.User
 
 	user, err := s.userRepo.GetUserByEmail(credentials.Email)
-	if err.Code != "" {
+	if err.IsError() {
 		return user, err
 	}
 
@@ -87,6 +126,12 @@ func (s *AuthService) Authenticate(credentials model.Credentials) (model.User, u
 		return user, authServiceError("invalid password", "04")
 	}
 
+	if !user.Active {
+		errorCode := utils.NewErrorCode(utils.ForbiddenErrorCode, utils.UserErrorType, "09")
+
+		return user, utils.NewError("account disabled", errorCode)
+	}
+
 	activityService := service.NewActivityService(s.activityRepo)
 	activity := model.Activity{
 		Type:        "login",
@@ -95,26 +140,37 @@ func (s *AuthService) Authenticate(credentials model.Credentials) (model.User, u
 	}
 
 	activityError := activityService.CreateActivity(&activity)
-	if activityError.Code != "" {
+	if activityError.IsError() {
 		return user, activityError
 	}
 
 	return user, utils.Error{}
 }
 
+func (s *AuthService) Login(email string, password string) (string, utils.Error) {
+	credentials := model.Credentials{
+		Email:    email,
+		Password: password,
+	}
+
+	user, err := s.Authenticate(credentials)
+	if err.IsError() {
+		return "", err
+	}
+
+	return s.GenerateToken(user)
+}
+
 func (s *AuthService) GetUserData(token string) (model.User, utils.Error) {
 	var user model.User
 
-	tokenData, err := ValidateToken(token)
-	if err != nil {
-		return user, authServiceError("failed to validate token", "05")
+	claims, err := ValidateToken(token)
+	if err.IsError() {
+		return user, err
 	}
 
-	claims := tokenData.Claims.(jwt.MapClaims)
-	tokenEmail := claims["email"].(string)
-
-	user, userError := s.userRepo.GetUserByEmail(tokenEmail)
-	if userError.Code != "" {
+	user, userError := s.userRepo.GetUserByEmail(claims.Email)
+	if userError.IsError() {
 		return user, userError
 	}
 
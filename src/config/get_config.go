@@ -1,16 +1,78 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
 	DbConnection string `mapstructure:"DSN"`
 	SecretKey    string `mapstructure:"SECRET_KEY"`
 }
 
+type DatabaseConfig struct {
+	MaxOpenConns    int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+}
+
 type CloudinaryConfig struct {
 	CloudinaryUrl string `mapstructure:"CLOUDINARY_URL"`
 }
 
+type FilesConfig struct {
+	MaxResumeSize int64 `mapstructure:"MAX_RESUME_SIZE"`
+}
+
+type VacancyConfig struct {
+	IdempotencyKeyTTL  time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	MaxPerPage         int           `mapstructure:"VACANCIES_MAX_PER_PAGE"`
+	MaxDescription     int           `mapstructure:"VACANCY_DESCRIPTION_MAX_LENGTH"`
+	JobAlertsInterval  time.Duration `mapstructure:"JOB_ALERTS_INTERVAL"`
+	DuplicateWindow    time.Duration `mapstructure:"VACANCY_DUPLICATE_WINDOW"`
+	MaxOpenPerCompany  int           `mapstructure:"VACANCY_MAX_OPEN_PER_COMPANY"`
+	ViewThrottleWindow time.Duration `mapstructure:"VACANCY_VIEW_THROTTLE_WINDOW"`
+	PopularWindow      time.Duration `mapstructure:"VACANCY_POPULAR_WINDOW"`
+}
+
+type RateLimitConfig struct {
+	LoginAttempts int           `mapstructure:"LOGIN_RATE_LIMIT_ATTEMPTS"`
+	LoginWindow   time.Duration `mapstructure:"LOGIN_RATE_LIMIT_WINDOW"`
+	LoginByEmail  bool          `mapstructure:"LOGIN_RATE_LIMIT_BY_EMAIL"`
+}
+
+type CorsConfig struct {
+	AllowedOrigins   string        `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   string        `mapstructure:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   string        `mapstructure:"CORS_ALLOWED_HEADERS"`
+	AllowCredentials bool          `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `mapstructure:"CORS_MAX_AGE"`
+}
+
+type RequestConfig struct {
+	MaxBodySize int  `mapstructure:"REQUEST_MAX_BODY_SIZE"`
+	StrictJson  bool `mapstructure:"REQUEST_STRICT_JSON"`
+}
+
+type PasswordConfig struct {
+	MinLength     int  `mapstructure:"PASSWORD_MIN_LENGTH"`
+	RequireLower  bool `mapstructure:"PASSWORD_REQUIRE_LOWER"`
+	RequireUpper  bool `mapstructure:"PASSWORD_REQUIRE_UPPER"`
+	RequireDigit  bool `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	RequireSymbol bool `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+}
+
+type MailerConfig struct {
+	Enabled      bool   `mapstructure:"MAIL_ENABLED"`
+	AppUrl       string `mapstructure:"APP_URL"`
+	SmtpHost     string `mapstructure:"SMTP_HOST"`
+	SmtpPort     int    `mapstructure:"SMTP_PORT"`
+	SmtpUser     string `mapstructure:"SMTP_USER"`
+	SmtpPassword string `mapstructure:"SMTP_PASSWORD"`
+	From         string `mapstructure:"MAIL_FROM"`
+}
+
 func LoadConfig(path string) (config Config, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName("app")
@@ -26,6 +88,25 @@ func LoadConfig(path string) (config Config, err error) {
 	return
 }
 
+func LoadDatabaseConfig(path string) (config DatabaseConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
+	viper.SetDefault("DB_CONN_MAX_LIFETIME", "5m")
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
 func LoadCloudinaryConfig(path string) (config CloudinaryConfig, err error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigType("env")
@@ -41,3 +122,142 @@ func LoadCloudinaryConfig(path string) (config CloudinaryConfig, err error) {
 	err = viper.Unmarshal(&config)
 	return
 }
+
+func LoadFilesConfig(path string) (config FilesConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("MAX_RESUME_SIZE", 5*1024*1024)
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
+func LoadVacancyConfig(path string) (config VacancyConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+	viper.SetDefault("VACANCIES_MAX_PER_PAGE", 100)
+	viper.SetDefault("VACANCY_DESCRIPTION_MAX_LENGTH", 10000)
+	viper.SetDefault("JOB_ALERTS_INTERVAL", "1h")
+	viper.SetDefault("VACANCY_DUPLICATE_WINDOW", "168h")
+	viper.SetDefault("VACANCY_MAX_OPEN_PER_COMPANY", 50)
+	viper.SetDefault("VACANCY_VIEW_THROTTLE_WINDOW", "30m")
+	viper.SetDefault("VACANCY_POPULAR_WINDOW", "720h")
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
+func LoadRateLimitConfig(path string) (config RateLimitConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("LOGIN_RATE_LIMIT_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_RATE_LIMIT_WINDOW", "15m")
+	viper.SetDefault("LOGIN_RATE_LIMIT_BY_EMAIL", false)
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
+func LoadCorsConfig(path string) (config CorsConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("CORS_ALLOWED_ORIGINS", "https://conexao-inclusao.com")
+	viper.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE")
+	viper.SetDefault("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,Idempotency-Key,X-Request-Id,If-None-Match,If-Modified-Since")
+	viper.SetDefault("CORS_ALLOW_CREDENTIALS", true)
+	viper.SetDefault("CORS_MAX_AGE", "10m")
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
+func LoadMailerConfig(path string) (config MailerConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("MAIL_ENABLED", false)
+	viper.SetDefault("APP_URL", "https://conexao-inclusao.com")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
+func LoadPasswordConfig(path string) (config PasswordConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_REQUIRE_LOWER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_UPPER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_DIGIT", true)
+	viper.SetDefault("PASSWORD_REQUIRE_SYMBOL", false)
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
+
+func LoadRequestConfig(path string) (config RequestConfig, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigType("env")
+	viper.SetConfigName("app")
+
+	viper.SetDefault("REQUEST_MAX_BODY_SIZE", 1048576)
+	viper.SetDefault("REQUEST_STRICT_JSON", false)
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
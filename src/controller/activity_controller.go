@@ -42,7 +42,7 @@ func (a *ActivityController) CreateActivity(ctx *fiber.Ctx) error {
 
 	activity := activityRequest.ToModel()
 
-	if err := a.activityService.CreateActivity(activity); err.Code != "" {
+	if err := a.activityService.CreateActivity(activity); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -93,7 +93,7 @@ func (a *ActivityController) GetActivitiesByTypeAndPeriod(ctx *fiber.Ctx) error
 	}
 
 	activities, activitiesError := a.activityService.GetActivitiesByTypeAndPeriod(activityType, startDateInt, endDateInt)
-	if activitiesError.Code != "" {
+	if activitiesError.IsError() {
 		response := model.Response{
 			Message: activitiesError.Error(),
 			Code:    activitiesError.Code,
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"cij_api/src/model"
+	"cij_api/src/service"
+	"cij_api/src/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditLogController struct {
+	auditLogService service.AuditLogService
+}
+
+func NewAuditLogController(auditLogService service.AuditLogService) AuditLogController {
+	return AuditLogController{
+		auditLogService: auditLogService,
+	}
+}
+
+// ListAuditLogs
+// @Summary List audit logs
+// @Description List who created, updated or deleted the entity and what changed, most recent first
+// @Tags AuditLogs
+// @Accept json
+// @Produce json
+// @Param entity query string true "Entity (vacancy)"
+// @Param entity_id query int true "Entity ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /audit-logs [get]
+func (a *AuditLogController) ListAuditLogs(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	entityId, convErr := strconv.Atoi(ctx.Query("entity_id"))
+	if convErr != nil {
+		response = model.Response{
+			Message: "entity_id is required",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	auditLogs, err := a.auditLogService.ListAuditLogs(ctx.Query("entity"), entityId)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "audit logs listed successfully",
+		Data:    auditLogs,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
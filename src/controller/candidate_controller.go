@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"cij_api/src/middleware"
+	"cij_api/src/model"
+	"cij_api/src/service"
+	"cij_api/src/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CandidateController struct {
+	candidateService service.CandidateService
+}
+
+func NewCandidateController(candidateService service.CandidateService) *CandidateController {
+	return &CandidateController{
+		candidateService: candidateService,
+	}
+}
+
+// ListCandidates
+// @Summary List all candidates.
+// @Description list all registered candidates with their disabilities.
+// @Tags Candidates
+// @Accept application/json
+// @Produce json
+// @Param Authorization header string true "Token"
+// @Success 200 {array} model.CandidateResponse
+// @Failure 500 {object} MessageResponse
+// @Router /candidates [get]
+func (c *CandidateController) ListCandidates(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	candidates, err := c.candidateService.ListCandidates()
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Error(),
+			Code:    err.Code,
+		}
+
+		return ctx.Status(http.StatusInternalServerError).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    candidates,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// SearchCandidates
+// @Summary Search the candidate pool.
+// @Description search the candidates by disability category and area, sorted by name, without their cpf.
+// @Tags Candidates
+// @Accept application/json
+// @Produce json
+// @Param disability_category query string false "Disability category"
+// @Param area query string false "Area"
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Router /candidates/search [get]
+func (c *CandidateController) SearchCandidates(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+
+	candidates, err := c.candidateService.SearchCandidates(ctx.Query("disability_category"), ctx.Query("area"), page, perPage, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "candidates listed successfully",
+		Data:    candidates,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// ExportCandidateData
+// @Summary Export the candidate data.
+// @Description export as json the profile, applications and uploaded files of the candidate, for the LGPD data access requests.
+// @Tags Candidates
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {file} file
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /candidates/{id}/data [get]
+func (c *CandidateController) ExportCandidateData(ctx *fiber.Ctx) error {
+	candidateId, _ := strconv.Atoi(ctx.Params("id"))
+
+	data, err := c.candidateService.ExportCandidateData(candidateId, middleware.Claims(ctx))
+	if err.IsError() {
+		response := model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="candidate-data.json"`)
+
+	return ctx.Status(http.StatusOK).Send(data)
+}
+
+// DeleteCandidateData
+// @Summary Delete the candidate data.
+// @Description anonymize the personal data of the candidate, keeping the applications for the aggregate reports, for the LGPD erasure requests.
+// @Tags Candidates
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /candidates/{id}/data [delete]
+func (c *CandidateController) DeleteCandidateData(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	candidateId, _ := strconv.Atoi(ctx.Params("id"))
+
+	err := c.candidateService.DeleteCandidateData(candidateId, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "candidate data deleted successfully",
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// GetCandidate
+// @Summary Get a candidate by ID.
+// @Description get a candidate by their ID.
+// @Tags Candidates
+// @Accept application/json
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.CandidateResponse
+// @Failure 404 {object} MessageResponse
+// @Failure 500 {object} MessageResponse
+// @Router /candidates/:id [get]
+func (c *CandidateController) GetCandidate(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	idInt, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	candidate, errCandidate := c.candidateService.GetCandidateById(idInt)
+	if errCandidate.Message == "candidate not found" {
+		response = model.Response{
+			Message: errCandidate.Error(),
+			Code:    errCandidate.Code,
+		}
+
+		return ctx.Status(http.StatusNotFound).JSON(response)
+	}
+
+	if errCandidate.IsError() {
+		response = model.Response{
+			Message: errCandidate.Error(),
+			Code:    errCandidate.Code,
+		}
+
+		return ctx.Status(http.StatusInternalServerError).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    candidate,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// UploadResume
+// @Summary Upload a candidate resume.
+// @Description upload a pdf resume for a candidate, stored as their curriculum.
+// @Tags Candidates
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param file formData file true "Resume"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Failure 500 {object} model.Response
+// @Router /candidates/:id/resume [post]
+func (c *CandidateController) UploadResume(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	idInt, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	openFile, err := file.Open()
+	if err != nil {
+		response = model.Response{
+			Message: "failed to open the file",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	defer openFile.Close()
+
+	resumeUrl, errResume := c.candidateService.UploadResume(idInt, openFile, file.Header.Get("Content-Type"))
+	if errResume.IsError() {
+		response = model.Response{
+			Message: errResume.Error(),
+			Code:    errResume.Code,
+		}
+
+		switch errResume.Message {
+		case "candidate not found":
+			return ctx.Status(http.StatusNotFound).JSON(response)
+		case "failed to get the candidate", "failed to upload the resume":
+			return ctx.Status(http.StatusInternalServerError).JSON(response)
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    resumeUrl,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
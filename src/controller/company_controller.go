@@ -42,25 +42,42 @@ func (n *CompanyController) CreateCompany(ctx *fiber.Ctx) error {
 	var companyRequest model.CompanyRequest
 	var response model.Response
 
-	if err := ctx.BodyParser(&companyRequest); err != nil {
+	if err := parseBody(ctx, &companyRequest); err.IsError() {
 		response = model.Response{
-			Message: err.Error(),
+			Message: err.Message,
+			Code:    err.Code,
 		}
 
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := validateCompanyRequiredFields(companyRequest); err.Code != "" {
-		response = model.Response{
-			Message: err.Error(),
-			Code:    err.Code,
-			Fields:  err.Fields,
+	companyRequest.Cnpj = utils.NormalizeCnpj(companyRequest.Cnpj)
+
+	if companyRequest.Phone != "" {
+		phone, err := utils.NormalizePhone(companyRequest.Phone)
+		if err.IsError() {
+			response = model.Response{
+				Message: err.Error(),
+				Code:    err.Code,
+				Fields:  []model.Field{{Name: "phone", Message: err.Message}},
+			}
+
+			return ctx.Status(http.StatusBadRequest).JSON(response)
 		}
 
-		return ctx.Status(http.StatusBadRequest).JSON(response)
+		companyRequest.Phone = phone
+	}
+
+	companyValidation := companyRequest.Validate()
+
+	if companyRequest.Cnpj != "" && !utils.ValidateCNPJ(companyRequest.Cnpj) {
+		companyValidation.Add("cnpj", "03", "cnpj is not valid")
 	}
 
-	if err := n.validateCompany(companyRequest); err.Code != "" {
+	validation := utils.EntityValidation(utils.CompanyErrorType, companyValidation)
+	validation.Merge(utils.UserValidation(companyRequest.User))
+
+	if err := utils.NewValidationError(validation); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -70,7 +87,7 @@ func (n *CompanyController) CreateCompany(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := utils.ValidateUser(companyRequest.User); err.Code != "" {
+	if err := n.validateCompany(companyRequest); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -80,7 +97,7 @@ func (n *CompanyController) CreateCompany(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := utils.ValidateAddress(companyRequest.Address); err.Code != "" {
+	if err := utils.ValidateAddress(companyRequest.Address); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -90,12 +107,16 @@ func (n *CompanyController) CreateCompany(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.companyService.CreateCompany(companyRequest); err.Code != "" {
+	if err := n.companyService.CreateCompany(companyRequest); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
 		}
 
+		if utils.HttpStatus(err) == http.StatusConflict {
+			return ctx.Status(http.StatusConflict).JSON(response)
+		}
+
 		return ctx.Status(http.StatusInternalServerError).JSON(response)
 	}
 
@@ -108,10 +129,12 @@ func (n *CompanyController) CreateCompany(ctx *fiber.Ctx) error {
 
 // ListCompanies
 // @Summary List all registered companies.
-// @Description list all registered companies and their users.
+// @Description list all registered companies and their users. The phone and the user are only shown to authenticated callers.
 // @Tags Companies
 // @Accept application/json
 // @Produce json
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
 // @Success 200 {array} model.CompanyResponse
 // @Failure 404 {object} string "not found"
 // @Failure 500 {object} string "internal server error"
@@ -119,8 +142,13 @@ func (n *CompanyController) CreateCompany(ctx *fiber.Ctx) error {
 func (n *CompanyController) ListCompanies(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	companies, err := n.companyService.ListCompanies()
-	if err.Code != "" {
+	page := ctx.QueryInt("page", 1)
+	if page <= 0 {
+		page = 1
+	}
+
+	companies, err := n.companyService.ListCompanies(page, ctx.QueryInt("per_page"))
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -134,12 +162,21 @@ func (n *CompanyController) ListCompanies(ctx *fiber.Ctx) error {
 		Data:    companies,
 	}
 
+	if _, authenticated := model.CurrentUser(ctx.UserContext()); !authenticated {
+		publicCompanies := []model.CompanyPublicResponse{}
+		for _, company := range companies {
+			publicCompanies = append(publicCompanies, company.ToPublicResponse())
+		}
+
+		response.Data = publicCompanies
+	}
+
 	return ctx.Status(http.StatusOK).JSON(response)
 }
 
 // GetCompany
 // @Summary Get a company by ID.
-// @Description get a company by ID and their user.
+// @Description get a company by ID and their user. The phone and the user are only shown to authenticated callers.
 // @Tags Companies
 // @Accept application/json
 // @Produce json
@@ -163,7 +200,7 @@ func (n *CompanyController) GetCompany(ctx *fiber.Ctx) error {
 	}
 
 	company, err := n.companyService.GetCompanyById(idInt)
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -172,9 +209,58 @@ func (n *CompanyController) GetCompany(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusInternalServerError).JSON(response)
 	}
 
+	if company.Id == 0 {
+		response = model.Response{
+			Message: "company not found",
+		}
+
+		return ctx.Status(http.StatusNotFound).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    company,
+	}
+
+	if _, authenticated := model.CurrentUser(ctx.UserContext()); !authenticated {
+		response.Data = company.ToPublicResponse()
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// GetCompanyByCnpj
+// @Summary Get a company by CNPJ.
+// @Description get a company by its CNPJ, formatted or only the digits.
+// @Tags Companies
+// @Accept application/json
+// @Produce json
+// @Param cnpj query string true "Company CNPJ"
+// @Success 200 {object} model.CompanyResponse
+// @Failure 400 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /companies/by-cnpj [get]
+func (n *CompanyController) GetCompanyByCnpj(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	company, err := n.companyService.GetCompanyByCnpj(ctx.Query("cnpj"))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Error(),
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
 	response = model.Response{
 		Message: "success",
-		Data:    company.ToResponse(*company.User),
+		Data:    company,
+	}
+
+	if _, authenticated := model.CurrentUser(ctx.UserContext()); !authenticated {
+		response.Data = company.ToPublicResponse()
 	}
 
 	return ctx.Status(http.StatusOK).JSON(response)
@@ -197,9 +283,10 @@ func (n *CompanyController) UpdateCompany(ctx *fiber.Ctx) error {
 	var companyRequest model.CompanyRequest
 	var response model.Response
 
-	if err := ctx.BodyParser(&companyRequest); err != nil {
+	if err := parseBody(ctx, &companyRequest); err.IsError() {
 		response = model.Response{
-			Message: err.Error(),
+			Message: err.Message,
+			Code:    err.Code,
 		}
 
 		return ctx.Status(http.StatusBadRequest).JSON(response)
@@ -216,12 +303,16 @@ func (n *CompanyController) UpdateCompany(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.companyService.UpdateCompany(companyRequest, idInt); err.Code != "" {
+	if err := n.companyService.UpdateCompany(companyRequest, idInt); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
 		}
 
+		if err.Message == "company not found" {
+			return ctx.Status(http.StatusNotFound).JSON(response)
+		}
+
 		return ctx.Status(http.StatusInternalServerError).JSON(response)
 	}
 
@@ -258,7 +349,7 @@ func (n *CompanyController) DeleteCompany(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.companyService.DeleteCompany(idInt); err.Code != "" {
+	if err := n.companyService.DeleteCompany(idInt); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 		}
@@ -273,48 +364,151 @@ func (n *CompanyController) DeleteCompany(ctx *fiber.Ctx) error {
 	return ctx.Status(http.StatusOK).JSON(response)
 }
 
-func validateCompanyRequiredFields(company model.CompanyRequest) utils.Error {
-	fieldsWithError := []model.Field{}
+// UploadCompanyLogo
+// @Summary Upload a company logo.
+// @Description upload a png or jpeg logo for a company.
+// @Tags Companies
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param file formData file true "Logo"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Failure 500 {object} model.Response
+// @Router /companies/:id/logo [post]
+// VerifyCompany
+// @Summary Verify a company.
+// @Description mark the company as verified after confirming it is legitimate. The badge is shown on the company and on its vacancies.
+// @Tags Companies
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /companies/{id}/verify [patch]
+func (n *CompanyController) VerifyCompany(ctx *fiber.Ctx) error {
+	return n.setCompanyVerified(ctx, true)
+}
 
-	if company.Cnpj == "" {
-		fieldsWithError = append(fieldsWithError, model.Field{Name: "cnpj"})
-	}
+// UnverifyCompany
+// @Summary Unverify a company.
+// @Description remove the verified badge from the company and its vacancies.
+// @Tags Companies
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /companies/{id}/unverify [patch]
+func (n *CompanyController) UnverifyCompany(ctx *fiber.Ctx) error {
+	return n.setCompanyVerified(ctx, false)
+}
+
+func (n *CompanyController) setCompanyVerified(ctx *fiber.Ctx, verified bool) error {
+	var response model.Response
+
+	companyId, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: "invalid company id",
+		}
 
-	if company.Name == "" {
-		fieldsWithError = append(fieldsWithError, model.Field{Name: "name"})
+		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if company.Phone == "" {
-		fieldsWithError = append(fieldsWithError, model.Field{Name: "phone"})
+	var updateErr utils.Error
+	var message string
+
+	if verified {
+		updateErr = n.companyService.VerifyCompany(companyId)
+		message = "company verified successfully"
+	} else {
+		updateErr = n.companyService.UnverifyCompany(companyId)
+		message = "company unverified successfully"
 	}
 
-	if len(fieldsWithError) > 0 {
-		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.CompanyErrorType, "01")
+	if updateErr.IsError() {
+		response = model.Response{
+			Message: updateErr.Error(),
+			Code:    updateErr.Code,
+		}
 
-		return utils.NewErrorWithFields("required fields are missing", errorCode, fieldsWithError)
+		return ctx.Status(utils.HttpStatus(updateErr)).JSON(response)
 	}
 
-	return utils.Error{}
+	response = model.Response{
+		Message: message,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
 }
 
-func (c *CompanyController) validateCompany(companyRequest model.CompanyRequest) utils.Error {
-	fieldsWithError := []model.Field{}
+func (n *CompanyController) UploadCompanyLogo(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	idInt, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
 
-	if len(companyRequest.Cnpj) != 14 {
-		fieldsWithError = append(fieldsWithError, model.Field{Name: "cnpj", Value: "cnpj must have 14 digits"})
+		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	company, err := c.companyService.GetCompanyByCnpj(companyRequest.Cnpj)
-	if err.Code != "" {
-		return err
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if company.Id != 0 {
-		return companyControllerError("cnpj already registered", "01", nil)
+	openFile, err := file.Open()
+	if err != nil {
+		response = model.Response{
+			Message: "failed to open the file",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
+	defer openFile.Close()
+
+	logoUrl, errLogo := n.companyService.UploadCompanyLogo(idInt, openFile, file.Header.Get("Content-Type"))
+	if errLogo.IsError() {
+		response = model.Response{
+			Message: errLogo.Error(),
+			Code:    errLogo.Code,
+		}
+
+		switch errLogo.Message {
+		case "company not found":
+			return ctx.Status(http.StatusNotFound).JSON(response)
+		case "failed to upload the logo", "failed to update the company logo":
+			return ctx.Status(http.StatusInternalServerError).JSON(response)
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    logoUrl,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// validateCompany checks the email of the company user is not taken, the fields are
+// validated before
+func (c *CompanyController) validateCompany(companyRequest model.CompanyRequest) utils.Error {
+	fieldsWithError := []model.Field{}
+
 	companyUser, err := c.companyService.GetUserByEmail(companyRequest.User.Email)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
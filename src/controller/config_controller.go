@@ -57,7 +57,7 @@ func (c *ConfigController) UpdateUserConfig(ctx *fiber.Ctx) error {
 	// }
 
 	err := c.configService.UploadUserConfig(userEmail, &configRequest)
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 		}
@@ -79,15 +79,15 @@ func validateUserConfig(config model.Config) utils.Error {
 	const MIN_FONT_SIZE = 14
 
 	if !config.Theme.IsValid() {
-		errorFields = append(errorFields, model.Field{Name: "theme", Value: "theme must be 'light', 'dark' or 'system'"})
+		errorFields = append(errorFields, model.Field{Name: "theme", Message: "theme must be 'light', 'dark' or 'system'"})
 	}
 
 	if config.FontSize < MIN_FONT_SIZE || config.FontSize > MAX_FONT_SIZE {
-		errorFields = append(errorFields, model.Field{Name: "font_size", Value: "font_size must be between 14 and 30"})
+		errorFields = append(errorFields, model.Field{Name: "font_size", Message: "font_size must be between 14 and 30"})
 	}
 
 	if !config.ColorBlindness.IsValid() {
-		errorFields = append(errorFields, model.Field{Name: "color_blindness", Value: "color_blindness must be 'normal', 'protanopia', 'deuteranopia' or 'tritanopia'"})
+		errorFields = append(errorFields, model.Field{Name: "color_blindness", Message: "color_blindness must be 'normal', 'protanopia', 'deuteranopia' or 'tritanopia'"})
 	}
 
 	errorColors := validateConfigColors(config)
@@ -120,13 +120,13 @@ func validateConfigColors(config model.Config) []model.Field {
 
 	for colorCategory, colorValue := range primaryColors {
 		if !isHexString(colorValue) {
-			errorColors = append(errorColors, model.Field{Name: colorCategory, Value: colorValue + " must be a valid hex color"})
+			errorColors = append(errorColors, model.Field{Name: colorCategory, Message: colorValue + " must be a valid hex color"})
 		}
 	}
 
 	for chartCategory, chartColor := range configColors.ChartColors {
 		if !isHexString(chartColor) {
-			errorColors = append(errorColors, model.Field{Name: string(chartCategory), Value: chartColor + " must be a valid hex color"})
+			errorColors = append(errorColors, model.Field{Name: string(chartCategory), Message: chartColor + " must be a valid hex color"})
 		}
 	}
 
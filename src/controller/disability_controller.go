@@ -42,7 +42,7 @@ func (c *DisabilityController) CreateDisability(ctx *fiber.Ctx) error {
 	}
 
 	err := c.disabilityService.CreateDisability(disabilityRequest.Disabilities)
-	if err.Code != "" {
+	if err.IsError() {
 		response := model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -57,3 +57,31 @@ func (c *DisabilityController) CreateDisability(ctx *fiber.Ctx) error {
 
 	return ctx.Status(fiber.StatusCreated).JSON(response)
 }
+
+// ListDisabilities
+// @Summary List the disabilities catalog.
+// @Description list the disabilities grouped by category.
+// @Tags Disabilities
+// @Produce json
+// @Success 200 {object} model.Response
+// @Router /disabilities [get]
+func (c *DisabilityController) ListDisabilities(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	disabilities, err := c.disabilityService.ListDisabilities()
+	if err.IsError() {
+		response := model.Response{
+			Message: err.Error(),
+			Code:    err.GetCode(),
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    model.GroupDisabilitiesByCategory(disabilities),
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
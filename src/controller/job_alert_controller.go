@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"cij_api/src/middleware"
+	"cij_api/src/model"
+	vacancy "cij_api/src/model/vacancy"
+	"cij_api/src/service"
+	"cij_api/src/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobAlertController struct {
+	jobAlertService service.JobAlertService
+}
+
+func NewJobAlertController(jobAlertService service.JobAlertService) *JobAlertController {
+	return &JobAlertController{
+		jobAlertService: jobAlertService,
+	}
+}
+
+// CreateJobAlert
+// @Summary Create a job alert
+// @Description Save the search criteria of a candidate to be emailed about the new matching vacancies
+// @Tags Job Alerts
+// @Accept json
+// @Produce json
+// @Param jobAlert body vacancy.JobAlertRequest true "Job alert"
+// @Param Authorization header string true "Token"
+// @Success 201 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /job-alerts [post]
+func (j *JobAlertController) CreateJobAlert(ctx *fiber.Ctx) error {
+	var jobAlertRequest vacancy.JobAlertRequest
+	var response model.Response
+
+	if err := ctx.BodyParser(&jobAlertRequest); err != nil {
+		response = model.Response{
+			Message: "failed to parse the request body",
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	jobAlertId, err := j.jobAlertService.CreateJobAlert(jobAlertRequest, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "job alert created successfully",
+		Data:    jobAlertId,
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ListJobAlerts
+// @Summary List the job alerts of a candidate
+// @Description List the job alerts of a candidate, including the paused ones
+// @Tags Job Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Router /job-alerts/candidate/{id} [get]
+func (j *JobAlertController) ListJobAlerts(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	candidateId, _ := strconv.Atoi(ctx.Params("id"))
+
+	jobAlerts, err := j.jobAlertService.ListJobAlerts(candidateId, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "job alerts listed successfully",
+		Data:    jobAlerts,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// PauseJobAlert
+// @Summary Pause a job alert
+// @Description Stop emailing the candidate about the alert until it is resumed
+// @Tags Job Alerts
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /job-alerts/{id}/pause [patch]
+func (j *JobAlertController) PauseJobAlert(ctx *fiber.Ctx) error {
+	return j.setJobAlertPaused(ctx, true)
+}
+
+// ResumeJobAlert
+// @Summary Resume a job alert
+// @Description Resume a paused job alert
+// @Tags Job Alerts
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /job-alerts/{id}/resume [patch]
+func (j *JobAlertController) ResumeJobAlert(ctx *fiber.Ctx) error {
+	return j.setJobAlertPaused(ctx, false)
+}
+
+func (j *JobAlertController) setJobAlertPaused(ctx *fiber.Ctx, paused bool) error {
+	var response model.Response
+
+	jobAlertId, _ := strconv.Atoi(ctx.Params("id"))
+
+	err := j.jobAlertService.SetJobAlertPaused(jobAlertId, paused, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "job alert updated successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteJobAlert
+// @Summary Delete a job alert
+// @Description Delete a job alert
+// @Tags Job Alerts
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /job-alerts/{id} [delete]
+func (j *JobAlertController) DeleteJobAlert(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	jobAlertId, _ := strconv.Atoi(ctx.Params("id"))
+
+	err := j.jobAlertService.DeleteJobAlert(jobAlertId, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "job alert deleted successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
@@ -33,7 +33,7 @@ func (n *NewsController) ListNews(ctx *fiber.Ctx) error {
 	var response model.Response
 
 	news, err := n.newsService.ListNews()
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.Code,
@@ -87,7 +87,7 @@ func (n *NewsController) CreateNews(ctx *fiber.Ctx) error {
 	}
 
 	newsError := n.newsService.CreateNews(request, files)
-	if newsError.Code != "" {
+	if newsError.IsError() {
 		return ctx.Status(http.StatusInternalServerError).JSON(model.Response{
 			Message: newsError.Error(),
 			Code:    newsError.Code,
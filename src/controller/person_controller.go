@@ -54,7 +54,7 @@ func (n *PersonController) CreatePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := validatePersonRequiredFields(personRequest); err.Code != "" {
+	if err := validatePersonRequiredFields(personRequest); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -64,7 +64,7 @@ func (n *PersonController) CreatePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := utils.ValidateUser(personRequest.User); err.Code != "" {
+	if err := utils.ValidateUser(personRequest.User); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -74,7 +74,7 @@ func (n *PersonController) CreatePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.validatePerson(personRequest); err.Code != "" {
+	if err := n.validatePerson(personRequest); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -84,7 +84,7 @@ func (n *PersonController) CreatePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := utils.ValidateAddress(personRequest.Address); err.Code != "" {
+	if err := utils.ValidateAddress(personRequest.Address); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -94,7 +94,7 @@ func (n *PersonController) CreatePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.validatePersonDisabilities(personRequest.Disabilities); err.Code != "" {
+	if err := n.validatePersonDisabilities(personRequest.Disabilities); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -103,7 +103,7 @@ func (n *PersonController) CreatePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.personService.CreatePerson(personRequest); err.Code != "" {
+	if err := n.personService.CreatePerson(personRequest); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -133,7 +133,7 @@ func (n *PersonController) ListPeople(ctx *fiber.Ctx) error {
 	var response model.Response
 
 	people, err := n.personService.ListPeople()
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -176,7 +176,7 @@ func (n *PersonController) GetPerson(ctx *fiber.Ctx) error {
 	}
 
 	person, errPerson := n.personService.GetPersonById(idInt)
-	if errPerson.Code != "" {
+	if errPerson.IsError() {
 		response = model.Response{
 			Message: errPerson.Error(),
 		}
@@ -236,7 +236,7 @@ func (n *PersonController) UpdatePerson(ctx *fiber.Ctx) error {
 	}
 
 	person, errPerson := n.personService.GetPersonById(idInt)
-	if errPerson.Code != "" {
+	if errPerson.IsError() {
 		response = model.Response{
 			Message: errPerson.Error(),
 		}
@@ -253,7 +253,7 @@ func (n *PersonController) UpdatePerson(ctx *fiber.Ctx) error {
 	}
 
 	// TODO: Validate only the passed fields
-	// if err := n.validatePerson(personRequest); err.Code != "" {
+	// if err := n.validatePerson(personRequest); err.IsError() {
 	// 	response = model.Response{
 	// 		Message: err.Error(),
 	// 		Code:    err.GetCode(),
@@ -263,7 +263,7 @@ func (n *PersonController) UpdatePerson(ctx *fiber.Ctx) error {
 	// 	return ctx.Status(http.StatusBadRequest).JSON(response)
 	// }
 
-	if err := n.personService.UpdatePerson(personRequest, idInt); err.Code != "" {
+	if err := n.personService.UpdatePerson(personRequest, idInt); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -315,7 +315,7 @@ func (n *PersonController) UpdatePersonAddress(ctx *fiber.Ctx) error {
 	}
 
 	person, errPerson := n.personService.GetPersonById(idInt)
-	if errPerson.Code != "" {
+	if errPerson.IsError() {
 		response = model.Response{
 			Message: errPerson.Error(),
 		}
@@ -331,7 +331,7 @@ func (n *PersonController) UpdatePersonAddress(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusNotFound).JSON(response)
 	}
 
-	if err := n.personService.UpdatePersonAddress(addressRequest, idInt, nil); err.Code != "" {
+	if err := n.personService.UpdatePersonAddress(addressRequest, idInt, nil); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -383,7 +383,7 @@ func (n *PersonController) UpdatePersonDisabilities(ctx *fiber.Ctx) error {
 	}
 
 	person, errPerson := n.personService.GetPersonById(idInt)
-	if errPerson.Code != "" {
+	if errPerson.IsError() {
 		response = model.Response{
 			Message: errPerson.Error(),
 		}
@@ -399,7 +399,7 @@ func (n *PersonController) UpdatePersonDisabilities(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusNotFound).JSON(response)
 	}
 
-	if err := n.validatePersonDisabilities(disabilities); err.Code != "" {
+	if err := n.validatePersonDisabilities(disabilities); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -408,7 +408,7 @@ func (n *PersonController) UpdatePersonDisabilities(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusBadRequest).JSON(response)
 	}
 
-	if err := n.personService.UpdatePersonDisabilities(disabilities, idInt, nil); err.Code != "" {
+	if err := n.personService.UpdatePersonDisabilities(disabilities, idInt, nil); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -450,7 +450,7 @@ func (n *PersonController) DeletePerson(ctx *fiber.Ctx) error {
 	}
 
 	person, errPerson := n.personService.GetPersonById(idInt)
-	if errPerson.Code != "" {
+	if errPerson.IsError() {
 		response = model.Response{
 			Message: errPerson.Error(),
 		}
@@ -466,7 +466,7 @@ func (n *PersonController) DeletePerson(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusNotFound).JSON(response)
 	}
 
-	if err := n.personService.DeletePerson(idInt); err.Code != "" {
+	if err := n.personService.DeletePerson(idInt); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -518,7 +518,7 @@ func (n *PersonController) UploadCurriculum(ctx *fiber.Ctx) error {
 	}
 
 	person, errPerson := n.personService.GetPersonById(idInt)
-	if errPerson.Code != "" {
+	if errPerson.IsError() {
 		response = model.Response{
 			Message: errPerson.Error(),
 		}
@@ -534,7 +534,7 @@ func (n *PersonController) UploadCurriculum(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusNotFound).JSON(response)
 	}
 
-	if err := n.personService.UploadCurriculum(*file, idInt); err.Code != "" {
+	if err := n.personService.UploadCurriculum(*file, idInt); err.IsError() {
 		response = model.Response{
 			Message: err.Error(),
 			Code:    err.GetCode(),
@@ -582,11 +582,11 @@ func (c *PersonController) validatePerson(personRequest model.PersonRequest) uti
 	fieldsWithErrors := []model.Field{}
 
 	if len(personRequest.Cpf) != 11 {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "cpf", Value: "cpf must have 11 digits"})
+		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "cpf", Message: "cpf must have 11 digits"})
 	}
 
 	person, err := c.personService.GetPersonByCpf(personRequest.Cpf)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -595,15 +595,15 @@ func (c *PersonController) validatePerson(personRequest model.PersonRequest) uti
 	}
 
 	if len(personRequest.Phone) != 13 {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "phone", Value: "phone must have 13 digits"})
+		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "phone", Message: "phone must have 13 digits"})
 	}
 
 	if !personRequest.Gender.IsValid() {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "gender", Value: "gender is not valid"})
+		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "gender", Message: "gender is not valid"})
 	}
 
 	user, err := c.personService.GetUserByEmail(personRequest.User.Email)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -623,7 +623,7 @@ func (c *PersonController) validatePerson(personRequest model.PersonRequest) uti
 func (n *PersonController) validatePersonDisabilities(disabiliesRequest []model.PersonDisabilityRequest) utils.Error {
 	for _, disabilityRequest := range disabiliesRequest {
 		disability, err := n.personService.GetDisabilityById(disabilityRequest.Id)
-		if err.Code != "" {
+		if err.IsError() {
 			return err
 		}
 
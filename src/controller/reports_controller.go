@@ -39,7 +39,7 @@ func (c *ReportsController) GetDisabilityTotals(ctx *fiber.Ctx) error {
 	var response model.Response
 
 	disabilityTotals, err := c.reportsService.GetDisabilityTotals()
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
@@ -89,7 +89,7 @@ func (c *ReportsController) GetDisabilityTotalsByNeighborhood(ctx *fiber.Ctx) er
 	}
 
 	disabilityTotals, reportErr := c.reportsService.GetDisabilityTotalsByNeighborhood(neighborhood)
-	if reportErr.Code != "" {
+	if reportErr.IsError() {
 		response = model.Response{
 			Message: reportErr.Message,
 			Code:    reportErr.Code,
@@ -132,7 +132,7 @@ func (c *ReportsController) CountActivitiesByPeriod(ctx *fiber.Ctx) error {
 	}
 
 	countActivitiesByPeriod, err := c.reportsService.CountActivitiesByPeriod(activityType, period)
-	if err.Code != "" {
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"bytes"
+	"cij_api/src/config"
+	"cij_api/src/utils"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// strictJson is read once, it rejects the json fields the request doesn't declare
+var strictJson = sync.OnceValue(func() bool {
+	requestConfig, err := config.LoadRequestConfig(".")
+
+	return err == nil && requestConfig.StrictJson
+})
+
+// parseBody decodes the json body into the request, reporting malformed json and,
+// in strict mode, unknown fields with their own codes. Other content types keep the
+// fiber body parser
+func parseBody(ctx *fiber.Ctx, request any) utils.Error {
+	if !ctx.Is("json") {
+		if err := ctx.BodyParser(request); err != nil {
+			errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.RequestErrorType, "01")
+
+			return utils.NewError("failed to parse the request body", errorCode)
+		}
+
+		return utils.Error{}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(ctx.Body()))
+	if strictJson() {
+		decoder.DisallowUnknownFields()
+	}
+
+	err := decoder.Decode(request)
+	if err == nil {
+		return utils.Error{}
+	}
+
+	// the decoder has no typed error for unknown fields
+	if field, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.RequestErrorType, "02")
+
+		return utils.NewError("unknown field "+field+" in the request body", errorCode)
+	}
+
+	message := "the request body is not valid json"
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		message = "invalid type for the field " + typeErr.Field + " in the request body"
+	}
+
+	errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.RequestErrorType, "01")
+
+	return utils.NewError(message, errorCode)
+}
@@ -0,0 +1,307 @@
+package controller
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/middleware"
+	"cij_api/src/model"
+	"cij_api/src/service"
+	"cij_api/src/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserController struct {
+	userService service.UserService
+}
+
+func NewUserController(userService service.UserService) *UserController {
+	return &UserController{
+		userService: userService,
+	}
+}
+
+// RequestPasswordReset
+// @Summary Request a password reset.
+// @Description generate a single use password reset token for the email. The response is the same whether or not the email is registered.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body model.PasswordResetRequest true "Email"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Router /password-reset [post]
+func (c *UserController) RequestPasswordReset(ctx *fiber.Ctx) error {
+	var request model.PasswordResetRequest
+	var response model.Response
+
+	if err := ctx.BodyParser(&request); err != nil || request.Email == "" {
+		response = model.Response{
+			Message: "email is required",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	if _, err := c.userService.RequestPasswordReset(request.Email); err.IsError() {
+		response = model.Response{
+			Message: err.Error(),
+			Code:    err.Code,
+		}
+
+		return ctx.Status(http.StatusInternalServerError).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "if the email is registered, a password reset was requested",
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// ResetPassword
+// @Summary Reset a password.
+// @Description set a new password using a password reset token.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body model.PasswordResetConfirmRequest true "Token and new password"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Router /password-reset/confirm [post]
+func (c *UserController) ResetPassword(ctx *fiber.Ctx) error {
+	var request model.PasswordResetConfirmRequest
+	var response model.Response
+
+	if err := ctx.BodyParser(&request); err != nil {
+		response = model.Response{
+			Message: "failed to parse the request body",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	if err := c.userService.ResetPassword(request.Token, request.Password); err.IsError() {
+		response = model.Response{
+			Message: err.Error(),
+			Code:    err.Code,
+		}
+
+		switch err.Message {
+		case "failed to get the password reset token", "failed to encrypt the password", "failed to reset the password":
+			return ctx.Status(http.StatusInternalServerError).JSON(response)
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "password reset successfully",
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// ListUsers
+// @Summary List users.
+// @Description list the users page by page, optionally filtering by role and by a part of the email.
+// @Tags Users
+// @Produce json
+// @Param page query int false "Page"
+// @Param per_page query int false "Per page"
+// @Param role query string false "Role (admin, company or person)"
+// @Param email query string false "Part of the email"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Router /users [get]
+func (c *UserController) ListUsers(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+
+	filters := model.UserFilters{
+		Role:  enum.UserRole(ctx.Query("role")),
+		Email: ctx.Query("email"),
+	}
+
+	users, err := c.userService.ListUsers(filters, page, perPage)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Error(),
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "users listed successfully",
+		Data:    users,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// Me
+// @Summary Get the authenticated user.
+// @Description get the profile of the user of the token.
+// @Tags Users
+// @Produce json
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 401 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /users/me [get]
+func (c *UserController) Me(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	user, err := c.userService.Me(ctx.UserContext())
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Error(),
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "user retrieved successfully",
+		Data:    user,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// UpdateUser
+// @Summary Update a user.
+// @Description partially update the email and/or password of a user. Users can only update themselves unless they are admins.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body model.UserUpdateRequest true "Fields to update"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Failure 409 {object} model.Response
+// @Router /users/{id} [patch]
+func (c *UserController) UpdateUser(ctx *fiber.Ctx) error {
+	var request model.UserUpdateRequest
+	var response model.Response
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: "invalid user id",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	claims := middleware.Claims(ctx)
+	if claims.Id != id && claims.Role != string(enum.AdminRole) {
+		response = model.Response{
+			Message: "users can only update themselves",
+		}
+
+		return ctx.Status(http.StatusForbidden).JSON(response)
+	}
+
+	if err := ctx.BodyParser(&request); err != nil {
+		response = model.Response{
+			Message: "failed to parse the request body",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	user, updateErr := c.userService.UpdateUser(id, request)
+	if updateErr.IsError() {
+		response = model.Response{
+			Message: updateErr.Error(),
+			Code:    updateErr.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(updateErr)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "user updated successfully",
+		Data:    user,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// DeactivateUser
+// @Summary Deactivate a user.
+// @Description disable the account instead of deleting it. The user can't log in and the vacancies of their company are hidden from the listings until it is reactivated.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /users/{id}/deactivate [patch]
+func (c *UserController) DeactivateUser(ctx *fiber.Ctx) error {
+	return c.setUserActive(ctx, false)
+}
+
+// ReactivateUser
+// @Summary Reactivate a user.
+// @Description enable a deactivated account again, restoring the login and the vacancies of their company.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /users/{id}/reactivate [patch]
+func (c *UserController) ReactivateUser(ctx *fiber.Ctx) error {
+	return c.setUserActive(ctx, true)
+}
+
+func (c *UserController) setUserActive(ctx *fiber.Ctx, active bool) error {
+	var response model.Response
+
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: "invalid user id",
+		}
+
+		return ctx.Status(http.StatusBadRequest).JSON(response)
+	}
+
+	var updateErr utils.Error
+	var message string
+
+	if active {
+		updateErr = c.userService.ReactivateUser(id)
+		message = "user reactivated successfully"
+	} else {
+		updateErr = c.userService.DeactivateUser(id)
+		message = "user deactivated successfully"
+	}
+
+	if updateErr.IsError() {
+		response = model.Response{
+			Message: updateErr.Error(),
+			Code:    updateErr.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(updateErr)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: message,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
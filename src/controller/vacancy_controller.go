@@ -2,10 +2,16 @@ package controller
 
 import (
 	"cij_api/src/enum"
+	"cij_api/src/middleware"
 	"cij_api/src/model"
 	vacancy "cij_api/src/model/vacancy"
 	"cij_api/src/service"
+	"cij_api/src/utils"
+	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -29,15 +35,17 @@ func NewVacancyController(vacancyService service.VacancyService, companyService
 // @Accept json
 // @Produce json
 // @Param vacancy body vacancy.VacancyRequest true "Vacancy"
+// @Param Idempotency-Key header string false "Idempotency key to avoid duplicated vacancies"
 // @Success 201 {object} model.Response
 // @Router /vacancies [post]
 func (v *VacancyController) CreateVacancy(ctx *fiber.Ctx) error {
 	var vacancyRequest vacancy.VacancyRequest
 	var response model.Response
 
-	if err := ctx.BodyParser(&vacancyRequest); err != nil {
+	if err := parseBody(ctx, &vacancyRequest); err.IsError() {
 		response = model.Response{
-			Message: "failed to parse the request body",
+			Message: err.Message,
+			Code:    err.Code,
 		}
 
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
@@ -45,196 +53,1292 @@ func (v *VacancyController) CreateVacancy(ctx *fiber.Ctx) error {
 
 	if err := v.validateVacancy(vacancyRequest); err != nil {
 		response = model.Response{
-			Message: err.Error(),
+			Message: err.Error(),
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	vacancyId, err := v.vacancyService.WithContext(ctx.UserContext()).CreateVacancy(vacancyRequest, ctx.Get("Idempotency-Key"))
+	if utils.HttpStatus(err) == fiber.StatusConflict {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(fiber.StatusConflict).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy created successfully",
+		Data:    vacancyId,
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(response)
+}
+
+// vacancyViewer identifies the visitor for the view throttle by the user if logged in,
+// or by the ip and user agent otherwise
+func vacancyViewer(ctx *fiber.Ctx) string {
+	if claims := middleware.Claims(ctx); claims.Id != 0 {
+		return "user:" + strconv.Itoa(claims.Id)
+	}
+
+	return ctx.IP() + "|" + ctx.Get(fiber.HeaderUserAgent)
+}
+
+// vacancyCacheControl lets clients keep a copy of the vacancy but revalidate it on every
+// use, since the response may depend on the logged in candidate
+const vacancyCacheControl = "private, no-cache"
+
+// vacancyNotModified sets the cache validators of the vacancy and reports whether the
+// client copy is still current. If-None-Match takes precedence over If-Modified-Since
+func vacancyNotModified(ctx *fiber.Ctx, etag string, lastModified time.Time) bool {
+	ctx.Set(fiber.HeaderCacheControl, vacancyCacheControl)
+	ctx.Vary(fiber.HeaderAuthorization)
+
+	if etag != "" {
+		ctx.Set(fiber.HeaderETag, etag)
+	}
+
+	if !lastModified.IsZero() {
+		ctx.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if noneMatch := ctx.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, candidate := range strings.Split(noneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+			if candidate == "*" || (etag != "" && candidate == strings.TrimPrefix(etag, "W/")) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	modifiedSince, err := http.ParseTime(ctx.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(modifiedSince)
+}
+
+// GetVacancyBySlug
+// @Summary Get a vacancy by slug
+// @Description Get a vacancy by its slug, for shareable links
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param slug path string true "Slug"
+// @Success 200 {object} model.Response
+// @Router /vacancies/slug/{slug} [get]
+func (v *VacancyController) GetVacancyBySlug(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	vacancy, err := v.vacancyService.WithContext(ctx.UserContext()).GetVacancyBySlug(ctx.Params("slug"))
+	if utils.HttpStatus(err) == fiber.StatusNotFound {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusNotFound).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	v.vacancyService.WithContext(ctx.UserContext()).RecordVacancyView(vacancy.Id, vacancyViewer(ctx))
+
+	if vacancyNotModified(ctx, vacancy.ETag(), vacancy.UpdatedAt) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	response = model.Response{
+		Message: "vacancy retrieved successfully",
+		Data:    vacancy,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateVacancy
+// @Summary Update a vacancy
+// @Description Update a vacancy
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
+// @Param company_id query string false "Company ID"
+// @Param disability_id query string false "Disability ID"
+// @Param disability_category query string false "Disability categories separated by comma"
+// @Param accommodations query string false "Required accommodations separated by comma"
+// @Param area query string false "Area"
+// @Param city query string false "City"
+// @Param state query string false "State"
+// @Param contract_type query string false "Contract Type"
+// @Param work_mode query string false "Work mode: onsite, remote, hybrid"
+// @Param search_text query string false "Search in code, title, description, requirements and company name"
+// @Param salary_min query number false "Minimum Salary"
+// @Param salary_max query number false "Maximum Salary"
+// @Param posted_after query string false "Posted on or after the date (YYYY-MM-DD or RFC 3339)"
+// @Param posted_before query string false "Posted on or before the date (YYYY-MM-DD or RFC 3339)"
+// @Param lat query number false "Latitude of the radius filter center"
+// @Param lng query number false "Longitude of the radius filter center"
+// @Param radius_km query number false "Only vacancies within the radius, in km, of lat and lng"
+// @Param verified_only query bool false "Only vacancies of verified companies"
+// @Param sort_by query string false "Sort by: created_at, title, salary"
+// @Param sort_order query string false "Sort order: asc, desc"
+// @Success 200 {object} model.Response
+// @Router /vacancies [get]
+func (v *VacancyController) ListVacancies(ctx *fiber.Ctx) error {
+	return v.listVacancies(ctx, false, false)
+}
+
+// ListVacanciesAdmin
+// @Summary List vacancies for auditing
+// @Description List vacancies accepting the same filters as the public listing, optionally including deleted ones
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param include_deleted query bool false "Include deleted vacancies"
+// @Param include_expired query bool false "Include closed and expired vacancies"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /vacancies/admin [get]
+func (v *VacancyController) ListVacanciesAdmin(ctx *fiber.Ctx) error {
+	return v.listVacancies(ctx, ctx.QueryBool("include_deleted"), ctx.QueryBool("include_expired"))
+}
+
+func (v *VacancyController) listVacancies(ctx *fiber.Ctx, includeDeleted bool, includeExpired bool) error {
+	var response model.Response
+
+	pageInt, _ := strconv.Atoi(ctx.Query("page"))
+	perPageInt, _ := strconv.Atoi(ctx.Query("per_page"))
+
+	filters, filtersErr := vacancyFiltersFromQuery(ctx)
+	if filtersErr != nil {
+		response = model.Response{
+			Message: filtersErr.Error(),
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	filters.IncludeDeleted = includeDeleted
+	filters.IncludeExpired = includeExpired
+
+	vacancies, err := v.vacancyService.WithContext(ctx.UserContext()).ListVacancies(filters, pageInt, perPageInt)
+	if err.IsError() {
+		response := model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancies listed successfully",
+		Data:    vacancies,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ExportVacancies
+// @Summary Export vacancies as csv
+// @Description Export every vacancy matching the listing filters as a csv file, without pagination
+// @Tags Vacancies
+// @Produce text/csv
+// @Param company_id query string false "Company ID"
+// @Param disability_id query string false "Disability ID"
+// @Param disability_category query string false "Disability categories separated by comma"
+// @Param accommodations query string false "Required accommodations separated by comma"
+// @Param area query string false "Area"
+// @Param city query string false "City"
+// @Param state query string false "State"
+// @Param contract_type query string false "Contract Type"
+// @Param work_mode query string false "Work mode: onsite, remote, hybrid"
+// @Param search_text query string false "Search Text"
+// @Param include_deleted query bool false "Include deleted vacancies"
+// @Param include_expired query bool false "Include closed and expired vacancies"
+// @Param Authorization header string true "Token"
+// @Success 200 {file} file
+// @Router /vacancies/export [get]
+func (v *VacancyController) ExportVacancies(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	filters, filtersErr := vacancyFiltersFromQuery(ctx)
+	if filtersErr != nil {
+		response = model.Response{
+			Message: filtersErr.Error(),
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	filters.IncludeDeleted = ctx.QueryBool("include_deleted")
+	filters.IncludeExpired = ctx.QueryBool("include_expired")
+
+	export, err := v.vacancyService.WithContext(ctx.UserContext()).ExportVacancies(filters)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/csv")
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="vacancies.csv"`)
+
+	return ctx.Status(fiber.StatusOK).SendStream(export)
+}
+
+func vacancyFiltersFromQuery(ctx *fiber.Ctx) (vacancy.VacancyFilters, error) {
+	companyId, disabilityId := ctx.Query("company_id"), ctx.Query("disability_id")
+	area, contractType, searchText, candidateId := ctx.Query("area"), ctx.Query("contract_type"), ctx.Query("search_text"), ctx.Query("candidate_id")
+
+	companyIdInt, _ := strconv.Atoi(companyId)
+	disabilityIdInt, _ := strconv.Atoi(disabilityId)
+	candidateIdInt, _ := strconv.Atoi(candidateId)
+
+	var disabilityCategories []string
+	for _, category := range strings.Split(ctx.Query("disability_category"), ",") {
+		category = strings.TrimSpace(category)
+		if category != "" && !slices.Contains(disabilityCategories, category) {
+			disabilityCategories = append(disabilityCategories, category)
+		}
+	}
+
+	accommodations := vacancy.NormalizeAccommodations(strings.Split(ctx.Query("accommodations"), ","))
+
+	workMode := enum.VacancyWorkMode(ctx.Query("work_mode"))
+	if workMode != "" && !workMode.IsValid() {
+		return vacancy.VacancyFilters{}, fiber.NewError(fiber.StatusBadRequest, "invalid work mode. valid values are: 'onsite', 'remote', 'hybrid'")
+	}
+
+	sortBy := enum.VacancySortBy(ctx.Query("sort_by", string(enum.VacancySortByCreatedAt)))
+	if !sortBy.IsValid() {
+		return vacancy.VacancyFilters{}, fiber.NewError(fiber.StatusBadRequest, "invalid sort by. valid values are: 'created_at', 'title', 'salary'")
+	}
+
+	sortOrder := enum.SortOrderEnum(ctx.Query("sort_order", string(enum.Desc)))
+	if !sortOrder.IsValid() {
+		return vacancy.VacancyFilters{}, fiber.NewError(fiber.StatusBadRequest, "invalid sort order. valid values are: 'asc', 'desc'")
+	}
+
+	var salaryMin, salaryMax *float64
+
+	if value, err := strconv.ParseFloat(ctx.Query("salary_min"), 64); err == nil {
+		salaryMin = &value
+	}
+
+	if value, err := strconv.ParseFloat(ctx.Query("salary_max"), 64); err == nil {
+		salaryMax = &value
+	}
+
+	postedAfter, err := parseQueryDate(ctx.Query("posted_after"), false)
+	if err != nil {
+		return vacancy.VacancyFilters{}, fiber.NewError(fiber.StatusBadRequest, "invalid posted after. expected format is 'YYYY-MM-DD' or RFC 3339")
+	}
+
+	postedBefore, err := parseQueryDate(ctx.Query("posted_before"), true)
+	if err != nil {
+		return vacancy.VacancyFilters{}, fiber.NewError(fiber.StatusBadRequest, "invalid posted before. expected format is 'YYYY-MM-DD' or RFC 3339")
+	}
+
+	latitude, longitude, radiusKm, err := radiusFromQuery(ctx)
+	if err != nil {
+		return vacancy.VacancyFilters{}, err
+	}
+
+	filters := vacancy.VacancyFilters{
+		CompanyId:            companyIdInt,
+		DisabilityId:         disabilityIdInt,
+		DisabilityCategories: disabilityCategories,
+		Accommodations:       accommodations,
+		CandidateId:          candidateIdInt,
+		Area:                 area,
+		City:                 ctx.Query("city"),
+		State:                ctx.Query("state"),
+		ContractType:         enum.VacancyContractType(contractType),
+		WorkMode:             workMode,
+		SearchText:           searchText,
+		SalaryMin:            salaryMin,
+		SalaryMax:            salaryMax,
+		SortBy:               sortBy,
+		SortOrder:            sortOrder,
+		PostedAfter:          postedAfter,
+		PostedBefore:         postedBefore,
+		Latitude:             latitude,
+		Longitude:            longitude,
+		RadiusKm:             radiusKm,
+		VerifiedOnly:         ctx.QueryBool("verified_only"),
+	}
+
+	return filters, nil
+}
+
+// radiusFromQuery reads the optional lat, lng and radius_km filter, the point is
+// required once a radius is set
+func radiusFromQuery(ctx *fiber.Ctx) (*float64, *float64, float64, error) {
+	if ctx.Query("radius_km") == "" {
+		return nil, nil, 0, nil
+	}
+
+	radiusKm, err := strconv.ParseFloat(ctx.Query("radius_km"), 64)
+	if err != nil || radiusKm <= 0 {
+		return nil, nil, 0, fiber.NewError(fiber.StatusBadRequest, "invalid radius km. expected a positive number")
+	}
+
+	latitude, err := strconv.ParseFloat(ctx.Query("lat"), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil, nil, 0, fiber.NewError(fiber.StatusBadRequest, "invalid lat. expected a number between -90 and 90")
+	}
+
+	longitude, err := strconv.ParseFloat(ctx.Query("lng"), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil, nil, 0, fiber.NewError(fiber.StatusBadRequest, "invalid lng. expected a number between -180 and 180")
+	}
+
+	return &latitude, &longitude, radiusKm, nil
+}
+
+// parseQueryDate accepts a date or a RFC 3339 timestamp. A date used as the end of a range
+// covers the whole day
+func parseQueryDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		if endOfDay {
+			date = date.Add(24*time.Hour - time.Nanosecond)
+		}
+
+		return &date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
+
+// GetVacancyById
+// @Summary Get a vacancy by ID
+// @Description Get a vacancy by ID. Supports conditional requests through ETag and Last-Modified
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param If-None-Match header string false "ETag of a previously retrieved copy"
+// @Success 200 {object} model.Response
+// @Success 304 "Not Modified"
+// @Router /vacancies/{id} [get]
+func (v *VacancyController) GetVacancyById(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	id, _ := strconv.Atoi(ctx.Params("id"))
+	candidateId, _ := strconv.Atoi(ctx.Query("candidate_id"))
+
+	vacancy, err := v.vacancyService.WithContext(ctx.UserContext()).GetVacancyById(id, candidateId)
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	v.vacancyService.WithContext(ctx.UserContext()).RecordVacancyView(vacancy.Id, vacancyViewer(ctx))
+
+	if vacancyNotModified(ctx, vacancy.ETag(), vacancy.UpdatedAt) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	response = model.Response{
+		Message: "vacancy retrieved successfully",
+		Data:    vacancy,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateVacancy
+// @Summary Update a vacancy
+// @Description Update a vacancy
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param vacancy body vacancy.VacancyRequest true "Vacancy"
+// @Success 200 {object} model.Response
+// @Failure 409 {object} model.Response
+// @Router /vacancies/{id} [put]
+func (v *VacancyController) UpdateVacancy(ctx *fiber.Ctx) error {
+	var vacancyRequest vacancy.VacancyRequest
+	var response model.Response
+
+	vacancyId := ctx.Params("id")
+	vacancyIdInt, _ := strconv.Atoi(vacancyId)
+
+	if err := parseBody(ctx, &vacancyRequest); err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	if err := v.validateVacancy(vacancyRequest); err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).UpdateVacancy(vacancyRequest, vacancyIdInt, middleware.Claims(ctx))
+	if status := utils.HttpStatus(err); status == fiber.StatusNotFound || status == fiber.StatusForbidden || status == fiber.StatusConflict {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(status).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy updated successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteVacancy
+// @Summary Delete a vacancy
+// @Description Delete a vacancy
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} model.Response
+// @Router /vacancies/{id} [delete]
+func (v *VacancyController) DeleteVacancy(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	vacancyId := ctx.Params("id")
+	vacancyIdInt, _ := strconv.Atoi(vacancyId)
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).DeleteVacancy(vacancyIdInt, middleware.Claims(ctx))
+	if status := utils.HttpStatus(err); status == fiber.StatusNotFound || status == fiber.StatusForbidden {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(status).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy deleted successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// CloseVacancy
+// @Summary Close a vacancy
+// @Description Close a vacancy so it no longer accepts applies, regardless of its expiration date
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /vacancies/{id}/close [patch]
+func (v *VacancyController) CloseVacancy(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	vacancyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).CloseVacancy(vacancyId, middleware.Claims(ctx))
+	if status := utils.HttpStatus(err); status == fiber.StatusNotFound || status == fiber.StatusForbidden || status == fiber.StatusConflict {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(status).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy closed successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// PublishVacancy
+// @Summary Publish a draft vacancy
+// @Description Publish a draft vacancy so it shows up in the public listings
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Failure 409 {object} model.Response
+// @Router /vacancies/{id}/publish [patch]
+func (v *VacancyController) PublishVacancy(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	vacancyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).PublishVacancy(vacancyId, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy published successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// DuplicateVacancy
+// @Summary Duplicate a vacancy as a draft
+// @Description Copy the vacancy with its skills, requirements, responsabilities, accommodations and disabilities into a new draft of the same company
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 201 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /vacancies/{id}/duplicate [post]
+func (v *VacancyController) DuplicateVacancy(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	vacancyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	duplicateId, err := v.vacancyService.WithContext(ctx.UserContext()).DuplicateVacancy(vacancyId, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy duplicated successfully",
+		Data:    duplicateId,
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(response)
+}
+
+// FeatureVacancy
+// @Summary Feature a vacancy
+// @Description Set or unset the featured flag of the vacancy, listing it first until the featured until passes. Only admins can feature vacancies
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param request body vacancy.VacancyFeatureRequest true "Featured flag"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /vacancies/{id}/featured [patch]
+func (v *VacancyController) FeatureVacancy(ctx *fiber.Ctx) error {
+	var request vacancy.VacancyFeatureRequest
+	var response model.Response
+
+	vacancyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	if err := parseBody(ctx, &request); err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).FeatureVacancy(vacancyId, request)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy featured updated successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// TransferVacancies
+// @Summary Transfer the vacancies of a company
+// @Description Reassign every vacancy of the company to another one, for merged or corrected company accounts
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param request body vacancy.VacancyTransferRequest true "Company receiving the vacancies"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /vacancies/companies/{id}/transfer [post]
+func (v *VacancyController) TransferVacancies(ctx *fiber.Ctx) error {
+	var request vacancy.VacancyTransferRequest
+	var response model.Response
+
+	fromCompanyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	if err := ctx.BodyParser(&request); err != nil || request.ToCompanyId == 0 {
+		response = model.Response{
+			Message: "to_company_id is required",
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	transferred, err := v.vacancyService.WithContext(ctx.UserContext()).TransferVacancies(fromCompanyId, request.ToCompanyId)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancies transferred successfully",
+		Data:    map[string]int{"transferred": transferred},
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListCompaniesWithVacancies
+// @Summary List the companies with open vacancies
+// @Description List the companies having open vacancies for the company directory, each with its five newest open vacancies and their total
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
+// @Success 200 {object} model.Response
+// @Router /vacancies/companies [get]
+func (v *VacancyController) ListCompaniesWithVacancies(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+
+	companies, err := v.vacancyService.WithContext(ctx.UserContext()).ListCompaniesWithVacancies(page, perPage)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "companies listed successfully",
+		Data:    companies,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListCompanyVacancies
+// @Summary List the vacancies of a company
+// @Description List the vacancies of the company, including its drafts, accepting the same filters as the public listing
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
+// @Param include_expired query bool false "Include closed and expired vacancies"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Router /vacancies/companies/{id} [get]
+func (v *VacancyController) ListCompanyVacancies(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	companyId, _ := strconv.Atoi(ctx.Params("id"))
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+
+	filters, filtersErr := vacancyFiltersFromQuery(ctx)
+	if filtersErr != nil {
+		response = model.Response{
+			Message: filtersErr.Error(),
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	filters.IncludeExpired = ctx.QueryBool("include_expired")
+
+	vacancies, err := v.vacancyService.WithContext(ctx.UserContext()).ListCompanyVacancies(companyId, filters, page, perPage, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancies listed successfully",
+		Data:    vacancies,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ReopenVacancy
+// @Summary Reopen a vacancy
+// @Description Reopen a closed or expired vacancy, optionally with a new expiration date
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param vacancy body vacancy.VacancyReopenRequest false "New expiration date"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /vacancies/{id}/reopen [patch]
+func (v *VacancyController) ReopenVacancy(ctx *fiber.Ctx) error {
+	var response model.Response
+	var reopenRequest vacancy.VacancyReopenRequest
+
+	vacancyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&reopenRequest); err != nil {
+			response = model.Response{
+				Message: "failed to parse the request body",
+			}
+
+			return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		}
+	}
+
+	var newExpiresAt *time.Time
+	if reopenRequest.ExpiresAt != nil && *reopenRequest.ExpiresAt != "" {
+		expiresAt, parseErr := time.Parse("2006-01-02", *reopenRequest.ExpiresAt)
+		if parseErr != nil {
+			response = model.Response{
+				Message: "invalid expires at. expected format is 'YYYY-MM-DD'",
+			}
+
+			return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		}
+
+		newExpiresAt = &expiresAt
+	}
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).ReopenVacancy(vacancyId, newExpiresAt, middleware.Claims(ctx))
+	if status := utils.HttpStatus(err); status == fiber.StatusNotFound || status == fiber.StatusForbidden || status == fiber.StatusConflict {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(status).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy reopened successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ImportVacancies
+// @Summary Import vacancies from a csv file
+// @Description Create a vacancy for the company from each csv row, reporting the rows that failed. Expected columns are title, area, contract_type, disabilities (ids separated by semicolons), salary_min and salary_max
+// @Tags Vacancies
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param file formData file true "CSV file"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Failure 403 {object} model.Response
+// @Failure 404 {object} model.Response
+// @Router /vacancies/companies/{id}/import [post]
+func (v *VacancyController) ImportVacancies(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	companyId, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		response = model.Response{
+			Message: "invalid company id",
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	company, companyErr := v.companyService.GetCompanyById(companyId)
+	if companyErr.IsError() {
+		response = model.Response{
+			Message: companyErr.Message,
+			Code:    companyErr.Code,
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+
+	if company.Id == 0 {
+		response = model.Response{
+			Message: "company not found",
+		}
+
+		return ctx.Status(fiber.StatusNotFound).JSON(response)
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		response = model.Response{
+			Message: err.Error(),
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	openFile, err := file.Open()
+	if err != nil {
+		response = model.Response{
+			Message: "failed to open the file",
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	defer openFile.Close()
+
+	result, importErr := v.vacancyService.WithContext(ctx.UserContext()).ImportVacancies(companyId, openFile, middleware.Claims(ctx))
+	if importErr.IsError() {
+		response = model.Response{
+			Message: importErr.Message,
+			Code:    importErr.Code,
+			Data:    result,
+		}
+
+		return ctx.Status(utils.HttpStatus(importErr)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "success",
+		Data:    result,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// CountVacanciesByCompany
+// @Summary Count company vacancies
+// @Description Count the open vacancies of a company
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "Company ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /vacancies/stats/companies/{id} [get]
+func (v *VacancyController) CountVacanciesByCompany(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	companyId, _ := strconv.Atoi(ctx.Params("id"))
+
+	total, err := v.vacancyService.WithContext(ctx.UserContext()).CountVacanciesByCompany(companyId)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "company vacancies counted successfully",
+		Data:    total,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetVacanciesByIds
+// @Summary Get vacancies by IDs
+// @Description Get several vacancies in a single call, in the requested order. Missing ids are omitted
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param ids query string true "Vacancy ids separated by comma"
+// @Success 200 {object} model.Response
+// @Failure 400 {object} model.Response
+// @Router /vacancies/batch [get]
+func (v *VacancyController) GetVacanciesByIds(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	ids := []int{}
+	for _, value := range strings.Split(ctx.Query("ids"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		id, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			response = model.Response{
+				Message: "invalid vacancy id: " + value,
+			}
+
+			return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		}
+
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	vacancies, err := v.vacancyService.WithContext(ctx.UserContext()).GetVacanciesByIds(ids)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancies retrieved successfully",
+		Data:    vacancies,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// VacancyFacets
+// @Summary Count vacancies by contract type
+// @Description Count the vacancies matching the listing filters grouped by contract type, ignoring the contract type filter itself
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param company_id query string false "Company ID"
+// @Param disability_id query string false "Disability ID"
+// @Param disability_category query string false "Disability categories separated by comma"
+// @Param accommodations query string false "Required accommodations separated by comma"
+// @Param area query string false "Area"
+// @Param city query string false "City"
+// @Param state query string false "State"
+// @Param work_mode query string false "Work mode: onsite, remote, hybrid"
+// @Param search_text query string false "Search in code, title, description, requirements and company name"
+// @Param salary_min query number false "Minimum Salary"
+// @Param salary_max query number false "Maximum Salary"
+// @Success 200 {object} model.Response
+// @Router /vacancies/facets [get]
+func (v *VacancyController) VacancyFacets(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	filters, filtersErr := vacancyFiltersFromQuery(ctx)
+	if filtersErr != nil {
+		response = model.Response{
+			Message: filtersErr.Error(),
 		}
 
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
 
-	err := v.vacancyService.CreateVacancy(vacancyRequest)
-	if err.Code != "" {
+	facets, err := v.vacancyService.WithContext(ctx.UserContext()).VacancyFacets(filters)
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 
 	response = model.Response{
-		Message: "vacancy created successfully",
+		Message: "vacancy facets counted successfully",
+		Data:    facets,
 	}
 
-	return ctx.Status(fiber.StatusCreated).JSON(response)
+	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
-// UpdateVacancy
-// @Summary Update a vacancy
-// @Description Update a vacancy
+// ListVacancyAreas
+// @Summary List vacancy areas
+// @Description List the distinct areas of the open vacancies, sorted alphabetically
 // @Tags Vacancies
 // @Accept json
 // @Produce json
-// @Param page query string false "Page"
-// @Param per_page query string false "Per Page"
-// @Param company_id query string false "Company ID"
-// @Param disability query string false "Disability"
-// @Param area query string false "Area"
-// @Param contract_type query string false "Contract Type"
-// @Param search_text query string false "Search Text"
 // @Success 200 {object} model.Response
-// @Router /vacancies [get]
-func (v *VacancyController) ListVacancies(ctx *fiber.Ctx) error {
+// @Router /vacancies/areas [get]
+func (v *VacancyController) ListVacancyAreas(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	perPage, companyId, disabilityId := ctx.Query("per_page"), ctx.Query("company_id"), ctx.Query("disability_id")
-	area, contractType, searchText, candidateId := ctx.Query("area"), ctx.Query("contract_type"), ctx.Query("search_text"), ctx.Query("candidate_id")
-
-	perPageInt, _ := strconv.Atoi(perPage)
-	if perPageInt == 0 {
-		perPageInt = 10
-	}
-
-	companyIdInt, _ := strconv.Atoi(companyId)
-	disabilityIdInt, _ := strconv.Atoi(disabilityId)
-	candidateIdInt, _ := strconv.Atoi(candidateId)
-
-	vacancies, err := v.vacancyService.ListVacancies(perPageInt, companyIdInt, disabilityIdInt, candidateIdInt, area, enum.VacancyContractType(contractType), searchText)
-	if err.Code != "" {
-		response := model.Response{
+	areas, err := v.vacancyService.WithContext(ctx.UserContext()).ListVacancyAreas()
+	if err.IsError() {
+		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 
 	response = model.Response{
-		Message: "vacancies listed successfully",
-		Data:    vacancies,
+		Message: "vacancy areas listed successfully",
+		Data:    areas,
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
-// GetVacancyById
-// @Summary Get a vacancy by ID
-// @Description Get a vacancy by ID
+// ListVacancyCities
+// @Summary List vacancy cities
+// @Description List the distinct cities of the open vacancies, sorted alphabetically
 // @Tags Vacancies
 // @Accept json
 // @Produce json
-// @Param id path string true "ID"
 // @Success 200 {object} model.Response
-// @Router /vacancies/{id} [get]
-func (v *VacancyController) GetVacancyById(ctx *fiber.Ctx) error {
+// @Router /vacancies/cities [get]
+func (v *VacancyController) ListVacancyCities(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	id, _ := strconv.Atoi(ctx.Params("id"))
-	candidateId, _ := strconv.Atoi(ctx.Query("candidate_id"))
-
-	vacancy, err := v.vacancyService.GetVacancyById(id, candidateId)
-
-	if err.Message == "failed to get the vacancy" {
-		response = model.Response{
-			Message: err.Message,
-		}
-
-		return ctx.Status(fiber.StatusNotFound).JSON(response)
-	}
-
-	if err.Code != "" {
+	cities, err := v.vacancyService.WithContext(ctx.UserContext()).ListVacancyCities()
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 
 	response = model.Response{
-		Message: "vacancy retrieved successfully",
-		Data:    vacancy,
+		Message: "vacancy cities listed successfully",
+		Data:    cities,
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
-// UpdateVacancy
-// @Summary Update a vacancy
-// @Description Update a vacancy
+// ListPopularVacancies
+// @Summary List the popular vacancies
+// @Description List the open vacancies with the most applies in the recent window, each with its applies count
 // @Tags Vacancies
 // @Accept json
 // @Produce json
-// @Param id path string true "ID"
-// @Param vacancy body vacancy.VacancyRequest true "Vacancy"
+// @Param limit query string false "Limit"
 // @Success 200 {object} model.Response
-// @Router /vacancies/{id} [put]
-func (v *VacancyController) UpdateVacancy(ctx *fiber.Ctx) error {
-	var vacancyRequest vacancy.VacancyRequest
+// @Failure 400 {object} model.Response
+// @Router /vacancies/popular [get]
+func (v *VacancyController) ListPopularVacancies(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	vacancyId := ctx.Params("id")
-	vacancyIdInt, _ := strconv.Atoi(vacancyId)
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
 
-	if err := ctx.BodyParser(&vacancyRequest); err != nil {
+	vacancies, err := v.vacancyService.WithContext(ctx.UserContext()).ListPopularVacancies(limit)
+	if err.IsError() {
 		response = model.Response{
-			Message: "failed to parse the request body",
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
 	}
 
-	if err := v.validateVacancy(vacancyRequest); err != nil {
-		response = model.Response{
-			Message: err.Error(),
-		}
-
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	response = model.Response{
+		Message: "popular vacancies listed successfully",
+		Data:    vacancies,
 	}
 
-	err := v.vacancyService.UpdateVacancy(vacancyRequest, vacancyIdInt)
-	if err.Code != "" {
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// CountVacanciesGroupedByArea
+// @Summary Count vacancies by area
+// @Description Count the open vacancies grouped by area
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.Response
+// @Router /vacancies/stats/areas [get]
+func (v *VacancyController) CountVacanciesGroupedByArea(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	totals, err := v.vacancyService.WithContext(ctx.UserContext()).CountVacanciesGroupedByArea()
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 
 	response = model.Response{
-		Message: "vacancy updated successfully",
+		Message: "vacancies counted successfully",
+		Data:    totals,
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
-// DeleteVacancy
-// @Summary Delete a vacancy
-// @Description Delete a vacancy
+// VacancyStats
+// @Summary Vacancy stats over time
+// @Description Count the vacancies created and closed in the range, bucketed by day, week or month. The range defaults to the last 30 days
 // @Tags Vacancies
 // @Accept json
 // @Produce json
-// @Param id path string true "ID"
-// @Success 200 {object} model.Response
-// @Router /vacancies/{id} [delete]
-func (v *VacancyController) DeleteVacancy(ctx *fiber.Ctx) error {
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "End date (YYYY-MM-DD or RFC 3339), inclusive"
+// @Param interval query string false "Bucket size: day, week or month"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response{data=[]vacancy.VacancyStatsBucket}
+// @Failure 400 {object} model.Response
+// @Router /vacancies/stats/timeline [get]
+func (v *VacancyController) VacancyStats(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	vacancyId := ctx.Params("id")
-	vacancyIdInt, _ := strconv.Atoi(vacancyId)
+	from, fromErr := parseQueryDate(ctx.Query("from"), false)
+	to, toErr := parseQueryDate(ctx.Query("to"), true)
+	if fromErr != nil || toErr != nil {
+		response = model.Response{
+			Message: "invalid date. expected format is 'YYYY-MM-DD' or RFC 3339",
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	if to == nil {
+		now := time.Now()
+		to = &now
+	}
+
+	if from == nil {
+		start := to.AddDate(0, 0, -30)
+		from = &start
+	}
 
-	err := v.vacancyService.DeleteVacancy(vacancyIdInt)
-	if err.Code != "" {
+	stats, err := v.vacancyService.WithContext(ctx.UserContext()).VacancyStats(*from, *to, ctx.Query("interval", string(enum.VacancyStatsDay)))
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
+			Fields:  err.Fields,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
 	}
 
 	response = model.Response{
-		Message: "vacancy deleted successfully",
+		Message: "vacancy stats counted successfully",
+		Data:    stats,
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(response)
@@ -261,14 +1365,23 @@ func (v *VacancyController) CandidateApply(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
 
-	err := v.vacancyService.CandidateApplyVacancy(vacancyApplyRequest.CandidateId, vacancyApplyRequest.VacancyId)
-	if err.Code != "" {
+	err := v.vacancyService.WithContext(ctx.UserContext()).CandidateApplyVacancy(vacancyApplyRequest.CandidateId, vacancyApplyRequest.VacancyId)
+	if status := utils.HttpStatus(err); status == fiber.StatusNotFound || status == fiber.StatusConflict {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
 		}
 
-		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		return ctx.Status(status).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
 	}
 
 	response = model.Response{
@@ -280,19 +1393,26 @@ func (v *VacancyController) CandidateApply(ctx *fiber.Ctx) error {
 
 // ListVacancyApplies
 // @Summary List vacancy applies
-// @Description List vacancy applies
+// @Description List a page of the vacancy applies, newest first
 // @Tags VacancyApplies
 // @Accept json
 // @Produce json
 // @Param id path string true "ID"
+// @Param status query string false "Status: applied, under_review, interview, rejected, accepted, withdrawn"
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
 // @Success 200 {object} model.Response
 // @Router /vacancies/apply/{id} [get]
 func (v *VacancyController) ListVacancyApplies(ctx *fiber.Ctx) error {
 	var response model.Response
 
 	vacancyId, _ := strconv.Atoi(ctx.Params("id"))
-	vacancyApplies, err := v.vacancyService.GetVacancyAppliesByVacancyId(vacancyId)
-	if err.Code != "" {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+	status := enum.VacancyApplyStatus(ctx.Query("status"))
+
+	vacancyApplies, err := v.vacancyService.WithContext(ctx.UserContext()).GetVacancyAppliesByVacancyId(vacancyId, status, page, perPage)
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
@@ -309,6 +1429,123 @@ func (v *VacancyController) ListVacancyApplies(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
+// ListCandidateApplies
+// @Summary List candidate applies
+// @Description List the vacancies a candidate applied to
+// @Tags VacancyApplies
+// @Accept json
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Success 200 {object} model.Response
+// @Router /vacancies/apply/candidate/{id} [get]
+func (v *VacancyController) ListCandidateApplies(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	candidateId, _ := strconv.Atoi(ctx.Params("id"))
+	candidateApplies, err := v.vacancyService.WithContext(ctx.UserContext()).GetVacancyAppliesByCandidateId(candidateId)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "candidate applies listed successfully",
+		Data:    candidateApplies,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListApplicationsByCandidate
+// @Summary List the application history of a candidate
+// @Description List a page of the applications of the candidate with the vacancy title, company name, status and applied date, most recently applied first
+// @Tags VacancyApplies
+// @Accept json
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param status query string false "Status: applied, under_review, interview, rejected, accepted, withdrawn"
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /vacancies/applications/candidate/{id} [get]
+func (v *VacancyController) ListApplicationsByCandidate(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	candidateId, _ := strconv.Atoi(ctx.Params("id"))
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+	status := enum.VacancyApplyStatus(ctx.Query("status"))
+
+	applications, err := v.vacancyService.WithContext(ctx.UserContext()).ListApplicationsByCandidate(candidateId, status, page, perPage)
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "candidate applications listed successfully",
+		Data:    applications,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListVacanciesForCandidate
+// @Summary List vacancies for a candidate
+// @Description List the open vacancies covering a disability category of the candidate
+// @Tags Vacancies
+// @Accept json
+// @Produce json
+// @Param id path string true "Candidate ID"
+// @Param page query string false "Page"
+// @Param per_page query string false "Per Page"
+// @Param exclude_applied query bool false "Hide the vacancies the candidate already applied to"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /vacancies/candidate/{id}/eligible [get]
+func (v *VacancyController) ListVacanciesForCandidate(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	candidateId, _ := strconv.Atoi(ctx.Params("id"))
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	perPage, _ := strconv.Atoi(ctx.Query("per_page"))
+
+	vacancies, err := v.vacancyService.WithContext(ctx.UserContext()).ListVacanciesForCandidate(candidateId, page, perPage, ctx.QueryBool("exclude_applied"))
+	if utils.HttpStatus(err) == fiber.StatusNotFound {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusNotFound).JSON(response)
+	}
+
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancies listed successfully",
+		Data:    vacancies,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
 // UpdateVacancyApplyStatus
 // @Summary Update vacancy apply status
 // @Description Update vacancy apply status
@@ -327,14 +1564,23 @@ func (v *VacancyController) UpdateVacancyApplyStatus(ctx *fiber.Ctx) error {
 
 	if !enum.VacancyApplyStatus(status).IsValid() {
 		response = model.Response{
-			Message: "invalid status. valid values are: 'applied', 'approved', 'rejected'",
+			Message: "invalid status. valid values are: 'applied', 'under_review', 'interview', 'rejected', 'accepted'",
 		}
 
 		return ctx.Status(fiber.StatusBadRequest).JSON(response)
 	}
 
-	err := v.vacancyService.UpdateVacancyApplyStatus(vacancyApplyId, enum.VacancyApplyStatus(status))
-	if err.Code != "" {
+	err := v.vacancyService.WithContext(ctx.UserContext()).UpdateVacancyApplyStatus(vacancyApplyId, enum.VacancyApplyStatus(status))
+	if httpStatus := utils.HttpStatus(err); httpStatus == fiber.StatusNotFound || httpStatus == fiber.StatusConflict {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(httpStatus).JSON(response)
+	}
+
+	if err.IsError() {
 		response = model.Response{
 			Message: err.Message,
 			Code:    err.Code,
@@ -350,6 +1596,89 @@ func (v *VacancyController) UpdateVacancyApplyStatus(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(response)
 }
 
+// BulkUpdateApplicationStatus
+// @Summary Update the status of several vacancy applies
+// @Description Move every vacancy apply to the status, reporting which ones were updated and why each of the others failed
+// @Tags VacancyApplies
+// @Accept json
+// @Produce json
+// @Param request body vacancy.VacancyApplyBulkStatusRequest true "Applies and status"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response{data=vacancy.VacancyApplyBulkStatusResult}
+// @Failure 400 {object} model.Response
+// @Router /vacancies/apply/bulk-status [patch]
+func (v *VacancyController) BulkUpdateApplicationStatus(ctx *fiber.Ctx) error {
+	var request vacancy.VacancyApplyBulkStatusRequest
+	var response model.Response
+
+	if err := parseBody(ctx, &request); err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	if !request.Status.IsValid() {
+		response = model.Response{
+			Message: "invalid status. valid values are: 'applied', 'under_review', 'interview', 'rejected', 'accepted'",
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	result, err := v.vacancyService.WithContext(ctx.UserContext()).BulkUpdateApplicationStatus(request.Ids, request.Status, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+			Fields:  err.Fields,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy applies status updated",
+		Data:    result,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// WithdrawApplication
+// @Summary Withdraw a vacancy apply
+// @Description Withdraw an apply of the logged in candidate that isn't rejected, accepted or withdrawn yet
+// @Tags VacancyApplies
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param Authorization header string true "Token"
+// @Success 200 {object} model.Response
+// @Router /vacancies/apply/{id}/withdraw [patch]
+func (v *VacancyController) WithdrawApplication(ctx *fiber.Ctx) error {
+	var response model.Response
+
+	applicationId, _ := strconv.Atoi(ctx.Params("id"))
+
+	err := v.vacancyService.WithContext(ctx.UserContext()).WithdrawApplication(applicationId, middleware.Claims(ctx))
+	if err.IsError() {
+		response = model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(utils.HttpStatus(err)).JSON(response)
+	}
+
+	response = model.Response{
+		Message: "vacancy apply withdrawn successfully",
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
 func (v *VacancyController) validateVacancy(vacancyRequest vacancy.VacancyRequest) error {
 	if vacancyRequest.Code == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "code is required")
@@ -425,12 +1754,30 @@ func (v *VacancyController) validateVacancy(vacancyRequest vacancy.VacancyReques
 		return fiber.NewError(fiber.StatusBadRequest, "invalid contract type. valid values are: 'clt', 'pj', 'trainee'")
 	}
 
+	if vacancyRequest.WorkMode != "" && !vacancyRequest.WorkMode.IsValid() {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid work mode. valid values are: 'onsite', 'remote', 'hybrid'")
+	}
+
+	if vacancyRequest.SalaryMin != nil && *vacancyRequest.SalaryMin < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "salary min must not be negative")
+	}
+
+	if vacancyRequest.SalaryMin != nil && vacancyRequest.SalaryMax != nil && *vacancyRequest.SalaryMin > *vacancyRequest.SalaryMax {
+		return fiber.NewError(fiber.StatusBadRequest, "salary min must not be greater than salary max")
+	}
+
+	if vacancyRequest.ExpiresAt != nil && *vacancyRequest.ExpiresAt != "" {
+		if _, err := time.Parse("2006-01-02", *vacancyRequest.ExpiresAt); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid expiration date. expected format is 'YYYY-MM-DD'")
+		}
+	}
+
 	if vacancyRequest.CompanyId == 0 {
 		return fiber.NewError(fiber.StatusBadRequest, "company ID is required")
 	}
 
 	company, err := v.companyService.GetCompanyById(vacancyRequest.CompanyId)
-	if err.Code != "" {
+	if err.IsError() {
 		return fiber.NewError(fiber.StatusBadRequest, "failed to get the company")
 	}
 
@@ -0,0 +1,228 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaMigration records a migration version already applied to the database
+type schemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// RunMigrations applies, in version order, the migrations in src/database/migrations
+// that aren't recorded in the schema_migrations table yet. The files follow the
+// golang-migrate naming, <version>_<name>.up.sql and <version>_<name>.down.sql
+func RunMigrations(client *gorm.DB) error {
+	migrations, applied, err := loadMigrationState(client)
+	if err != nil {
+		return err
+	}
+
+	for _, pending := range migrations {
+		if applied[pending.version] {
+			continue
+		}
+
+		err := client.Transaction(func(tx *gorm.DB) error {
+			if err := execMigrationSql(tx, pending.up); err != nil {
+				return err
+			}
+
+			return tx.Create(&schemaMigration{
+				Version:   pending.version,
+				Name:      pending.name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %06d_%s: %w", pending.version, pending.name, err)
+		}
+
+		log.Printf("applied migration %06d_%s", pending.version, pending.name)
+	}
+
+	return nil
+}
+
+// RollbackMigration reverts the latest applied migration with its down file
+func RollbackMigration(client *gorm.DB) error {
+	migrations, applied, err := loadMigrationState(client)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		latest := migrations[i]
+		if !applied[latest.version] {
+			continue
+		}
+
+		err := client.Transaction(func(tx *gorm.DB) error {
+			if err := execMigrationSql(tx, latest.down); err != nil {
+				return err
+			}
+
+			return tx.Delete(&schemaMigration{}, latest.version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revert migration %06d_%s: %w", latest.version, latest.name, err)
+		}
+
+		log.Printf("reverted migration %06d_%s", latest.version, latest.name)
+
+		return nil
+	}
+
+	return nil
+}
+
+// CurrentMigrationVersion is the latest applied migration version, zero when none is
+func CurrentMigrationVersion(client *gorm.DB) (int, error) {
+	var version int
+
+	err := client.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func loadMigrationState(client *gorm.DB) ([]migration, map[int]bool, error) {
+	if err := client.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to create the schema_migrations table: %w", err)
+	}
+
+	migrations, err := readMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var versions []int
+	if err := client.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+
+	applied := make(map[int]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+
+	return migrations, applied, nil
+}
+
+func readMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the migration files: %w", err)
+	}
+
+	byVersion := map[int]*migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		direction := ""
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		baseName := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionText, name, found := strings.Cut(baseName, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name %s", fileName)
+		}
+
+		version, err := strconv.Atoi(versionText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s", fileName)
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the migration %s: %w", fileName, err)
+		}
+
+		current, ok := byVersion[version]
+		if !ok {
+			current = &migration{version: version, name: name}
+			byVersion[version] = current
+		}
+
+		if direction == "up" {
+			current.up = string(content)
+		} else {
+			current.down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, parsed := range byVersion {
+		migrations = append(migrations, *parsed)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// execMigrationSql runs each statement of a migration file on its own, since the
+// mysql driver doesn't accept multiple statements in one call. Statements end with
+// a semicolon at the end of the line and comment lines are ignored
+func execMigrationSql(tx *gorm.DB, content string) error {
+	var statement strings.Builder
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			if err := tx.Exec(statement.String()).Error; err != nil {
+				return err
+			}
+			statement.Reset()
+		}
+	}
+
+	if strings.TrimSpace(statement.String()) != "" {
+		return tx.Exec(statement.String()).Error
+	}
+
+	return nil
+}
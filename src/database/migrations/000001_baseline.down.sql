@@ -0,0 +1 @@
+-- The baseline tables are owned by AutoMigrate and are never dropped here.
@@ -0,0 +1,3 @@
+-- Baseline: the schema at this version is the one created by the gorm models
+-- through AutoMigrate (see migrateDb in main.go), so there is nothing to apply.
+-- Column changes, renames and data fixes go in the next versioned files.
@@ -0,0 +1 @@
+-- The original casing of the emails isn't kept, so there is nothing to revert.
@@ -0,0 +1,4 @@
+-- The lookups compare the emails as stored, so the emails registered before they were
+-- normalized on write are trimmed and lowercased. BINARY makes the check case sensitive
+-- under the default collation.
+UPDATE users SET email = LOWER(TRIM(email)) WHERE BINARY email <> LOWER(TRIM(email));
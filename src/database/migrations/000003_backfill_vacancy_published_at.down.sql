@@ -0,0 +1 @@
+-- The column belongs to the gorm model, so reverting leaves the backfilled dates in place.
@@ -0,0 +1,3 @@
+-- The job alerts look for the vacancies published since their last run. The vacancies
+-- published before published_at existed are taken as published when they were created.
+UPDATE vacancies SET published_at = created_at WHERE status <> 'draft' AND published_at IS NULL;
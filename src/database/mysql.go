@@ -4,6 +4,7 @@ import (
 	"cij_api/src/config"
 	"fmt"
 	"log"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -11,7 +12,9 @@ import (
 
 func ConnectionDB(config *config.Config) *gorm.DB {
 	dsn := config.DbConnection
-	client, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	client, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		TranslateError: true,
+	})
 
 	if err != nil {
 		panic("failed to connect database")
@@ -27,6 +30,7 @@ func ConnectionDB(config *config.Config) *gorm.DB {
 		panic("failed to enter database cij")
 	}
 
+	configureConnectionPool(client)
 	createFunctionToNormalizeText(client)
 
 	fmt.Print("Database connected\n\n")
@@ -34,6 +38,36 @@ func ConnectionDB(config *config.Config) *gorm.DB {
 	return client
 }
 
+// configureConnectionPool applies the pool limits to the underlying sql.DB, so the
+// load can't exhaust the mysql connections
+func configureConnectionPool(client *gorm.DB) {
+	maxOpenConns, maxIdleConns, connMaxLifetime := 25, 10, 5*time.Minute
+
+	databaseConfig, err := config.LoadDatabaseConfig(".")
+	if err == nil {
+		if databaseConfig.MaxOpenConns > 0 {
+			maxOpenConns = databaseConfig.MaxOpenConns
+		}
+
+		if databaseConfig.MaxIdleConns > 0 {
+			maxIdleConns = databaseConfig.MaxIdleConns
+		}
+
+		if databaseConfig.ConnMaxLifetime > 0 {
+			connMaxLifetime = databaseConfig.ConnMaxLifetime
+		}
+	}
+
+	sqlDb, err := client.DB()
+	if err != nil {
+		panic("failed to get the database connection pool")
+	}
+
+	sqlDb.SetMaxOpenConns(maxOpenConns)
+	sqlDb.SetMaxIdleConns(maxIdleConns)
+	sqlDb.SetConnMaxLifetime(connMaxLifetime)
+}
+
 func createFunctionToNormalizeText(client *gorm.DB) {
 	var count int
 	err := client.Raw(`
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/model"
+	vacancy "cij_api/src/model/vacancy"
+	"cij_api/src/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const demoCompanyPassword = "Demo@1234"
+
+type demoCompany struct {
+	company   model.Company
+	email     string
+	address   model.Address
+	vacancies []vacancy.Vacancy
+}
+
+// SeedDemoData creates a few demo companies with open vacancies. Companies and
+// vacancies already present are skipped, so it can run more than once
+func SeedDemoData(db *gorm.DB) error {
+	for _, demo := range demoCompanies() {
+		if err := createDemoCompany(db, demo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createDemoCompany(db *gorm.DB, demo demoCompany) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var company model.Company
+		err := tx.Where("cnpj = ?", demo.company.Cnpj).First(&company).Error
+		if err == gorm.ErrRecordNotFound {
+			password, err := utils.EncryptPassword(demoCompanyPassword)
+			if err != nil {
+				return err
+			}
+
+			user := model.User{Email: demo.email, Password: password, RoleId: model.CompanyRole}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+
+			address := demo.address
+			if err := tx.Create(&address).Error; err != nil {
+				return err
+			}
+
+			company = demo.company
+			company.UserId = user.Id
+			company.AddressId = &address.Id
+			if err := tx.Create(&company).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		for _, demoVacancy := range demo.vacancies {
+			var count int64
+			if err := tx.Model(&vacancy.Vacancy{}).Where("company_id = ? AND code = ?", company.Id, demoVacancy.Code).Count(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				continue
+			}
+
+			if err := createDemoVacancy(tx, company.Id, demoVacancy); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// createDemoVacancy links the vacancy to the first disability of each category, so
+// the listings show the accepted disabilities
+func createDemoVacancy(tx *gorm.DB, companyId int, demoVacancy vacancy.Vacancy) error {
+	var disabilities []model.Disability
+	if err := tx.Where("id IN (?)", tx.Model(&model.Disability{}).Select("MIN(id)").Group("category")).Find(&disabilities).Error; err != nil {
+		return err
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	demoVacancy.CompanyId = companyId
+	demoVacancy.PublishDate = today
+	demoVacancy.RegistrationDate = today
+	demoVacancy.Status = enum.VacancyPublished
+	demoVacancy.Disabilities = disabilities
+
+	return tx.Create(&demoVacancy).Error
+}
+
+func demoCompanies() []demoCompany {
+	jaraguaAddress := func(street string, number string) model.Address {
+		return model.Address{
+			Street:       street,
+			Number:       number,
+			Neighborhood: "Centro",
+			City:         "Jaraguá do Sul",
+			State:        "SC",
+			Country:      "Brasil",
+			ZipCode:      "89251000",
+		}
+	}
+
+	return []demoCompany{
+		{
+			company: model.Company{Name: "Tecnologia Jaraguá Demo", Cnpj: "11222333000181", Phone: "5547999990001"},
+			email:   "demo.tecnologia@conexao-inclusao.com",
+			address: jaraguaAddress("Rua Reinoldo Rau", "100"),
+			vacancies: []vacancy.Vacancy{
+				{
+					Code:         "DEMO-TEC-001",
+					Title:        "Desenvolvedor Back-end Júnior",
+					Description:  "Desenvolvimento e manutenção de APIs em Go.",
+					Department:   "Tecnologia",
+					Section:      "Desenvolvimento",
+					Turn:         "Integral",
+					Area:         "Tecnologia",
+					ContractType: enum.CLT,
+					WorkMode:     enum.Hybrid,
+					Skills:       []vacancy.VacancySkill{{Skill: "Go"}, {Skill: "SQL"}},
+				},
+				{
+					Code:         "DEMO-TEC-002",
+					Title:        "Analista de Suporte",
+					Description:  "Atendimento aos usuários e suporte aos sistemas internos.",
+					Department:   "Tecnologia",
+					Section:      "Suporte",
+					Turn:         "Manhã",
+					Area:         "Tecnologia",
+					ContractType: enum.CLT,
+					WorkMode:     enum.Onsite,
+				},
+			},
+		},
+		{
+			company: model.Company{Name: "Comércio Inclusivo Demo", Cnpj: "44555666000122", Phone: "5547999990002"},
+			email:   "demo.comercio@conexao-inclusao.com",
+			address: jaraguaAddress("Rua Marechal Deodoro da Fonseca", "200"),
+			vacancies: []vacancy.Vacancy{
+				{
+					Code:         "DEMO-COM-001",
+					Title:        "Assistente Administrativo",
+					Description:  "Rotinas administrativas, controle de documentos e atendimento.",
+					Department:   "Administrativo",
+					Section:      "Escritório",
+					Turn:         "Tarde",
+					Area:         "Administração",
+					ContractType: enum.Trainee,
+					WorkMode:     enum.Remote,
+				},
+			},
+		},
+	}
+}
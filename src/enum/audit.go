@@ -0,0 +1,19 @@
+package enum
+
+type AuditEntity string
+
+const (
+	AuditVacancy AuditEntity = "vacancy"
+)
+
+func (a AuditEntity) IsValid() bool {
+	return a == AuditVacancy
+}
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
@@ -33,3 +33,19 @@ func GetPeriodFilterEnum(value string) PeriodFilterEnum {
 
 	return ""
 }
+
+type SortOrderEnum string
+
+const (
+	Asc  SortOrderEnum = "asc"
+	Desc SortOrderEnum = "desc"
+)
+
+func (e SortOrderEnum) IsValid() bool {
+	switch e {
+	case Asc, Desc:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,18 @@
+package enum
+
+type UserRole string
+
+const (
+	AdminRole   UserRole = "admin"
+	CompanyRole UserRole = "company"
+	// candidates are persisted under the "person" role name
+	CandidateRole UserRole = "person"
+)
+
+func (u UserRole) IsValid() bool {
+	switch u {
+	case AdminRole, CompanyRole, CandidateRole:
+		return true
+	}
+	return false
+}
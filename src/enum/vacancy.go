@@ -31,17 +31,109 @@ func (v VacancyContractType) IsValid() bool {
 	return false
 }
 
+type VacancyWorkMode string
+
+const (
+	Onsite VacancyWorkMode = "onsite"
+	Remote VacancyWorkMode = "remote"
+	Hybrid VacancyWorkMode = "hybrid"
+)
+
+func (v VacancyWorkMode) IsValid() bool {
+	switch v {
+	case Onsite, Remote, Hybrid:
+		return true
+	}
+	return false
+}
+
+type VacancyStatus string
+
+const (
+	VacancyDraft     VacancyStatus = "draft"
+	VacancyPublished VacancyStatus = "published"
+	VacancyClosed    VacancyStatus = "closed"
+)
+
+func (v VacancyStatus) IsValid() bool {
+	switch v {
+	case VacancyDraft, VacancyPublished, VacancyClosed:
+		return true
+	}
+	return false
+}
+
 type VacancyApplyStatus string
 
 const (
-	VacancyApplyApplied  VacancyApplyStatus = "applied"
-	VacancyApplyRejected VacancyApplyStatus = "rejected"
-	VacancyApplyAccepted VacancyApplyStatus = "accepted"
+	VacancyApplyApplied     VacancyApplyStatus = "applied"
+	VacancyApplyUnderReview VacancyApplyStatus = "under_review"
+	VacancyApplyInterview   VacancyApplyStatus = "interview"
+	VacancyApplyRejected    VacancyApplyStatus = "rejected"
+	VacancyApplyAccepted    VacancyApplyStatus = "accepted"
+	VacancyApplyWithdrawn   VacancyApplyStatus = "withdrawn"
 )
 
+var vacancyApplyTransitions = map[VacancyApplyStatus][]VacancyApplyStatus{
+	VacancyApplyApplied:     {VacancyApplyUnderReview, VacancyApplyInterview, VacancyApplyRejected, VacancyApplyAccepted},
+	VacancyApplyUnderReview: {VacancyApplyInterview, VacancyApplyRejected, VacancyApplyAccepted},
+	VacancyApplyInterview:   {VacancyApplyRejected, VacancyApplyAccepted},
+}
+
 func (v VacancyApplyStatus) IsValid() bool {
 	switch v {
-	case VacancyApplyApplied, VacancyApplyRejected, VacancyApplyAccepted:
+	case VacancyApplyApplied, VacancyApplyUnderReview, VacancyApplyInterview, VacancyApplyRejected, VacancyApplyAccepted, VacancyApplyWithdrawn:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the apply can't change its status anymore
+func (v VacancyApplyStatus) IsTerminal() bool {
+	switch v {
+	case VacancyApplyRejected, VacancyApplyAccepted, VacancyApplyWithdrawn:
+		return true
+	}
+	return false
+}
+
+func (v VacancyApplyStatus) CanTransitionTo(status VacancyApplyStatus) bool {
+	for _, next := range vacancyApplyTransitions[v] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// VacancyStatsInterval is the size of the buckets of the vacancy time series
+type VacancyStatsInterval string
+
+const (
+	VacancyStatsDay   VacancyStatsInterval = "day"
+	VacancyStatsWeek  VacancyStatsInterval = "week"
+	VacancyStatsMonth VacancyStatsInterval = "month"
+)
+
+func (v VacancyStatsInterval) IsValid() bool {
+	switch v {
+	case VacancyStatsDay, VacancyStatsWeek, VacancyStatsMonth:
+		return true
+	}
+	return false
+}
+
+type VacancySortBy string
+
+const (
+	VacancySortByCreatedAt VacancySortBy = "created_at"
+	VacancySortByTitle     VacancySortBy = "title"
+	VacancySortBySalary    VacancySortBy = "salary"
+)
+
+func (v VacancySortBy) IsValid() bool {
+	switch v {
+	case VacancySortByCreatedAt, VacancySortByTitle, VacancySortBySalary:
 		return true
 	}
 	return false
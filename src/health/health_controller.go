@@ -0,0 +1,78 @@
+package health
+
+import (
+	"cij_api/src/model"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthController struct {
+	healthService *HealthService
+}
+
+func NewHealthController(healthService *HealthService) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+	}
+}
+
+// Liveness
+// @Summary Liveness probe.
+// @Description report that the server is running.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} model.Response
+// @Router /health/live [get]
+func (h *HealthController) Liveness(ctx *fiber.Ctx) error {
+	response := model.Response{
+		Message: "Server is up and running",
+		Data:    h.healthService.Liveness(),
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// Readiness
+// @Summary Readiness probe.
+// @Description report whether the server dependencies, such as the database, are reachable.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} model.Response
+// @Failure 503 {object} model.Response
+// @Router /health/ready [get]
+func (h *HealthController) Readiness(ctx *fiber.Ctx) error {
+	status := h.healthService.Readiness()
+
+	if status.Status != StatusUp {
+		response := model.Response{
+			Message: "a dependency is unavailable",
+			Data:    status,
+		}
+
+		return ctx.Status(http.StatusServiceUnavailable).JSON(response)
+	}
+
+	response := model.Response{
+		Message: "ready",
+		Data:    status,
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
+
+// Version
+// @Summary Deployed version.
+// @Description report the git commit, build time and go version of the deployed build, and the latest applied schema migration.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} model.Response
+// @Router /version [get]
+func (h *HealthController) Version(ctx *fiber.Ctx) error {
+	response := model.Response{
+		Message: "version retrieved successfully",
+		Data:    h.healthService.Version(),
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response)
+}
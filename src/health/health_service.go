@@ -0,0 +1,75 @@
+package health
+
+import (
+	"cij_api/src/database"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const readinessTimeout = 2 * time.Second
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type Status struct {
+	Status       string            `json:"status"`
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+}
+
+type HealthService struct {
+	db *gorm.DB
+}
+
+func NewHealthService(db *gorm.DB) *HealthService {
+	return &HealthService{
+		db: db,
+	}
+}
+
+// Liveness only reports that the process is running, without touching any dependency
+func (h *HealthService) Liveness() Status {
+	return Status{Status: StatusUp}
+}
+
+// Readiness pings the database and reports the status of each dependency
+func (h *HealthService) Readiness() Status {
+	status := Status{
+		Status:       StatusUp,
+		Dependencies: map[string]string{"database": StatusUp},
+	}
+
+	if err := h.pingDatabase(); err != nil {
+		status.Status = StatusDown
+		status.Dependencies["database"] = StatusDown
+	}
+
+	return status
+}
+
+// Version reports the deployed build and the latest applied schema migration, zero
+// when it can't be read
+func (h *HealthService) Version() Version {
+	version := buildVersion()
+
+	if migration, err := database.CurrentMigrationVersion(h.db); err == nil {
+		version.SchemaMigration = migration
+	}
+
+	return version
+}
+
+func (h *HealthService) pingDatabase() error {
+	sqlDb, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+	defer cancel()
+
+	return sqlDb.PingContext(ctx)
+}
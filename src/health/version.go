@@ -0,0 +1,53 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Commit and BuildTime are set at build time, for example with
+// go build -ldflags "-X cij_api/src/health.Commit=$(git rev-parse HEAD) -X cij_api/src/health.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+const unknownBuildValue = "unknown"
+
+type Version struct {
+	Commit          string `json:"commit"`
+	BuildTime       string `json:"build_time"`
+	GoVersion       string `json:"go_version"`
+	SchemaMigration int    `json:"schema_migration"`
+}
+
+// buildVersion falls back to the vcs information embedded by the go toolchain when
+// the build didn't set the ldflags
+func buildVersion() Version {
+	version := Version{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && version.Commit == "":
+				version.Commit = setting.Value
+			case setting.Key == "vcs.time" && version.BuildTime == "":
+				version.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if version.Commit == "" {
+		version.Commit = unknownBuildValue
+	}
+
+	if version.BuildTime == "" {
+		version.BuildTime = unknownBuildValue
+	}
+
+	return version
+}
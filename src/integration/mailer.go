@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"cij_api/src/config"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+type smtpMailer struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+type noopMailer struct{}
+
+// NewNoopMailer returns a mailer that discards every email, used when sending is disabled
+func NewNoopMailer() Mailer {
+	return &noopMailer{}
+}
+
+func (n *noopMailer) Send(to string, subject string, body string) error {
+	return nil
+}
+
+func NewSmtpMailer(mailerConfig config.MailerConfig) Mailer {
+	var auth smtp.Auth
+	if mailerConfig.SmtpUser != "" {
+		auth = smtp.PlainAuth("", mailerConfig.SmtpUser, mailerConfig.SmtpPassword, mailerConfig.SmtpHost)
+	}
+
+	return &smtpMailer{
+		address: fmt.Sprintf("%s:%d", mailerConfig.SmtpHost, mailerConfig.SmtpPort),
+		from:    mailerConfig.From,
+		auth:    auth,
+	}
+}
+
+func (s *smtpMailer) Send(to string, subject string, body string) error {
+	headers := []string{
+		"From: " + s.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+	}
+
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" + body
+
+	return smtp.SendMail(s.address, s.auth, s.from, []string{to}, []byte(message))
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+type contextKey string
+
+const requestIdKey contextKey = "request_id"
+
+var base = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, requestIdKey, requestId)
+}
+
+func RequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	requestId, _ := ctx.Value(requestIdKey).(string)
+
+	return requestId
+}
+
+// FromContext returns the logger tagged with the request id stored in the context, if any
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestId := RequestId(ctx); requestId != "" {
+		return base.With("request_id", requestId)
+	}
+
+	return base
+}
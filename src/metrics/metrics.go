@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Default is the registry the services report to and the /metrics endpoint exposes
+var Default = NewRegistry(DefaultBuckets)
+
+type operationMetrics struct {
+	requests     uint64
+	errors       uint64
+	bucketCounts []uint64
+	durationSum  float64
+}
+
+// Registry keeps the request count, the error count and the latency histogram of
+// each service operation, written in the prometheus text format
+type Registry struct {
+	mutex      sync.Mutex
+	buckets    []float64
+	operations map[string]*operationMetrics
+}
+
+func NewRegistry(buckets []float64) *Registry {
+	return &Registry{
+		buckets:    buckets,
+		operations: map[string]*operationMetrics{},
+	}
+}
+
+// Observe records one call of the operation, how long it took and whether it failed
+func (r *Registry) Observe(operation string, duration time.Duration, failed bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	metrics, ok := r.operations[operation]
+	if !ok {
+		metrics = &operationMetrics{bucketCounts: make([]uint64, len(r.buckets))}
+		r.operations[operation] = metrics
+	}
+
+	seconds := duration.Seconds()
+
+	metrics.requests++
+	metrics.durationSum += seconds
+	if failed {
+		metrics.errors++
+	}
+
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			metrics.bucketCounts[i]++
+		}
+	}
+}
+
+// WriteTo writes every metric in the prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	operations := make([]string, 0, len(r.operations))
+	for operation := range r.operations {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+
+	writer := &countingWriter{w: w}
+
+	writer.printf("# HELP cij_service_requests_total Number of calls of each service operation.\n")
+	writer.printf("# TYPE cij_service_requests_total counter\n")
+	for _, operation := range operations {
+		writer.printf("cij_service_requests_total{operation=%q} %d\n", operation, r.operations[operation].requests)
+	}
+
+	writer.printf("# HELP cij_service_errors_total Number of service operation calls that returned an error.\n")
+	writer.printf("# TYPE cij_service_errors_total counter\n")
+	for _, operation := range operations {
+		writer.printf("cij_service_errors_total{operation=%q} %d\n", operation, r.operations[operation].errors)
+	}
+
+	writer.printf("# HELP cij_service_duration_seconds Latency of each service operation.\n")
+	writer.printf("# TYPE cij_service_duration_seconds histogram\n")
+	for _, operation := range operations {
+		metrics := r.operations[operation]
+
+		for i, bound := range r.buckets {
+			writer.printf("cij_service_duration_seconds_bucket{operation=%q,le=\"%g\"} %d\n", operation, bound, metrics.bucketCounts[i])
+		}
+		writer.printf("cij_service_duration_seconds_bucket{operation=%q,le=\"+Inf\"} %d\n", operation, metrics.requests)
+		writer.printf("cij_service_duration_seconds_sum{operation=%q} %g\n", operation, metrics.durationSum)
+		writer.printf("cij_service_duration_seconds_count{operation=%q} %d\n", operation, metrics.requests)
+	}
+
+	return writer.written, writer.err
+}
+
+type countingWriter struct {
+	w       io.Writer
+	written int64
+	err     error
+}
+
+func (c *countingWriter) printf(format string, args ...any) {
+	if c.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(c.w, format, args...)
+	c.written += int64(n)
+	c.err = err
+}
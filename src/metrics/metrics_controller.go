@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler
+// @Summary Service metrics.
+// @Description expose the request count, error count and latency histogram of each service operation in the prometheus text format.
+// @Tags Metrics
+// @Produce plain
+// @Success 200 {string} string
+// @Router /metrics [get]
+func Handler(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+
+	if _, err := Default.WriteTo(ctx); err != nil {
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	return ctx.Status(http.StatusOK).Send(nil)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"cij_api/src/auth"
+	"cij_api/src/model"
+	"cij_api/src/repo"
+	"cij_api/src/utils"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ActiveUser rejects the tokens of the deactivated accounts, which would be accepted
+// until they expire otherwise. Requests without a valid token are left to the auth
+// middlewares of the routes
+type ActiveUser struct {
+	userRepo repo.UserRepo
+}
+
+func NewActiveUser(userRepo repo.UserRepo) *ActiveUser {
+	return &ActiveUser{userRepo: userRepo}
+}
+
+func (a *ActiveUser) Handler(ctx *fiber.Ctx) error {
+	tokenParam := ctx.Get("Authorization")
+	if tokenParam == "" {
+		return ctx.Next()
+	}
+
+	claims, err := auth.ValidateToken(tokenParam)
+	if err.IsError() {
+		return ctx.Next()
+	}
+
+	user, err := a.userRepo.GetUserById(claims.Id)
+	if err.IsError() {
+		response := model.Response{
+			Message: err.Message,
+			Code:    err.Code,
+		}
+
+		return ctx.Status(http.StatusInternalServerError).JSON(response)
+	}
+
+	if user.Id == 0 {
+		response := model.Response{
+			Message: "user not found",
+			Code:    utils.NewErrorCode(utils.UnauthorizedErrorCode, utils.UserErrorType, "10"),
+		}
+
+		return ctx.Status(http.StatusUnauthorized).JSON(response)
+	}
+
+	if !user.Active {
+		response := model.Response{
+			Message: "account disabled",
+			Code:    utils.NewErrorCode(utils.ForbiddenErrorCode, utils.UserErrorType, "09"),
+		}
+
+		return ctx.Status(http.StatusForbidden).JSON(response)
+	}
+
+	return ctx.Next()
+}
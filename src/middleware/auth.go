@@ -2,31 +2,49 @@ package middleware
 
 import (
 	"cij_api/src/auth"
+	"cij_api/src/enum"
 	"cij_api/src/model"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt"
 )
 
-type CustomClaims struct {
-	jwt.StandardClaims
-}
-
 const PERSON_ROLE = "person"
 const COMPANY_ROLE = "company"
 const ADMIN_ROLE = "admin"
 
+const claimsLocalsKey = "user_claims"
+
+// Claims returns the claims stored by the auth middlewares for the current request
+func Claims(ctx *fiber.Ctx) model.UserClaims {
+	claims, _ := ctx.Locals(claimsLocalsKey).(model.UserClaims)
+
+	return claims
+}
+
+// storeClaims keeps the claims in the locals and the authenticated user in the user context
+func storeClaims(ctx *fiber.Ctx, claims model.UserClaims) {
+	ctx.Locals(claimsLocalsKey, claims)
+
+	user := model.User{
+		Id:     claims.Id,
+		Email:  claims.Email,
+		RoleId: model.RoleIdFromUserRole(enum.UserRole(claims.Role)),
+	}
+
+	ctx.SetUserContext(model.WithCurrentUser(ctx.UserContext(), user))
+}
+
 func AuthUser(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	token, err := Auth(ctx)
+	claims, err := Auth(ctx)
 	if err.Message != "" {
-		return ctx.Status(http.StatusBadRequest).JSON(err)
+		return ctx.Status(http.StatusUnauthorized).JSON(err)
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	tokenRole := claims["role"].(string)
+	storeClaims(ctx, claims)
+	tokenRole := claims.Role
 
 	if tokenRole != PERSON_ROLE && tokenRole != ADMIN_ROLE {
 		response = model.Response{
@@ -39,16 +57,38 @@ func AuthUser(ctx *fiber.Ctx) error {
 	return ctx.Next()
 }
 
+// Authenticated accepts any valid token, whatever the role
+func Authenticated(ctx *fiber.Ctx) error {
+	claims, err := Auth(ctx)
+	if err.Message != "" {
+		return ctx.Status(http.StatusUnauthorized).JSON(err)
+	}
+
+	storeClaims(ctx, claims)
+
+	return ctx.Next()
+}
+
+// OptionalAuth stores the claims of a valid token and lets anonymous requests through,
+// for public routes that show more to authenticated users
+func OptionalAuth(ctx *fiber.Ctx) error {
+	if claims, err := Auth(ctx); err.Message == "" {
+		storeClaims(ctx, claims)
+	}
+
+	return ctx.Next()
+}
+
 func AuthAdmin(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	token, err := Auth(ctx)
+	claims, err := Auth(ctx)
 	if err.Message != "" {
-		return ctx.Status(http.StatusBadRequest).JSON(err)
+		return ctx.Status(http.StatusUnauthorized).JSON(err)
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	tokenRole := claims["role"].(string)
+	storeClaims(ctx, claims)
+	tokenRole := claims.Role
 
 	if tokenRole != ADMIN_ROLE {
 		response = model.Response{
@@ -64,13 +104,13 @@ func AuthAdmin(ctx *fiber.Ctx) error {
 func AuthCompany(ctx *fiber.Ctx) error {
 	var response model.Response
 
-	token, err := Auth(ctx)
+	claims, err := Auth(ctx)
 	if err.Message != "" {
-		return ctx.Status(http.StatusBadRequest).JSON(err)
+		return ctx.Status(http.StatusUnauthorized).JSON(err)
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	tokenRole := claims["role"].(string)
+	storeClaims(ctx, claims)
+	tokenRole := claims.Role
 
 	if tokenRole != COMPANY_ROLE && tokenRole != ADMIN_ROLE {
 		response = model.Response{
@@ -83,7 +123,7 @@ func AuthCompany(ctx *fiber.Ctx) error {
 	return ctx.Next()
 }
 
-func Auth(ctx *fiber.Ctx) (*jwt.Token, model.Response) {
+func Auth(ctx *fiber.Ctx) (model.UserClaims, model.Response) {
 	var response model.Response
 	tokenParam := ctx.Get("Authorization")
 
@@ -92,25 +132,18 @@ func Auth(ctx *fiber.Ctx) (*jwt.Token, model.Response) {
 			Message: "token not found",
 		}
 
-		return nil, response
-	}
-
-	token, err := auth.ValidateToken(tokenParam)
-	if err != nil {
-		response = model.Response{
-			Message: "token invalid or expired",
-		}
-
-		return nil, response
+		return model.UserClaims{}, response
 	}
 
-	if !token.Valid {
+	claims, err := auth.ValidateToken(tokenParam)
+	if err.IsError() {
 		response = model.Response{
-			Message: "token is not valid",
+			Message: err.Message,
+			Code:    err.Code,
 		}
 
-		return nil, response
+		return model.UserClaims{}, response
 	}
 
-	return token, model.Response{}
+	return claims, model.Response{}
 }
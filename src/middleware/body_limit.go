@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"cij_api/src/model"
+	"cij_api/src/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit rejects json bodies larger than the limit. Multipart uploads keep the
+// server limit, since curriculums and logos are bigger than any json payload
+type BodyLimit struct {
+	maxBytes int
+}
+
+func NewBodyLimit(maxBytes int) *BodyLimit {
+	return &BodyLimit{maxBytes: maxBytes}
+}
+
+func (b *BodyLimit) Handler(ctx *fiber.Ctx) error {
+	if b.maxBytes <= 0 || !ctx.Is("json") {
+		return ctx.Next()
+	}
+
+	if ctx.Request().Header.ContentLength() > b.maxBytes || len(ctx.Body()) > b.maxBytes {
+		response := model.Response{
+			Message: fmt.Sprintf("the request body must have at most %d bytes", b.maxBytes),
+			Code:    utils.NewErrorCode(utils.ValidationErrorCode, utils.RequestErrorType, "03"),
+		}
+
+		return ctx.Status(http.StatusRequestEntityTooLarge).JSON(response)
+	}
+
+	return ctx.Next()
+}
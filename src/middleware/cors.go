@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const corsWildcard = "*"
+
+// Cors answers the preflight requests and sets the cors headers for the allowed
+// origins. Requests from other origins get no cors headers, so the browser blocks them
+type Cors struct {
+	origins          map[string]bool
+	allowAllOrigins  bool
+	methods          string
+	headers          string
+	allowCredentials bool
+	maxAge           string
+}
+
+// NewCors only allows every origin when the wildcard is explicitly listed, which is
+// meant for local development
+func NewCors(origins []string, methods []string, headers []string, allowCredentials bool, maxAge time.Duration) *Cors {
+	cors := &Cors{
+		origins:          map[string]bool{},
+		methods:          strings.Join(methods, ","),
+		headers:          strings.Join(headers, ","),
+		allowCredentials: allowCredentials,
+		maxAge:           strconv.Itoa(int(maxAge.Seconds())),
+	}
+
+	for _, origin := range origins {
+		if origin == corsWildcard {
+			cors.allowAllOrigins = true
+			continue
+		}
+
+		cors.origins[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return cors
+}
+
+func (c *Cors) Handler(ctx *fiber.Ctx) error {
+	origin := ctx.Get(fiber.HeaderOrigin)
+	preflight := ctx.Method() == fiber.MethodOptions && ctx.Get(fiber.HeaderAccessControlRequestMethod) != ""
+
+	ctx.Vary(fiber.HeaderOrigin)
+
+	if origin == "" || (!c.allowAllOrigins && !c.origins[origin]) {
+		if preflight {
+			return ctx.SendStatus(fiber.StatusNoContent)
+		}
+
+		return ctx.Next()
+	}
+
+	// browsers refuse the wildcard on credentialed requests, so the origin is echoed
+	allowOrigin := origin
+	if c.allowAllOrigins && !c.allowCredentials {
+		allowOrigin = corsWildcard
+	}
+
+	ctx.Set(fiber.HeaderAccessControlAllowOrigin, allowOrigin)
+
+	if c.allowCredentials {
+		ctx.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+	}
+
+	if !preflight {
+		ctx.Set(fiber.HeaderAccessControlExposeHeaders, RequestIdHeader+","+fiber.HeaderETag+","+fiber.HeaderLastModified)
+
+		return ctx.Next()
+	}
+
+	ctx.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+	ctx.Set(fiber.HeaderAccessControlAllowMethods, c.methods)
+	ctx.Set(fiber.HeaderAccessControlAllowHeaders, c.headers)
+	ctx.Set(fiber.HeaderAccessControlMaxAge, c.maxAge)
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
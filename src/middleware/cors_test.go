@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newCorsTestApp() *fiber.App {
+	cors := NewCors(
+		[]string{"https://conexao-inclusao.com"},
+		[]string{"GET", "POST"},
+		[]string{"Content-Type", "Authorization"},
+		true,
+		10*time.Minute,
+	)
+
+	app := fiber.New()
+	app.Use(cors.Handler)
+	app.Post("/vacancies", func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+
+	return app
+}
+
+func TestCorsPreflightFromDisallowedOrigin(t *testing.T) {
+	request := httptest.NewRequest(fiber.MethodOptions, "/vacancies", nil)
+	request.Header.Set(fiber.HeaderOrigin, "https://evil.example.com")
+	request.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+
+	response, err := newCorsTestApp().Test(request)
+	if err != nil {
+		t.Fatalf("the preflight failed: %v", err)
+	}
+
+	if allowOrigin := response.Header.Get(fiber.HeaderAccessControlAllowOrigin); allowOrigin != "" {
+		t.Errorf("a disallowed origin got the allow origin header %q", allowOrigin)
+	}
+
+	if allowCredentials := response.Header.Get(fiber.HeaderAccessControlAllowCredentials); allowCredentials != "" {
+		t.Errorf("a disallowed origin got the allow credentials header %q", allowCredentials)
+	}
+}
+
+func TestCorsPreflightFromAllowedOrigin(t *testing.T) {
+	request := httptest.NewRequest(fiber.MethodOptions, "/vacancies", nil)
+	request.Header.Set(fiber.HeaderOrigin, "https://conexao-inclusao.com")
+	request.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+
+	response, err := newCorsTestApp().Test(request)
+	if err != nil {
+		t.Fatalf("the preflight failed: %v", err)
+	}
+
+	if allowOrigin := response.Header.Get(fiber.HeaderAccessControlAllowOrigin); allowOrigin != "https://conexao-inclusao.com" {
+		t.Errorf("the allowed origin should be echoed, got %q", allowOrigin)
+	}
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"cij_api/src/model"
+	"cij_api/src/utils"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const rateLimitSweepSize = 10000
+
+type rateLimitEntry struct {
+	attempts  int
+	expiresAt time.Time
+}
+
+// RateLimiter allows a number of failed attempts per key within a window. Responses
+// with an error status count as failed attempts and a successful one resets the key
+type RateLimiter struct {
+	mu          sync.Mutex
+	entries     map[string]*rateLimitEntry
+	maxAttempts int
+	window      time.Duration
+	keyFunc     func(ctx *fiber.Ctx) string
+}
+
+func NewRateLimiter(maxAttempts int, window time.Duration, keyFunc func(ctx *fiber.Ctx) string) *RateLimiter {
+	if keyFunc == nil {
+		keyFunc = IpRateLimitKey
+	}
+
+	return &RateLimiter{
+		entries:     map[string]*rateLimitEntry{},
+		maxAttempts: maxAttempts,
+		window:      window,
+		keyFunc:     keyFunc,
+	}
+}
+
+func IpRateLimitKey(ctx *fiber.Ctx) string {
+	return ctx.IP()
+}
+
+// IpAndEmailRateLimitKey keys the limit by ip and the email sent in the json body,
+// so one address can't lock out every account behind the same ip
+func IpAndEmailRateLimitKey(ctx *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.Unmarshal(ctx.Body(), &body); err != nil || body.Email == "" {
+		return ctx.IP()
+	}
+
+	return ctx.IP() + "|" + utils.NormalizeEmail(body.Email)
+}
+
+func (r *RateLimiter) Handler(ctx *fiber.Ctx) error {
+	key := r.keyFunc(ctx)
+
+	if retryAfter, blocked := r.blocked(key); blocked {
+		response := model.Response{
+			Message: "too many attempts, try again later",
+		}
+
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+		return ctx.Status(http.StatusTooManyRequests).JSON(response)
+	}
+
+	err := ctx.Next()
+
+	if err != nil || ctx.Response().StatusCode() >= http.StatusBadRequest {
+		r.fail(key)
+	} else {
+		r.reset(key)
+	}
+
+	return err
+}
+
+func (r *RateLimiter) blocked(key string) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return 0, false
+	}
+
+	now := time.Now()
+	if now.After(entry.expiresAt) {
+		delete(r.entries, key)
+		return 0, false
+	}
+
+	if entry.attempts < r.maxAttempts {
+		return 0, false
+	}
+
+	return entry.expiresAt.Sub(now), true
+}
+
+func (r *RateLimiter) fail(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	if len(r.entries) >= rateLimitSweepSize {
+		for entryKey, entry := range r.entries {
+			if now.After(entry.expiresAt) {
+				delete(r.entries, entryKey)
+			}
+		}
+	}
+
+	entry, ok := r.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		entry = &rateLimitEntry{expiresAt: now.Add(r.window)}
+		r.entries[key] = entry
+	}
+
+	entry.attempts++
+}
+
+func (r *RateLimiter) reset(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, key)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"cij_api/src/logger"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const RequestIdHeader = "X-Request-Id"
+
+// RequestId reuses the incoming request id header or generates one, storing it in
+// the user context for the logger and echoing it back in the response
+func RequestId(ctx *fiber.Ctx) error {
+	requestId := ctx.Get(RequestIdHeader)
+
+	if requestId == "" || len(requestId) > 64 {
+		requestIdBytes := make([]byte, 16)
+		if _, err := rand.Read(requestIdBytes); err == nil {
+			requestId = hex.EncodeToString(requestIdBytes)
+		}
+	}
+
+	ctx.Locals("request_id", requestId)
+	ctx.SetUserContext(logger.WithRequestId(ctx.UserContext(), requestId))
+	ctx.Set(RequestIdHeader, requestId)
+
+	return ctx.Next()
+}
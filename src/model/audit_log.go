@@ -0,0 +1,66 @@
+package model
+
+import (
+	"cij_api/src/enum"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+type AuditLog struct {
+	Id          int              `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	Entity      enum.AuditEntity `gorm:"type:varchar(50);not null;index:idx_audit_log_entity" json:"entity"`
+	EntityId    int              `gorm:"type:int;not null;index:idx_audit_log_entity" json:"entity_id"`
+	Action      enum.AuditAction `gorm:"type:varchar(20);not null" json:"action"`
+	ActorUserId *int             `gorm:"type:int" json:"actor_user_id"`
+	Timestamp   time.Time        `gorm:"not null" json:"timestamp"`
+	Changes     json.RawMessage  `gorm:"type:json" json:"changes"`
+}
+
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// DiffAuditFields returns the fields whose value differs between before and after, a
+// field missing from one of them is taken as nil
+func DiffAuditFields(before map[string]any, after map[string]any) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+
+	for field, from := range before {
+		if to := after[field]; !reflect.DeepEqual(from, to) {
+			changes[field] = AuditChange{From: from, To: to}
+		}
+	}
+
+	for field, to := range after {
+		if _, ok := before[field]; !ok && to != nil {
+			changes[field] = AuditChange{To: to}
+		}
+	}
+
+	return changes
+}
+
+// NewAuditLog stamps the entry with the current time and the actor of the context,
+// encoding the changes as json
+func NewAuditLog(entity enum.AuditEntity, entityId int, action enum.AuditAction, actor *User, changes map[string]AuditChange) (AuditLog, error) {
+	encodedChanges, err := json.Marshal(changes)
+	if err != nil {
+		return AuditLog{}, err
+	}
+
+	auditLog := AuditLog{
+		Entity:    entity,
+		EntityId:  entityId,
+		Action:    action,
+		Timestamp: time.Now(),
+		Changes:   encodedChanges,
+	}
+
+	if actor != nil && actor.Id != 0 {
+		auditLog.ActorUserId = &actor.Id
+	}
+
+	return auditLog, nil
+}
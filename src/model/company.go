@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Company struct {
 	*gorm.Model
@@ -8,46 +12,115 @@ type Company struct {
 	Name      string `gorm:"type:varchar(200);not null" json:"name"`
 	Cnpj      string `gorm:"type:char(14);not null;unique" json:"cnpj"`
 	Phone     string `gorm:"type:char(13);not null" json:"phone"`
+	LogoUrl   string `gorm:"type:varchar(255)" json:"logo_url"`
 	UserId    int    `gorm:"type:int;not null;unique" json:"user_id"`
 	AddressId *int   `gorm:"type:int;not null;unique" json:"address_id"`
-	User      *User
-	Address   *Address
+	// MaxOpenVacancies overrides the configured cap of open vacancies for the company,
+	// zero removes the cap
+	MaxOpenVacancies *int `gorm:"type:int" json:"max_open_vacancies"`
+	// Verified is set by an admin once the company is confirmed to be legitimate, it's
+	// shown as a badge on the company and its vacancies
+	Verified   bool       `gorm:"not null;default:false" json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	User       *User
+	Address    *Address
 }
 
 type CompanyRequest struct {
-	Name    string         `json:"name"`
-	Cnpj    string         `json:"cnpj"`
-	Phone   string         `json:"phone"`
-	User    UserRequest    `json:"user"`
-	Address AddressRequest `json:"address"`
+	Name             string         `json:"name"`
+	Cnpj             string         `json:"cnpj"`
+	Phone            string         `json:"phone"`
+	MaxOpenVacancies *int           `json:"max_open_vacancies,omitempty"`
+	User             UserRequest    `json:"user"`
+	Address          AddressRequest `json:"address"`
 }
 
 type CompanyResponse struct {
-	Id      int             `json:"id"`
-	Name    string          `json:"name"`
-	Cnpj    string          `json:"cnpj"`
-	Phone   string          `json:"phone"`
-	User    UserResponse    `json:"user"`
-	Address AddressResponse `json:"address"`
+	Id               int             `json:"id"`
+	Name             string          `json:"name"`
+	Cnpj             string          `json:"cnpj"`
+	Phone            string          `json:"phone"`
+	LogoUrl          string          `json:"logo_url,omitempty"`
+	MaxOpenVacancies *int            `json:"max_open_vacancies,omitempty"`
+	Verified         bool            `json:"verified"`
+	VerifiedAt       *time.Time      `json:"verified_at,omitempty"`
+	User             UserResponse    `json:"user"`
+	Address          AddressResponse `json:"address"`
+}
+
+// CompanyPublicResponse is the company shown to anonymous callers, without the phone
+// and the user email
+type CompanyPublicResponse struct {
+	Id       int             `json:"id"`
+	Name     string          `json:"name"`
+	Cnpj     string          `json:"cnpj"`
+	LogoUrl  string          `json:"logo_url,omitempty"`
+	Verified bool            `json:"verified"`
+	Address  AddressResponse `json:"address"`
+}
+
+func (c *CompanyResponse) ToPublicResponse() CompanyPublicResponse {
+	return CompanyPublicResponse{
+		Id:       c.Id,
+		Name:     c.Name,
+		Cnpj:     c.Cnpj,
+		LogoUrl:  c.LogoUrl,
+		Verified: c.Verified,
+		Address:  c.Address,
+	}
+}
+
+// Validate checks the required fields of the company, the cnpj digits and the user are
+// checked by the controller
+func (c *CompanyRequest) Validate() ValidationError {
+	validation := ValidationError{}
+
+	if c.Name == "" {
+		validation.Add("name", "01", "name is required")
+	}
+
+	if c.Cnpj == "" {
+		validation.Add("cnpj", "01", "cnpj is required")
+	}
+
+	if c.Phone == "" {
+		validation.Add("phone", "01", "phone is required")
+	}
+
+	if c.MaxOpenVacancies != nil && *c.MaxOpenVacancies < 0 {
+		validation.Add("max_open_vacancies", "05", "max open vacancies must not be negative")
+	}
+
+	return validation
 }
 
 func (c *Company) ToResponse(user User) CompanyResponse {
+	var address AddressResponse
+	if c.Address != nil {
+		address = c.Address.ToResponse()
+	}
+
 	return CompanyResponse{
-		Id:      c.Id,
-		Name:    c.Name,
-		Cnpj:    c.Cnpj,
-		Phone:   c.Phone,
-		User:    user.ToResponse(),
-		Address: c.Address.ToResponse(),
+		Id:               c.Id,
+		Name:             c.Name,
+		Cnpj:             c.Cnpj,
+		Phone:            c.Phone,
+		LogoUrl:          c.LogoUrl,
+		MaxOpenVacancies: c.MaxOpenVacancies,
+		Verified:         c.Verified,
+		VerifiedAt:       c.VerifiedAt,
+		User:             user.ToResponse(),
+		Address:          address,
 	}
 }
 
 func (c *CompanyRequest) ToModel(user User) Company {
 	return Company{
-		Name:   c.Name,
-		Cnpj:   c.Cnpj,
-		Phone:  c.Phone,
-		UserId: user.Id,
+		Name:             c.Name,
+		Cnpj:             c.Cnpj,
+		Phone:            c.Phone,
+		MaxOpenVacancies: c.MaxOpenVacancies,
+		UserId:           user.Id,
 	}
 }
 
@@ -55,6 +128,7 @@ func (c *CompanyRequest) ToUser() User {
 	return User{
 		Email:    c.User.Email,
 		Password: c.User.Password,
+		RoleId:   CompanyRole,
 	}
 }
 
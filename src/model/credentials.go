@@ -1,6 +1,15 @@
 package model
 
+import "github.com/golang-jwt/jwt"
+
 type Credentials struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type UserClaims struct {
+	jwt.StandardClaims
+	Id    int    `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
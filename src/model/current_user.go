@@ -0,0 +1,26 @@
+package model
+
+import "context"
+
+type currentUserKey struct{}
+
+// WithCurrentUser stores the authenticated user in the context
+func WithCurrentUser(ctx context.Context, user User) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, currentUserKey{}, user)
+}
+
+// CurrentUser returns the authenticated user of the context. It is only filled from the
+// token claims, the second value is false when the request is not authenticated
+func CurrentUser(ctx context.Context) (User, bool) {
+	if ctx == nil {
+		return User{}, false
+	}
+
+	user, ok := ctx.Value(currentUserKey{}).(User)
+
+	return user, ok
+}
@@ -32,6 +32,30 @@ type DisabilityResponse struct {
 	Acquired    bool   `json:"acquired"`
 }
 
+type DisabilityCategoryResponse struct {
+	Category     string               `json:"category"`
+	Disabilities []DisabilityResponse `json:"disabilities"`
+}
+
+// GroupDisabilitiesByCategory keeps the categories in the order they first appear
+func GroupDisabilitiesByCategory(disabilities []DisabilityResponse) []DisabilityCategoryResponse {
+	categories := []DisabilityCategoryResponse{}
+	indexes := map[string]int{}
+
+	for _, disability := range disabilities {
+		index, ok := indexes[disability.Category]
+		if !ok {
+			index = len(categories)
+			indexes[disability.Category] = index
+			categories = append(categories, DisabilityCategoryResponse{Category: disability.Category})
+		}
+
+		categories[index].Disabilities = append(categories[index].Disabilities, disability)
+	}
+
+	return categories
+}
+
 func (d *Disability) ToResponse() DisabilityResponse {
 	return DisabilityResponse{
 		Id:          d.Id,
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type PasswordResetToken struct {
+	Id        int        `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	UserId    int        `gorm:"type:int;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	User      *User
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
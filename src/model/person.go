@@ -2,23 +2,29 @@ package model
 
 import (
 	"cij_api/src/enum"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Person struct {
 	*gorm.Model
-	Id           int             `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
-	Name         string          `gorm:"type:varchar(200);not null" json:"name"`
-	Cpf          string          `gorm:"type:char(11);not null;unique" json:"cpf"`
-	Phone        string          `gorm:"type:char(13);not null" json:"phone"`
-	Gender       enum.GenderEnum `gorm:"type:char(6);not null" json:"gender"`
-	UserId       int             `gorm:"type:int;not null;unique" json:"user_id"`
-	AddressId    *int            `gorm:"type:int;unique" json:"address_id"`
-	Curriculum   string          `gorm:"type:varchar(255)" json:"curriculum"`
-	Address      *Address
-	User         *User
-	Disabilities []PersonDisability
+	Id             int             `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	Name           string          `gorm:"type:varchar(200);not null" json:"name"`
+	Cpf            string          `gorm:"type:char(11);not null;unique" json:"cpf"`
+	Phone          string          `gorm:"type:char(13);not null" json:"phone"`
+	Gender         enum.GenderEnum `gorm:"type:char(6);not null" json:"gender"`
+	UserId         int             `gorm:"type:int;not null;unique" json:"user_id"`
+	AddressId      *int            `gorm:"type:int;unique" json:"address_id"`
+	Curriculum     string          `gorm:"type:varchar(255)" json:"curriculum"`
+	BirthDate      *string         `gorm:"type:date" json:"birth_date"`
+	Area           string          `gorm:"type:varchar(200)" json:"area"`
+	ConsentGivenAt *time.Time      `json:"consent_given_at"`
+	AnonymizedAt   *time.Time      `json:"anonymized_at"`
+	Address        *Address
+	User           *User
+	Disabilities   []PersonDisability
 }
 
 type PersonRequest struct {
@@ -26,6 +32,9 @@ type PersonRequest struct {
 	Cpf          string                    `json:"cpf"`
 	Phone        string                    `json:"phone"`
 	Gender       enum.GenderEnum           `json:"gender"`
+	BirthDate    *string                   `json:"birth_date"`
+	Area         string                    `json:"area"`
+	Consent      bool                      `json:"consent"`
 	User         UserRequest               `json:"user"`
 	Address      AddressRequest            `json:"address"`
 	Disabilities []PersonDisabilityRequest `json:"disabilities"`
@@ -38,19 +47,25 @@ type PersonResponse struct {
 	Phone        string                      `json:"phone"`
 	Gender       enum.GenderEnum             `json:"gender"`
 	Curriculum   string                      `json:"curriculum,omitempty"`
+	BirthDate    *string                     `json:"birth_date,omitempty"`
+	Area         string                      `json:"area,omitempty"`
 	User         UserResponse                `json:"user"`
 	Address      *AddressResponse            `json:"address,omitempty"`
 	Disabilities *[]PersonDisabilityResponse `json:"disabilities,omitempty"`
 }
 
 type CandidateResponse struct {
-	Name         string               `json:"name"`
-	Cpf          string               `json:"cpf"`
-	Phone        string               `json:"phone"`
-	Gender       enum.GenderEnum      `json:"gender"`
-	Curriculum   string               `json:"curriculum"`
-	Address      AddressResponse      `json:"address"`
-	Disabilities []DisabilityResponse `json:"disabilities"`
+	Id             int                  `json:"id"`
+	Name           string               `json:"name"`
+	Cpf            string               `json:"cpf,omitempty"`
+	Phone          string               `json:"phone"`
+	Gender         enum.GenderEnum      `json:"gender"`
+	BirthDate      *string              `json:"birth_date,omitempty"`
+	Area           string               `json:"area,omitempty"`
+	ConsentGivenAt *time.Time           `json:"consent_given_at,omitempty"`
+	Curriculum     string               `json:"curriculum"`
+	Address        AddressResponse      `json:"address"`
+	Disabilities   []DisabilityResponse `json:"disabilities"`
 }
 
 func (p *Person) ToResponse(user User) PersonResponse {
@@ -61,35 +76,78 @@ func (p *Person) ToResponse(user User) PersonResponse {
 		Phone:      p.Phone,
 		Gender:     p.Gender,
 		Curriculum: p.Curriculum,
+		BirthDate:  p.BirthDate,
+		Area:       p.Area,
 		User:       user.ToResponse(),
 	}
 }
 
 func (p *Person) ToCandidateResponse(disabilities []DisabilityResponse, address Address) CandidateResponse {
 	return CandidateResponse{
-		Name:         p.Name,
-		Cpf:          p.Cpf,
-		Phone:        p.Phone,
-		Gender:       p.Gender,
-		Curriculum:   p.Curriculum,
-		Disabilities: disabilities,
-		Address:      address.ToResponse(),
+		Id:             p.Id,
+		Name:           p.Name,
+		Cpf:            p.Cpf,
+		Phone:          p.Phone,
+		Gender:         p.Gender,
+		BirthDate:      p.BirthDate,
+		Area:           p.Area,
+		ConsentGivenAt: p.ConsentGivenAt,
+		Curriculum:     p.Curriculum,
+		Disabilities:   disabilities,
+		Address:        address.ToResponse(),
 	}
 }
 
+// ToModel records the consent time when the request gives the consent, a request
+// without it keeps the consent already given
 func (p *PersonRequest) ToModel(user User) Person {
+	var consentGivenAt *time.Time
+	if p.Consent {
+		now := time.Now()
+		consentGivenAt = &now
+	}
+
 	return Person{
-		Name:   p.Name,
-		Cpf:    p.Cpf,
-		Phone:  p.Phone,
-		Gender: p.Gender,
-		UserId: user.Id,
+		Name:           p.Name,
+		Cpf:            p.Cpf,
+		Phone:          p.Phone,
+		Gender:         p.Gender,
+		BirthDate:      p.BirthDate,
+		Area:           strings.Join(strings.Fields(p.Area), " "),
+		ConsentGivenAt: consentGivenAt,
+		UserId:         user.Id,
 	}
 }
 
+// CandidateDataExport is everything the platform stores about a candidate, returned
+// when the candidate requests their data under the LGPD
+type CandidateDataExport struct {
+	ExportedAt   time.Time                  `json:"exported_at"`
+	Email        string                     `json:"email"`
+	Profile      CandidateResponse          `json:"profile"`
+	Applications []CandidateDataApplication `json:"applications"`
+	Files        []string                   `json:"files"`
+}
+
+type CandidateDataApplication struct {
+	VacancyId       int                     `json:"vacancy_id"`
+	VacancyTitle    string                  `json:"vacancy_title"`
+	CompanyName     string                  `json:"company_name"`
+	Status          enum.VacancyApplyStatus `json:"status"`
+	AppliedAt       time.Time               `json:"applied_at"`
+	StatusUpdatedAt *time.Time              `json:"status_updated_at,omitempty"`
+}
+
+// CandidateFilters narrows the candidate search, empty fields don't filter
+type CandidateFilters struct {
+	DisabilityCategory string
+	Area               string
+}
+
 func (p *PersonRequest) ToUser() User {
 	return User{
 		Email:    p.User.Email,
 		Password: p.User.Password,
+		RoleId:   PersonRole,
 	}
 }
@@ -1,8 +1,9 @@
 package model
 
 type Field struct {
-	Name  string `json:"name"`
-	Value string `json:"value,omitempty"`
+	Name    string `json:"field"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 type Response struct {
@@ -18,3 +19,30 @@ type LoginResponse struct {
 	UserInfo interface{} `json:"user_info,omitempty"`
 	Message  string      `json:"message,omitempty"`
 }
+
+type PaginatedResponse[T any] struct {
+	Items      []T `json:"items"`
+	Total      int `json:"total"`
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	TotalPages int `json:"total_pages"`
+}
+
+func NewPaginatedResponse[T any](items []T, total int, page int, perPage int) PaginatedResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	totalPages := 0
+	if perPage > 0 {
+		totalPages = (total + perPage - 1) / perPage
+	}
+
+	return PaginatedResponse[T]{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+	}
+}
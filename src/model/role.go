@@ -1,5 +1,7 @@
 package model
 
+import "cij_api/src/enum"
+
 type Role struct {
 	Id   int    `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
 	Name string `gorm:"type:varchar(200);not null;unique" json:"name"`
@@ -12,3 +14,27 @@ const (
 	CompanyRole RoleId = 2
 	AdminRole   RoleId = 3
 )
+
+func (r RoleId) UserRole() enum.UserRole {
+	switch r {
+	case PersonRole:
+		return enum.CandidateRole
+	case CompanyRole:
+		return enum.CompanyRole
+	case AdminRole:
+		return enum.AdminRole
+	}
+	return ""
+}
+
+func RoleIdFromUserRole(role enum.UserRole) RoleId {
+	switch role {
+	case enum.CandidateRole:
+		return PersonRole
+	case enum.CompanyRole:
+		return CompanyRole
+	case enum.AdminRole:
+		return AdminRole
+	}
+	return 0
+}
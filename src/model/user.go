@@ -1,6 +1,8 @@
 package model
 
 import (
+	"cij_api/src/enum"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -12,7 +14,10 @@ type User struct {
 	Password  string `gorm:"type:varchar(255);not null" json:"password"`
 	ConfigUrl string `gorm:"type:varchar(255);not null" json:"config_url"`
 	RoleId    RoleId `gorm:"type:int;not null" json:"role_id"`
-	Role      *Role
+	// Active is false for the deactivated accounts, which can't log in or use the tokens
+	// already issued and whose vacancies are hidden from the listings
+	Active bool `gorm:"not null;default:true" json:"active"`
+	Role   *Role
 }
 
 type UserRequest struct {
@@ -20,19 +25,55 @@ type UserRequest struct {
 	Password string `json:"password"`
 }
 
+// UserUpdateRequest only applies the fields that are set
+type UserUpdateRequest struct {
+	Email    *string `json:"email"`
+	Password *string `json:"password"`
+}
+
+// UserFilters narrows the users listing, empty fields don't filter
+type UserFilters struct {
+	Role  enum.UserRole
+	Email string
+}
+
 type UserResponse struct {
-	Id     int         `json:"id"`
-	Email  string      `json:"email"`
-	Config interface{} `json:"config,omitempty"`
+	Id     int           `json:"id"`
+	Email  string        `json:"email"`
+	Role   enum.UserRole `json:"role,omitempty"`
+	Active bool          `json:"active"`
+	Config interface{}   `json:"config,omitempty"`
+}
+
+// Validate checks the required fields, the email format and the password strength
+// are checked by utils.ValidateUser
+func (u *UserRequest) Validate() ValidationError {
+	validation := ValidationError{}
+
+	if u.Email == "" {
+		validation.Add("email", "01", "email is required")
+	}
+
+	if u.Password == "" {
+		validation.Add("password", "01", "password is required")
+	}
+
+	return validation
 }
 
 func (u *User) ValidatePassword(password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil
 }
 
+func (u *User) HasRole(role enum.UserRole) bool {
+	return u.RoleId.UserRole() == role
+}
+
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		Id:    u.Id,
-		Email: u.Email,
+		Id:     u.Id,
+		Email:  u.Email,
+		Role:   u.RoleId.UserRole(),
+		Active: u.Active,
 	}
 }
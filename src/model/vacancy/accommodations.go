@@ -0,0 +1,52 @@
+package model
+
+import (
+	"cij_api/src/utils"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type VacancyAccommodation struct {
+	*gorm.Model
+	Id            int    `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	Accommodation string `gorm:"type:varchar(100);not null;index" json:"accommodation"`
+	VacancyId     int    `gorm:"type:int;not null" json:"vacancy_id"`
+	Vacancy       *Vacancy
+}
+
+// NormalizeAccommodation turns an accommodation into its stored tag, like
+// "Wheelchair  access" into "wheelchair_access"
+func NormalizeAccommodation(accommodation string) string {
+	accommodation = strings.ToLower(utils.CollapseSpaces(accommodation))
+
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(accommodation)
+}
+
+// NormalizeAccommodations normalizes the accommodations, dropping the empty and the
+// repeated ones while keeping the order
+func NormalizeAccommodations(accommodations []string) []string {
+	normalized := []string{}
+
+	for _, accommodation := range accommodations {
+		accommodation = NormalizeAccommodation(accommodation)
+		if accommodation == "" || slices.Contains(normalized, accommodation) {
+			continue
+		}
+
+		normalized = append(normalized, accommodation)
+	}
+
+	return normalized
+}
+
+func accommodationsToResponse(accommodations []VacancyAccommodation) []string {
+	response := []string{}
+
+	for _, accommodation := range accommodations {
+		response = append(response, accommodation.Accommodation)
+	}
+
+	return response
+}
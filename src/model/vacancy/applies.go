@@ -3,15 +3,18 @@ package model
 import (
 	"cij_api/src/enum"
 	"cij_api/src/model"
+	"time"
 )
 
 type VacancyApply struct {
-	Id          int                     `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
-	VacancyId   int                     `gorm:"type:int;not null" json:"vacancy_id"`
-	CandidateId int                     `gorm:"type:int;not null" json:"candidate_id"`
-	Status      enum.VacancyApplyStatus `gorm:"type:varchar(10);not null" json:"status"`
-	Vacancy     *Vacancy
-	Candidate   *model.Person
+	Id              int                     `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	VacancyId       int                     `gorm:"type:int;not null;uniqueIndex:idx_vacancy_apply_candidate" json:"vacancy_id"`
+	CandidateId     int                     `gorm:"type:int;not null;uniqueIndex:idx_vacancy_apply_candidate" json:"candidate_id"`
+	Status          enum.VacancyApplyStatus `gorm:"type:varchar(20);not null" json:"status"`
+	CreatedAt       time.Time               `gorm:"autoCreateTime" json:"created_at"`
+	StatusUpdatedAt *time.Time              `json:"status_updated_at"`
+	Vacancy         *Vacancy
+	Candidate       *model.Person
 }
 
 type VacancyApplyRequest struct {
@@ -20,9 +23,48 @@ type VacancyApplyRequest struct {
 }
 
 type VacancyApplyResponse struct {
-	Id        int                     `json:"id"`
-	Candidate model.CandidateResponse `json:"candidate"`
-	Status    enum.VacancyApplyStatus `json:"status"`
+	Id              int                     `json:"id"`
+	Candidate       model.CandidateResponse `json:"candidate"`
+	Status          enum.VacancyApplyStatus `json:"status"`
+	CreatedAt       time.Time               `json:"created_at"`
+	StatusUpdatedAt *time.Time              `json:"status_updated_at,omitempty"`
+}
+
+type CandidateApplyResponse struct {
+	Id              int                     `json:"id"`
+	Vacancy         VacancySimpleResponse   `json:"vacancy"`
+	Status          enum.VacancyApplyStatus `json:"status"`
+	CreatedAt       time.Time               `json:"created_at"`
+	StatusUpdatedAt *time.Time              `json:"status_updated_at,omitempty"`
+}
+
+// CandidateApplication is a row of the candidate application history
+type CandidateApplication struct {
+	Id           int                     `json:"id"`
+	VacancyId    int                     `json:"vacancy_id"`
+	VacancyTitle string                  `json:"vacancy_title"`
+	CompanyName  string                  `json:"company_name"`
+	Status       enum.VacancyApplyStatus `json:"status"`
+	AppliedAt    time.Time               `json:"applied_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+type VacancyApplyBulkStatusRequest struct {
+	Ids    []int                   `json:"ids"`
+	Status enum.VacancyApplyStatus `json:"status"`
+}
+
+// VacancyApplyBulkStatusResult lists the applies updated by a bulk status update and
+// why each of the others failed
+type VacancyApplyBulkStatusResult struct {
+	Updated []int                           `json:"updated"`
+	Failed  []VacancyApplyBulkStatusFailure `json:"failed"`
+}
+
+type VacancyApplyBulkStatusFailure struct {
+	Id      int    `json:"id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func (v *VacancyApplyRequest) ToModel() *VacancyApply {
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"cij_api/src/enum"
+	"time"
+)
+
+type VacancyFilters struct {
+	CompanyId    int
+	DisabilityId int
+	// matches vacancies covering any of the categories
+	DisabilityCategories []string
+	// matches vacancies offering all the normalized accommodations
+	Accommodations []string
+	CandidateId    int
+	Area           string
+	City           string
+	State          string
+	ContractType   enum.VacancyContractType
+	WorkMode       enum.VacancyWorkMode
+	SearchText     string
+	SalaryMin      *float64
+	SalaryMax      *float64
+	SortBy         enum.VacancySortBy
+	SortOrder      enum.SortOrderEnum
+	IncludeDeleted bool
+	// closed and expired vacancies are hidden unless set
+	IncludeExpired bool
+	// drafts are hidden unless set, only for the owning company listing
+	IncludeDrafts bool
+	// only vacancies covering a disability category of the candidate
+	EligibleCandidateId int
+	// hides the vacancies the candidate already applied to
+	ExcludeAppliedCandidateId int
+	// only vacancies published after it, used by the job alerts
+	PublishedAfter *time.Time
+	// posted date window, both ends inclusive
+	PostedAfter  *time.Time
+	PostedBefore *time.Time
+	// only vacancies within RadiusKm of the point, vacancies without coordinates are
+	// left out while it's set
+	Latitude  *float64
+	Longitude *float64
+	RadiusKm  float64
+	// only vacancies of verified companies
+	VerifiedOnly bool
+}
+
+// VacancyStatsBucket counts the vacancies created and closed in the period starting at
+// the date, weeks start on monday
+type VacancyStatsBucket struct {
+	Period  string `json:"period"`
+	Created int    `json:"created"`
+	Closed  int    `json:"closed"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type VacancyIdempotencyKey struct {
+	Id        int       `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	Key       string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"key"`
+	VacancyId int       `gorm:"type:int;not null" json:"vacancy_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	Vacancy   *Vacancy
+}
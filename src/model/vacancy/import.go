@@ -0,0 +1,17 @@
+package model
+
+type ImportRowError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+type ImportResult struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+func (i *ImportResult) AddError(line int, reason string) {
+	i.Failed++
+	i.Errors = append(i.Errors, ImportRowError{Line: line, Reason: reason})
+}
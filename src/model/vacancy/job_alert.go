@@ -0,0 +1,81 @@
+package model
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JobAlert struct {
+	*gorm.Model
+	Id                 int                      `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
+	CandidateId        int                      `gorm:"type:int;not null;index" json:"candidate_id"`
+	DisabilityCategory string                   `gorm:"type:varchar(200)" json:"disability_category"`
+	Area               string                   `gorm:"type:varchar(200)" json:"area"`
+	ContractType       enum.VacancyContractType `gorm:"type:varchar(200)" json:"contract_type"`
+	SearchText         string                   `gorm:"type:varchar(200)" json:"search_text"`
+	Paused             bool                     `gorm:"not null;default:false" json:"paused"`
+	LastRunAt          *time.Time               `json:"last_run_at"`
+	Candidate          *model.Person
+}
+
+type JobAlertRequest struct {
+	CandidateId        int                      `json:"candidate_id"`
+	DisabilityCategory string                   `json:"disability_category"`
+	Area               string                   `json:"area"`
+	ContractType       enum.VacancyContractType `json:"contract_type"`
+	SearchText         string                   `json:"search_text"`
+}
+
+type JobAlertResponse struct {
+	Id                 int                      `json:"id"`
+	CandidateId        int                      `json:"candidate_id"`
+	DisabilityCategory string                   `json:"disability_category,omitempty"`
+	Area               string                   `json:"area,omitempty"`
+	ContractType       enum.VacancyContractType `json:"contract_type,omitempty"`
+	SearchText         string                   `json:"search_text,omitempty"`
+	Paused             bool                     `json:"paused"`
+	LastRunAt          *time.Time               `json:"last_run_at,omitempty"`
+}
+
+func (j *JobAlertRequest) ToModel() *JobAlert {
+	return &JobAlert{
+		CandidateId:        j.CandidateId,
+		DisabilityCategory: j.DisabilityCategory,
+		Area:               j.Area,
+		ContractType:       j.ContractType,
+		SearchText:         j.SearchText,
+	}
+}
+
+func (j *JobAlert) ToResponse() JobAlertResponse {
+	return JobAlertResponse{
+		Id:                 j.Id,
+		CandidateId:        j.CandidateId,
+		DisabilityCategory: j.DisabilityCategory,
+		Area:               j.Area,
+		ContractType:       j.ContractType,
+		SearchText:         j.SearchText,
+		Paused:             j.Paused,
+		LastRunAt:          j.LastRunAt,
+	}
+}
+
+// ToFilters returns the listing filters of the alert, matching the vacancies published
+// after since, so a draft published later is still alerted
+func (j *JobAlert) ToFilters(since time.Time) VacancyFilters {
+	filters := VacancyFilters{
+		Area:           j.Area,
+		ContractType:   j.ContractType,
+		SearchText:     j.SearchText,
+		PublishedAfter: &since,
+	}
+
+	if j.DisabilityCategory != "" {
+		filters.DisabilityCategories = []string{j.DisabilityCategory}
+	}
+
+	return filters
+}
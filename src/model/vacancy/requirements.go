@@ -4,6 +4,7 @@ import (
 	"gorm.io/gorm"
 
 	"cij_api/src/enum"
+	"cij_api/src/utils"
 )
 
 type VacancyRequirement struct {
@@ -16,24 +17,29 @@ type VacancyRequirement struct {
 }
 
 type VacancyRequirementResponse struct {
+	Id          int                         `json:"id"`
 	Requirement string                      `json:"requirement"`
 	Type        enum.VacancyRequirementType `json:"type"`
 }
 
+// VacancyRequirementRequest is matched by id when updating the vacancy, a requirement
+// without an id is a new one
 type VacancyRequirementRequest struct {
+	Id          int                         `json:"id,omitempty"`
 	Requirement string                      `json:"requirement"`
 	Type        enum.VacancyRequirementType `json:"type"`
 }
 
 func (v *VacancyRequirementRequest) ToModel() *VacancyRequirement {
 	return &VacancyRequirement{
-		Requirement: v.Requirement,
+		Requirement: utils.CollapseSpaces(v.Requirement),
 		Type:        v.Type,
 	}
 }
 
 func (v *VacancyRequirement) ToResponse() *VacancyRequirementResponse {
 	return &VacancyRequirementResponse{
+		Id:          v.Id,
 		Requirement: v.Requirement,
 		Type:        v.Type,
 	}
@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"cij_api/src/utils"
+
+	"gorm.io/gorm"
+)
 
 type VacancyResponsability struct {
 	*gorm.Model
@@ -16,7 +20,7 @@ type VacancyResponsabilityRequest string
 
 func (v *VacancyResponsabilityRequest) ToModel() *VacancyResponsability {
 	return &VacancyResponsability{
-		Responsability: string(*v),
+		Responsability: utils.CollapseSpaces(string(*v)),
 	}
 }
 
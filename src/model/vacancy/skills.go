@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"cij_api/src/utils"
+
+	"gorm.io/gorm"
+)
 
 type VacancySkill struct {
 	*gorm.Model
@@ -16,7 +20,7 @@ type VacancySkillRequest string
 
 func (v *VacancySkillRequest) ToModel() *VacancySkill {
 	return &VacancySkill{
-		Skill: string(*v),
+		Skill: utils.CollapseSpaces(string(*v)),
 	}
 }
 
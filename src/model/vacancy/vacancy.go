@@ -3,6 +3,13 @@ package model
 import (
 	"cij_api/src/enum"
 	"cij_api/src/model"
+	"cij_api/src/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -11,6 +18,7 @@ type Vacancy struct {
 	*gorm.Model
 	Id               int                      `gorm:"type:int;primaryKey;autoIncrement;not null" json:"id"`
 	Code             string                   `gorm:"type:varchar(200);not null" json:"code"`
+	Slug             *string                  `gorm:"type:varchar(255);uniqueIndex" json:"slug"`
 	Title            string                   `gorm:"type:varchar(200);not null" json:"title"`
 	Description      string                   `gorm:"type:text;not null" json:"description"`
 	Department       string                   `gorm:"type:varchar(200);not null" json:"department"`
@@ -19,15 +27,38 @@ type Vacancy struct {
 	PublishDate      string                   `gorm:"type:date;not null" json:"publish_date"`
 	RegistrationDate string                   `gorm:"type:date;not null" json:"registration_date"`
 	Area             string                   `gorm:"type:varchar(200);not null" json:"area"`
+	City             string                   `gorm:"type:varchar(200)" json:"city"`
+	State            string                   `gorm:"type:char(2)" json:"state"`
+	Latitude         *float64                 `gorm:"type:decimal(9,6);index:idx_vacancies_coordinates" json:"latitude"`
+	Longitude        *float64                 `gorm:"type:decimal(9,6);index:idx_vacancies_coordinates" json:"longitude"`
 	CompanyId        int                      `gorm:"type:int;not null" json:"company_id"`
 	ContractType     enum.VacancyContractType `gorm:"type:varchar(200);not null" json:"contract_type"`
-	Disabilities     []model.Disability       `gorm:"many2many:vacancy_disabilities" json:"disabilities"`
+	WorkMode         enum.VacancyWorkMode     `gorm:"type:varchar(20);not null;default:onsite" json:"work_mode"`
+	SalaryMin        *float64                 `gorm:"type:decimal(10,2)" json:"salary_min"`
+	SalaryMax        *float64                 `gorm:"type:decimal(10,2)" json:"salary_max"`
+	ExpiresAt        *string                  `gorm:"type:date" json:"expires_at"`
+	ClosedAt         *time.Time               `json:"closed_at"`
+	// PublishedAt is when the vacancy left the draft state, the job alerts look for the
+	// vacancies published since their last run
+	PublishedAt      *time.Time              `gorm:"index" json:"published_at"`
+	Featured         bool                    `gorm:"not null;default:false" json:"featured"`
+	FeaturedUntil    *time.Time              `json:"featured_until"`
+	ViewCount        int                     `gorm:"type:int;not null;default:0" json:"view_count"`
+	Version          int                     `gorm:"type:int;not null;default:1" json:"version"`
+	Status           enum.VacancyStatus      `gorm:"type:varchar(20);not null;default:published" json:"status"`
+	AppliesCount     int                     `gorm:"->;-:migration" json:"-"`
+	Disabilities     []model.Disability      `gorm:"many2many:vacancy_disabilities" json:"disabilities"`
+	Skills           []VacancySkill          `gorm:"foreignKey:VacancyId" json:"skills,omitempty"`
+	Requirements     []VacancyRequirement    `gorm:"foreignKey:VacancyId" json:"requirements,omitempty"`
+	Responsabilities []VacancyResponsability `gorm:"foreignKey:VacancyId" json:"responsabilities,omitempty"`
+	Accommodations   []VacancyAccommodation  `gorm:"foreignKey:VacancyId" json:"accommodations,omitempty"`
 	Company          model.Company
 }
 
 type VacancyResponse struct {
 	Id                      int                             `json:"id"`
 	Code                    string                          `json:"code"`
+	Slug                    string                          `json:"slug,omitempty"`
 	Title                   string                          `json:"title"`
 	Description             string                          `json:"description"`
 	Department              string                          `json:"department"`
@@ -36,23 +67,63 @@ type VacancyResponse struct {
 	PublishDate             string                          `json:"publish_date"`
 	RegistrationDate        string                          `json:"registration_date"`
 	Area                    string                          `json:"area"`
+	City                    string                          `json:"city,omitempty"`
+	State                   string                          `json:"state,omitempty"`
+	Latitude                *float64                        `json:"latitude,omitempty"`
+	Longitude               *float64                        `json:"longitude,omitempty"`
 	CandidateAlreadyApplied bool                            `json:"candidate_already_applied,omitempty"`
+	AppliesCount            int                             `json:"applies_count"`
+	ViewCount               int                             `json:"view_count"`
 	ContractType            enum.VacancyContractType        `json:"contract_type"`
+	WorkMode                enum.VacancyWorkMode            `json:"work_mode"`
+	SalaryMin               *float64                        `json:"salary_min"`
+	SalaryMax               *float64                        `json:"salary_max"`
+	ExpiresAt               *string                         `json:"expires_at,omitempty"`
+	ClosedAt                *time.Time                      `json:"closed_at,omitempty"`
+	Featured                bool                            `json:"featured"`
+	FeaturedUntil           *time.Time                      `json:"featured_until,omitempty"`
+	Version                 int                             `json:"version"`
+	Status                  enum.VacancyStatus              `json:"status"`
 	Company                 string                          `json:"company"`
+	CompanyVerified         bool                            `json:"company_verified"`
 	Disabilities            []model.DisabilityResponse      `json:"disabilities"`
 	Skills                  []VacancySkillResponse          `json:"skills"`
 	Responsabilities        []VacancyResponsabilityResponse `json:"responsabilities"`
 	Requirements            []VacancyRequirementResponse    `json:"requirements"`
+	Accommodations          []string                        `json:"accommodations"`
+	CreatedAt               time.Time                       `json:"created_at"`
+	UpdatedAt               time.Time                       `json:"updated_at"`
 }
 
 type VacancySimpleResponse struct {
-	Id           int                        `json:"id"`
-	Code         string                     `json:"code"`
-	Title        string                     `json:"title"`
-	Area         string                     `json:"area"`
-	Company      string                     `json:"company"`
-	ContractType enum.VacancyContractType   `json:"contract_type"`
-	Disabilities []model.DisabilityResponse `json:"disabilities"`
+	Id              int                        `json:"id"`
+	Code            string                     `json:"code"`
+	Slug            string                     `json:"slug,omitempty"`
+	Title           string                     `json:"title"`
+	Area            string                     `json:"area"`
+	City            string                     `json:"city,omitempty"`
+	State           string                     `json:"state,omitempty"`
+	Latitude        *float64                   `json:"latitude,omitempty"`
+	Longitude       *float64                   `json:"longitude,omitempty"`
+	Company         string                     `json:"company"`
+	CompanyVerified bool                       `json:"company_verified"`
+	ContractType    enum.VacancyContractType   `json:"contract_type"`
+	WorkMode        enum.VacancyWorkMode       `json:"work_mode"`
+	SalaryMin       *float64                   `json:"salary_min"`
+	SalaryMax       *float64                   `json:"salary_max"`
+	ExpiresAt       *string                    `json:"expires_at,omitempty"`
+	ClosedAt        *time.Time                 `json:"closed_at,omitempty"`
+	Featured        bool                       `json:"featured"`
+	FeaturedUntil   *time.Time                 `json:"featured_until,omitempty"`
+	Version         int                        `json:"version"`
+	Status          enum.VacancyStatus         `json:"status"`
+	AppliesCount    int                        `json:"applies_count,omitempty"`
+	Disabilities    []model.DisabilityResponse `json:"disabilities"`
+	DisabilityCount map[string]int             `json:"disability_count"`
+	Accommodations  []string                   `json:"accommodations"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	UpdatedAt       time.Time                  `json:"updated_at"`
+	DeletedAt       *time.Time                 `json:"deleted_at,omitempty"`
 }
 
 type VacancyRequest struct {
@@ -65,15 +136,192 @@ type VacancyRequest struct {
 	PublishDate      string                         `json:"publish_date"`
 	RegistrationDate string                         `json:"registration_date"`
 	Area             string                         `json:"area"`
+	City             string                         `json:"city"`
+	State            string                         `json:"state"`
+	Latitude         *float64                       `json:"latitude"`
+	Longitude        *float64                       `json:"longitude"`
 	CompanyId        int                            `json:"company_id"`
 	ContractType     enum.VacancyContractType       `json:"contract_type"`
+	WorkMode         enum.VacancyWorkMode           `json:"work_mode"`
+	SalaryMin        *float64                       `json:"salary_min"`
+	SalaryMax        *float64                       `json:"salary_max"`
+	ExpiresAt        *string                        `json:"expires_at"`
+	Version          int                            `json:"version"`
+	Publish          bool                           `json:"publish"`
+	AllowDuplicate   bool                           `json:"allow_duplicate"`
 	Disabilities     []VacancyDisabilityRequest     `json:"disabilities"`
 	Skills           []VacancySkillRequest          `json:"skills"`
 	Responsabilities []VacancyResponsabilityRequest `json:"responsabilities"`
 	Requirements     []VacancyRequirementRequest    `json:"requirements"`
+	Accommodations   []string                       `json:"accommodations"`
+}
+
+// CompanyVacanciesResponse is a company of the directory with its newest open vacancies
+type CompanyVacanciesResponse struct {
+	Id             int                     `json:"id"`
+	Name           string                  `json:"name"`
+	LogoUrl        string                  `json:"logo_url,omitempty"`
+	Verified       bool                    `json:"verified"`
+	TotalVacancies int                     `json:"total_vacancies"`
+	Vacancies      []VacancySimpleResponse `json:"vacancies"`
+}
+
+type VacancyTransferRequest struct {
+	ToCompanyId int `json:"to_company_id"`
+}
+
+type VacancyReopenRequest struct {
+	ExpiresAt *string `json:"expires_at"`
+}
+
+// VacancyFeatureRequest promotes the vacancy of a partner company, a nil featured
+// until keeps it featured until it is unset
+type VacancyFeatureRequest struct {
+	Featured      bool       `json:"featured"`
+	FeaturedUntil *time.Time `json:"featured_until"`
+}
+
+func (v *VacancyFeatureRequest) Validate() utils.Error {
+	if v.FeaturedUntil != nil && !v.FeaturedUntil.After(time.Now()) {
+		return vacancyValidationError("featured until must be in the future", "20", "featured_until")
+	}
+
+	return utils.Error{}
+}
+
+func vacancyValidationError(message string, code string, field string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, code)
+
+	return utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: field, Message: message}})
+}
+
+// Validate collects every invalid field of the vacancy, so they are all reported at once
+func (v *VacancyRequest) Validate() model.ValidationError {
+	validation := model.ValidationError{}
+
+	if strings.TrimSpace(v.Title) == "" {
+		validation.Add("title", "01", "title is required")
+	}
+
+	if len(v.Disabilities) == 0 {
+		validation.Add("disabilities", "02", "at least one disability is required")
+	}
+
+	if !v.ContractType.IsValid() {
+		validation.Add("contract_type", "03", "invalid contract type. valid values are: 'clt', 'pj', 'trainee'")
+	}
+
+	if v.SalaryMin != nil && *v.SalaryMin < 0 {
+		validation.Add("salary_min", "04", "salary min must not be negative")
+	}
+
+	if v.SalaryMax != nil && *v.SalaryMax < 0 {
+		validation.Add("salary_max", "05", "salary max must not be negative")
+	}
+
+	if v.WorkMode != "" && !v.WorkMode.IsValid() {
+		validation.Add("work_mode", "07", "invalid work mode. valid values are: 'onsite', 'remote', 'hybrid'")
+	}
+
+	for _, accommodation := range v.Accommodations {
+		if len(NormalizeAccommodation(accommodation)) > 100 {
+			validation.Add("accommodations", "17", "accommodations must have at most 100 characters")
+			break
+		}
+	}
+
+	if (v.Latitude == nil) != (v.Longitude == nil) {
+		validation.Add("latitude", "14", "latitude and longitude must be set together")
+	}
+
+	if v.Latitude != nil && (*v.Latitude < -90 || *v.Latitude > 90) {
+		validation.Add("latitude", "15", "latitude must be between -90 and 90")
+	}
+
+	if v.Longitude != nil && (*v.Longitude < -180 || *v.Longitude > 180) {
+		validation.Add("longitude", "16", "longitude must be between -180 and 180")
+	}
+
+	return validation
 }
 
 func (v *VacancyRequest) ToModel() *Vacancy {
+	return &Vacancy{
+		Code:             v.Code,
+		Title:            utils.CollapseSpaces(v.Title),
+		Description:      v.Description,
+		Department:       v.Department,
+		Section:          v.Section,
+		Turn:             v.Turn,
+		PublishDate:      v.PublishDate,
+		RegistrationDate: v.RegistrationDate,
+		Area:             utils.CollapseSpaces(v.Area),
+		City:             utils.CollapseSpaces(v.City),
+		State:            strings.ToUpper(strings.TrimSpace(v.State)),
+		Latitude:         v.Latitude,
+		Longitude:        v.Longitude,
+		ContractType:     v.ContractType,
+		WorkMode:         v.WorkMode,
+		SalaryMin:        v.SalaryMin,
+		SalaryMax:        v.SalaryMax,
+		ExpiresAt:        v.ExpiresAt,
+		CompanyId:        v.CompanyId,
+	}
+}
+
+func auditValue[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
+
+// AuditFields are the fields of the vacancy recorded in the audit log
+func (v *Vacancy) AuditFields() map[string]any {
+	return map[string]any{
+		"code":              v.Code,
+		"title":             v.Title,
+		"description":       v.Description,
+		"department":        v.Department,
+		"section":           v.Section,
+		"turn":              v.Turn,
+		"publish_date":      v.PublishDate,
+		"registration_date": v.RegistrationDate,
+		"area":              v.Area,
+		"city":              v.City,
+		"state":             v.State,
+		"latitude":          auditValue(v.Latitude),
+		"longitude":         auditValue(v.Longitude),
+		"company_id":        v.CompanyId,
+		"contract_type":     v.ContractType,
+		"work_mode":         v.WorkMode,
+		"salary_min":        auditValue(v.SalaryMin),
+		"salary_max":        auditValue(v.SalaryMax),
+		"expires_at":        auditValue(v.ExpiresAt),
+		"status":            v.Status,
+		"featured":          v.Featured,
+		"featured_until":    auditValue(v.FeaturedUntil),
+	}
+}
+
+// AuditFieldsAfterUpdate are the audit fields once the update is applied. Like the
+// gorm struct updates, the zero fields of the update keep their current value
+func (v *Vacancy) AuditFieldsAfterUpdate(update *Vacancy) map[string]any {
+	fields := v.AuditFields()
+
+	for field, value := range update.AuditFields() {
+		if value != nil && !reflect.ValueOf(value).IsZero() {
+			fields[field] = value
+		}
+	}
+
+	return fields
+}
+
+// DraftCopy is a new draft with the fields of the vacancy, leaving out its id, slug,
+// status history and associations
+func (v *Vacancy) DraftCopy() *Vacancy {
 	return &Vacancy{
 		Code:             v.Code,
 		Title:            v.Title,
@@ -84,11 +332,66 @@ func (v *VacancyRequest) ToModel() *Vacancy {
 		PublishDate:      v.PublishDate,
 		RegistrationDate: v.RegistrationDate,
 		Area:             v.Area,
-		ContractType:     v.ContractType,
+		City:             v.City,
+		State:            v.State,
+		Latitude:         v.Latitude,
+		Longitude:        v.Longitude,
 		CompanyId:        v.CompanyId,
+		ContractType:     v.ContractType,
+		WorkMode:         v.WorkMode,
+		SalaryMin:        v.SalaryMin,
+		SalaryMax:        v.SalaryMax,
+		ExpiresAt:        v.ExpiresAt,
+		Status:           enum.VacancyDraft,
 	}
 }
 
+func (v *Vacancy) IsOpen() bool {
+	if v.ClosedAt != nil || (v.Status != "" && v.Status != enum.VacancyPublished) {
+		return false
+	}
+
+	if v.ExpiresAt != nil && *v.ExpiresAt != "" {
+		expiresAt, err := time.Parse("2006-01-02", (*v.ExpiresAt)[:min(len(*v.ExpiresAt), 10)])
+		if err == nil && expiresAt.Before(time.Now().Truncate(24*time.Hour)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *Vacancy) slug() string {
+	if v.Slug == nil {
+		return ""
+	}
+
+	return *v.Slug
+}
+
+func (v *Vacancy) timestamps() (time.Time, time.Time) {
+	if v.Model == nil {
+		return time.Time{}, time.Time{}
+	}
+
+	return v.CreatedAt, v.UpdatedAt
+}
+
+// ETag is a weak validator of the response, hashed from everything but the view count,
+// which grows on every visit without changing what the client shows as the vacancy
+func (v VacancyResponse) ETag() string {
+	v.ViewCount = 0
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(body)
+
+	return `W/"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
 func (v *Vacancy) ToResponse(
 	disabilities []model.DisabilityResponse,
 	skills []VacancySkill,
@@ -111,9 +414,12 @@ func (v *Vacancy) ToResponse(
 		requirementsResponse = append(requirementsResponse, *r.ToResponse())
 	}
 
+	createdAt, updatedAt := v.timestamps()
+
 	return VacancyResponse{
 		Id:               v.Id,
 		Code:             v.Code,
+		Slug:             v.slug(),
 		Title:            v.Title,
 		Description:      v.Description,
 		Department:       v.Department,
@@ -122,23 +428,82 @@ func (v *Vacancy) ToResponse(
 		PublishDate:      v.PublishDate,
 		RegistrationDate: v.RegistrationDate,
 		Area:             v.Area,
+		City:             v.City,
+		State:            v.State,
+		Latitude:         v.Latitude,
+		Longitude:        v.Longitude,
 		ContractType:     v.ContractType,
+		WorkMode:         v.WorkMode,
+		SalaryMin:        v.SalaryMin,
+		SalaryMax:        v.SalaryMax,
+		ExpiresAt:        v.ExpiresAt,
+		ClosedAt:         v.ClosedAt,
+		Featured:         v.Featured,
+		FeaturedUntil:    v.FeaturedUntil,
+		Version:          v.Version,
+		Status:           v.Status,
+		AppliesCount:     v.AppliesCount,
+		ViewCount:        v.ViewCount,
 		Company:          v.Company.Name,
+		CompanyVerified:  v.Company.Verified,
 		Disabilities:     disabilities,
 		Skills:           skillsResponse,
 		Responsabilities: responsabilitiesResponse,
 		Requirements:     requirementsResponse,
+		Accommodations:   accommodationsToResponse(v.Accommodations),
+		CreatedAt:        createdAt,
+		UpdatedAt:        updatedAt,
 	}
 }
 
+// countDisabilitiesByCategory counts how many specific disabilities of each category
+// the vacancy accepts
+func countDisabilitiesByCategory(disabilities []model.DisabilityResponse) map[string]int {
+	counts := map[string]int{}
+
+	for _, disability := range disabilities {
+		counts[disability.Category]++
+	}
+
+	return counts
+}
+
 func (v *Vacancy) ToSimpleResponse(disabilities []model.DisabilityResponse) VacancySimpleResponse {
+	var deletedAt *time.Time
+	if v.Model != nil && v.DeletedAt.Valid {
+		deletedAt = &v.DeletedAt.Time
+	}
+
+	createdAt, updatedAt := v.timestamps()
+
 	return VacancySimpleResponse{
-		Id:           v.Id,
-		Code:         v.Code,
-		Title:        v.Title,
-		Area:         v.Area,
-		Company:      v.Company.Name,
-		ContractType: v.ContractType,
-		Disabilities: disabilities,
+		Id:              v.Id,
+		Code:            v.Code,
+		Slug:            v.slug(),
+		Title:           v.Title,
+		Area:            v.Area,
+		City:            v.City,
+		State:           v.State,
+		Latitude:        v.Latitude,
+		Longitude:       v.Longitude,
+		Company:         v.Company.Name,
+		CompanyVerified: v.Company.Verified,
+		ContractType:    v.ContractType,
+		WorkMode:        v.WorkMode,
+		SalaryMin:       v.SalaryMin,
+		SalaryMax:       v.SalaryMax,
+		ExpiresAt:       v.ExpiresAt,
+		ClosedAt:        v.ClosedAt,
+		Featured:        v.Featured,
+		FeaturedUntil:   v.FeaturedUntil,
+		Version:         v.Version,
+		Status:          v.Status,
+		AppliesCount:    v.AppliesCount,
+		Disabilities:    disabilities,
+		DisabilityCount: countDisabilitiesByCategory(disabilities),
+		Accommodations:  accommodationsToResponse(v.Accommodations),
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+		DeletedAt:       deletedAt,
 	}
 }
@@ -0,0 +1,45 @@
+package model
+
+import "testing"
+
+func TestVacancyRequestToModelCollapsesSpaces(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "untouched", input: "Backend developer", want: "Backend developer"},
+		{name: "leading and trailing spaces", input: "  Backend developer  ", want: "Backend developer"},
+		{name: "repeated spaces", input: "Backend    developer", want: "Backend developer"},
+		{name: "tabs", input: "\tBackend\t\tdeveloper\t", want: "Backend developer"},
+		{name: "newlines", input: "\nBackend\r\ndeveloper\n", want: "Backend developer"},
+		{name: "mixed whitespace", input: " \t Backend \n\t  developer \r\n", want: "Backend developer"},
+		{name: "only whitespace", input: " \t\n ", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := VacancyRequest{Title: test.input, Area: test.input, City: test.input}
+
+			vacancy := request.ToModel()
+
+			if vacancy.Title != test.want {
+				t.Errorf("title: got %q, want %q", vacancy.Title, test.want)
+			}
+
+			if vacancy.Area != test.want {
+				t.Errorf("area: got %q, want %q", vacancy.Area, test.want)
+			}
+
+			if vacancy.City != test.want {
+				t.Errorf("city: got %q, want %q", vacancy.City, test.want)
+			}
+
+			skill := VacancySkillRequest(test.input)
+
+			if got := skill.ToModel().Skill; got != test.want {
+				t.Errorf("skill: got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+// ValidationError collects every invalid field of a request, so they are all reported
+// at once instead of only the first. The codes are the identifiers of the entity error
+// codes, the full codes are built when it becomes an error
+type ValidationError struct {
+	Fields []Field
+}
+
+func (v *ValidationError) Add(field string, code string, message string) {
+	v.Fields = append(v.Fields, Field{Name: field, Code: code, Message: message})
+}
+
+// Merge appends the failures of other, the codes of both must be built the same way
+func (v *ValidationError) Merge(other ValidationError) {
+	v.Fields = append(v.Fields, other.Fields...)
+}
+
+func (v *ValidationError) HasErrors() bool {
+	return len(v.Fields) > 0
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/model"
+	"cij_api/src/utils"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepo interface {
+	BaseRepoMethods
+
+	CreateAuditLog(auditLog model.AuditLog, tx *gorm.DB) utils.Error
+	ListAuditLogs(entity enum.AuditEntity, entityId int) ([]model.AuditLog, utils.Error)
+}
+
+type auditLogRepo struct {
+	BaseRepo
+	db *gorm.DB
+}
+
+func NewAuditLogRepo(db *gorm.DB) AuditLogRepo {
+	repo := &auditLogRepo{
+		db: db,
+	}
+
+	repo.SetRepo(repo.db)
+
+	return repo
+}
+
+func auditLogRepoError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.AuditLogErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (a *auditLogRepo) CreateAuditLog(auditLog model.AuditLog, tx *gorm.DB) utils.Error {
+	databaseConn := a.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Create(&auditLog).Error; err != nil {
+		return auditLogRepoError("failed to create the audit log", "01").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+// ListAuditLogs returns the trail of the entity, most recent first
+func (a *auditLogRepo) ListAuditLogs(entity enum.AuditEntity, entityId int) ([]model.AuditLog, utils.Error) {
+	auditLogs := []model.AuditLog{}
+
+	err := a.db.Where("entity = ? AND entity_id = ?", entity, entityId).
+		Order("timestamp DESC, id DESC").
+		Find(&auditLogs).Error
+	if err != nil {
+		return []model.AuditLog{}, auditLogRepoError("failed to list the audit logs", "02").WithCause(err)
+	}
+
+	return auditLogs, utils.Error{}
+}
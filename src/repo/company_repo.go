@@ -3,19 +3,25 @@ package repo
 import (
 	"cij_api/src/model"
 	"cij_api/src/utils"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CompanyRepo interface {
 	BaseRepoMethods
 
 	CreateCompany(createCompany model.Company, tx *gorm.DB) utils.Error
-	ListCompanies() ([]model.Company, utils.Error)
+	ListCompanies(page int, perPage int) ([]model.Company, utils.Error)
 	GetCompanyById(companyId int) (model.Company, utils.Error)
+	GetCompanyByIdForUpdate(companyId int, tx *gorm.DB) (model.Company, utils.Error)
 	GetCompanyByUserId(userId int) (model.Company, utils.Error)
 	GetCompanyByCnpj(cnpj string) (model.Company, utils.Error)
 	UpdateCompany(company model.Company, companyId int) utils.Error
+	UpdateCompanyLogo(companyId int, logoUrl string) utils.Error
+	SetCompanyVerified(companyId int, verified bool) utils.Error
 	DeleteCompany(companyId int) utils.Error
 }
 
@@ -34,6 +40,10 @@ func NewCompanyRepo(db *gorm.DB) CompanyRepo {
 	return repo
 }
 
+// CnpjAlreadyRegisteredErrorCode is the code CreateCompany returns when the cnpj belongs
+// to another company
+var CnpjAlreadyRegisteredErrorCode = utils.NewErrorCode(utils.DatabaseErrorCode, utils.CompanyErrorType, "09")
+
 func companyRepoError(message string, code string) utils.Error {
 	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.CompanyErrorType, code)
 
@@ -48,16 +58,28 @@ func (n *companyRepo) CreateCompany(createCompany model.Company, tx *gorm.DB) ut
 	}
 
 	if err := databaseConn.Create(&createCompany).Error; err != nil {
+		// the user and address are created in the same transaction, so the only unique
+		// column that can collide is the cnpj
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.NewError("cnpj already registered", CnpjAlreadyRegisteredErrorCode)
+		}
+
 		return companyRepoError("failed to create the company", "01")
 	}
 
 	return utils.Error{}
 }
 
-func (n *companyRepo) ListCompanies() ([]model.Company, utils.Error) {
+func (n *companyRepo) ListCompanies(page int, perPage int) ([]model.Company, utils.Error) {
 	var companies []model.Company
 
-	err := n.db.Model(model.Company{}).Preload("User").Preload("Address").Find(&companies).Error
+	query := n.db.Model(model.Company{}).Preload("User").Preload("Address").Order("id")
+
+	if perPage > 0 {
+		query = query.Offset((page - 1) * perPage).Limit(perPage)
+	}
+
+	err := query.Find(&companies).Error
 	if err != nil {
 		return companies, companyRepoError("failed to list the companies", "02")
 	}
@@ -76,6 +98,25 @@ func (n *companyRepo) GetCompanyById(companyId int) (model.Company, utils.Error)
 	return company, utils.Error{}
 }
 
+// GetCompanyByIdForUpdate locks the company row until the transaction ends, so the
+// checks made on its vacancies can't interleave with another request of the company
+func (n *companyRepo) GetCompanyByIdForUpdate(companyId int, tx *gorm.DB) (model.Company, utils.Error) {
+	var company model.Company
+
+	databaseConn := n.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	err := databaseConn.Model(model.Company{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", companyId).Find(&company).Error
+	if err != nil {
+		return company, companyRepoError("failed to get the company", "11").WithCause(err)
+	}
+
+	return company, utils.Error{}
+}
+
 func (n *companyRepo) GetCompanyByUserId(userId int) (model.Company, utils.Error) {
 	var company model.Company
 
@@ -95,6 +136,34 @@ func (n *companyRepo) UpdateCompany(company model.Company, companyId int) utils.
 	return utils.Error{}
 }
 
+func (n *companyRepo) UpdateCompanyLogo(companyId int, logoUrl string) utils.Error {
+	if err := n.db.Model(model.Company{}).Where("id = ?", companyId).Update("logo_url", logoUrl).Error; err != nil {
+		return companyRepoError("failed to update the company logo", "08")
+	}
+
+	return utils.Error{}
+}
+
+// SetCompanyVerified updates the flag with a map, a struct update would skip the false
+// value. The verification time is cleared when the company is unverified
+func (n *companyRepo) SetCompanyVerified(companyId int, verified bool) utils.Error {
+	var verifiedAt *time.Time
+	if verified {
+		now := time.Now()
+		verifiedAt = &now
+	}
+
+	err := n.db.Model(model.Company{}).Where("id = ?", companyId).Updates(map[string]interface{}{
+		"verified":    verified,
+		"verified_at": verifiedAt,
+	}).Error
+	if err != nil {
+		return companyRepoError("failed to update the company verification", "10")
+	}
+
+	return utils.Error{}
+}
+
 func (n *companyRepo) DeleteCompany(companyId int) utils.Error {
 	if err := n.db.Model(model.Company{}).Where("id = ?", companyId).Unscoped().Delete(&model.Company{}).Error; err != nil {
 		return companyRepoError("failed to delete the company", "06")
@@ -103,10 +172,11 @@ func (n *companyRepo) DeleteCompany(companyId int) utils.Error {
 	return utils.Error{}
 }
 
+// GetCompanyByCnpj matches the cnpj digits, so formatted input finds the company too
 func (n *companyRepo) GetCompanyByCnpj(cnpj string) (model.Company, utils.Error) {
 	var company model.Company
 
-	err := n.db.Model(model.Company{}).Preload("User").Preload("Address").Where("cnpj = ?", cnpj).Find(&company).Error
+	err := n.db.Model(model.Company{}).Preload("User").Preload("Address").Where("cnpj = ?", utils.NormalizeCnpj(cnpj)).Find(&company).Error
 	if err != nil {
 		return company, companyRepoError("failed to get the company", "07")
 	}
@@ -12,6 +12,8 @@ type DisabilityRepo interface {
 	BaseRepoMethods
 
 	BatchInsertDisabilities(disabilities []*model.Disability) utils.Error
+	ListDisabilities() ([]model.Disability, utils.Error)
+	ListExistingDisabilityIds(ids []int) ([]int, utils.Error)
 }
 
 type disabilityRepo struct {
@@ -43,3 +45,28 @@ func (d *disabilityRepo) BatchInsertDisabilities(disabilities []*model.Disabilit
 
 	return utils.Error{}
 }
+
+func (d *disabilityRepo) ListDisabilities() ([]model.Disability, utils.Error) {
+	var disabilities []model.Disability
+
+	if err := d.db.Order("category, description").Find(&disabilities).Error; err != nil {
+		return []model.Disability{}, disabilityRepoError("failed to list the disabilities", "03")
+	}
+
+	return disabilities, utils.Error{}
+}
+
+// ListExistingDisabilityIds returns which of the ids are in the disabilities catalog
+func (d *disabilityRepo) ListExistingDisabilityIds(ids []int) ([]int, utils.Error) {
+	existingIds := []int{}
+
+	if len(ids) == 0 {
+		return existingIds, utils.Error{}
+	}
+
+	if err := d.db.Model(&model.Disability{}).Where("id IN ?", ids).Pluck("id", &existingIds).Error; err != nil {
+		return []int{}, disabilityRepoError("failed to list the disabilities", "04")
+	}
+
+	return existingIds, utils.Error{}
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"cij_api/src/model"
+	"cij_api/src/utils"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepo interface {
+	BaseRepoMethods
+
+	CreatePasswordResetToken(token model.PasswordResetToken) utils.Error
+	GetPasswordResetTokenByHash(tokenHash string) (model.PasswordResetToken, utils.Error)
+	MarkPasswordResetTokenUsed(id int, tx *gorm.DB) utils.Error
+}
+
+// ErrPasswordResetTokenUsed is the cause of the error MarkPasswordResetTokenUsed returns
+// when another request used the token first
+var ErrPasswordResetTokenUsed = errors.New("the password reset token was already used")
+
+type passwordResetRepo struct {
+	BaseRepo
+	db *gorm.DB
+}
+
+func NewPasswordResetRepo(db *gorm.DB) PasswordResetRepo {
+	repo := &passwordResetRepo{
+		db: db,
+	}
+
+	repo.SetRepo(repo.db)
+
+	return repo
+}
+
+func passwordResetRepoError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.UserErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (p *passwordResetRepo) CreatePasswordResetToken(token model.PasswordResetToken) utils.Error {
+	if err := p.db.Create(&token).Error; err != nil {
+		return passwordResetRepoError("failed to create the password reset token", "01")
+	}
+
+	return utils.Error{}
+}
+
+func (p *passwordResetRepo) GetPasswordResetTokenByHash(tokenHash string) (model.PasswordResetToken, utils.Error) {
+	var token model.PasswordResetToken
+
+	if err := p.db.Where("token_hash = ?", tokenHash).Find(&token).Error; err != nil {
+		return token, passwordResetRepoError("failed to get the password reset token", "02")
+	}
+
+	return token, utils.Error{}
+}
+
+// MarkPasswordResetTokenUsed only succeeds once per token, so two concurrent resets
+// with the same token can't both go through
+func (p *passwordResetRepo) MarkPasswordResetTokenUsed(id int, tx *gorm.DB) utils.Error {
+	databaseConn := p.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	result := databaseConn.Model(&model.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if result.Error != nil {
+		return passwordResetRepoError("failed to update the password reset token", "03")
+	}
+
+	if result.RowsAffected == 0 {
+		return passwordResetRepoError("the password reset token was already used", "04").WithCause(ErrPasswordResetTokenUsed)
+	}
+
+	return utils.Error{}
+}
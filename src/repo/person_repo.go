@@ -3,6 +3,9 @@ package repo
 import (
 	"cij_api/src/model"
 	"cij_api/src/utils"
+	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,6 +21,8 @@ type PersonRepo interface {
 	UpdatePerson(person model.Person, personId int, tx *gorm.DB) utils.Error
 	DeletePerson(personId int) utils.Error
 	UploadCurriculum(personId int, fileUrl string) utils.Error
+	SearchCandidates(filters model.CandidateFilters, page int, perPage int) ([]model.Person, int, utils.Error)
+	AnonymizePerson(person model.Person, tx *gorm.DB) utils.Error
 }
 
 type personRepo struct {
@@ -58,7 +63,7 @@ func (n *personRepo) CreatePerson(createPerson model.Person, tx *gorm.DB) (int,
 func (n *personRepo) ListPeople() ([]model.Person, utils.Error) {
 	var people []model.Person
 
-	err := n.db.Model(model.Person{}).Preload("User").Find(&people).Error
+	err := n.db.Model(model.Person{}).Preload("User").Preload("Address").Find(&people).Error
 	if err != nil {
 		return people, personRepoError("failed to list the people", "02")
 	}
@@ -133,3 +138,105 @@ func (n *personRepo) UploadCurriculum(personId int, fileUrl string) utils.Error
 
 	return utils.Error{}
 }
+
+// SearchCandidates returns a page of the people matching the filters, sorted by name,
+// with their disabilities preloaded, and the total of matches
+func (n *personRepo) SearchCandidates(filters model.CandidateFilters, page int, perPage int) ([]model.Person, int, utils.Error) {
+	var people []model.Person
+	var total int64
+
+	query := n.db.Model(&model.Person{})
+
+	if category := strings.TrimSpace(filters.DisabilityCategory); category != "" {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM person_disabilities JOIN disabilities ON disabilities.id = person_disabilities.disability_id WHERE person_disabilities.person_id = people.id AND LOWER(disabilities.category) = LOWER(?))",
+			category,
+		)
+	}
+
+	if area := utils.CollapseSpaces(filters.Area); area != "" {
+		query = query.Where("LOWER(people.area) = LOWER(?)", area)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return []model.Person{}, 0, personRepoError("failed to count the candidates", "09")
+	}
+
+	err := query.
+		Preload("Address").
+		Preload("Disabilities.Disability").
+		Order("people.name ASC, people.id ASC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&people).Error
+	if err != nil {
+		return []model.Person{}, 0, personRepoError("failed to search the candidates", "10")
+	}
+
+	return people, int(total), utils.Error{}
+}
+
+// AnonymizePerson erases the personal data of the person, their user and address and
+// the email in the activities. The gender, area, city, disabilities and applications
+// are kept for the aggregate reports. The cpf and email get placeholders, since both
+// are unique
+func (n *personRepo) AnonymizePerson(person model.Person, tx *gorm.DB) utils.Error {
+	databaseConn := n.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	anonymizedEmail := fmt.Sprintf("anonymized-%d@anonymized.invalid", person.UserId)
+
+	err := databaseConn.Model(&model.Person{}).Where("id = ?", person.Id).Updates(map[string]interface{}{
+		"name":          "Anonymized",
+		"cpf":           fmt.Sprintf("%011d", person.Id),
+		"phone":         "",
+		"birth_date":    nil,
+		"curriculum":    "",
+		"anonymized_at": time.Now(),
+	}).Error
+	if err != nil {
+		return personRepoError("failed to anonymize the person", "11")
+	}
+
+	var email string
+	if err := databaseConn.Model(&model.User{}).Where("id = ?", person.UserId).Select("email").Scan(&email).Error; err != nil {
+		return personRepoError("failed to anonymize the person", "12")
+	}
+
+	err = databaseConn.Model(&model.User{}).Where("id = ?", person.UserId).Updates(map[string]interface{}{
+		"email":      anonymizedEmail,
+		"password":   "",
+		"config_url": "",
+	}).Error
+	if err != nil {
+		return personRepoError("failed to anonymize the person", "12")
+	}
+
+	if person.AddressId != nil {
+		err = databaseConn.Model(&model.Address{}).Where("id = ?", *person.AddressId).Updates(map[string]interface{}{
+			"street":       "",
+			"number":       "",
+			"neighborhood": "",
+			"zip_code":     "",
+			"complement":   "",
+		}).Error
+		if err != nil {
+			return personRepoError("failed to anonymize the person", "13")
+		}
+	}
+
+	if email != "" {
+		err = databaseConn.Model(&model.Activity{}).Where("actor = ?", email).Updates(map[string]interface{}{
+			"actor":       anonymizedEmail,
+			"description": gorm.Expr("REPLACE(description, ?, ?)", email, anonymizedEmail),
+		}).Error
+		if err != nil {
+			return personRepoError("failed to anonymize the person", "14")
+		}
+	}
+
+	return utils.Error{}
+}
@@ -3,6 +3,8 @@ package repo
 import (
 	"cij_api/src/model"
 	"cij_api/src/utils"
+	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -12,10 +14,13 @@ type UserRepo interface {
 
 	CreateUser(createUser model.User, tx *gorm.DB) (int, utils.Error)
 	ListUsers() ([]model.User, utils.Error)
+	ListUsersPaginated(filters model.UserFilters, page int, perPage int) ([]model.User, int, utils.Error)
 	GetUserByEmail(email string) (model.User, utils.Error)
 	GetUserById(id int) (model.User, utils.Error)
 	UpdateUser(user model.User, userId int) utils.Error
 	UpdateUserConfig(configUrl string, userEmail string) utils.Error
+	UpdateUserPassword(userId int, hashedPassword string, tx *gorm.DB) utils.Error
+	SetUserActive(userId int, active bool) utils.Error
 	DeleteUser(id int) utils.Error
 }
 
@@ -34,6 +39,10 @@ func NewUserRepo(db *gorm.DB) UserRepo {
 	return repo
 }
 
+// EmailAlreadyRegisteredErrorCode is the code CreateUser and UpdateUser return when the
+// email belongs to another user
+var EmailAlreadyRegisteredErrorCode = utils.NewErrorCode(utils.DatabaseErrorCode, utils.UserErrorType, "09")
+
 func userRepoError(message string, code string) utils.Error {
 	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.UserErrorType, code)
 
@@ -47,13 +56,32 @@ func (n *userRepo) CreateUser(createUser model.User, tx *gorm.DB) (int, utils.Er
 		databaseConn = tx
 	}
 
+	hashedPassword, err := utils.EncryptPassword(createUser.Password)
+	if err != nil {
+		return 0, userRepoError("failed to encrypt the password", "08")
+	}
+
+	createUser.Password = hashedPassword
+	createUser.Email = utils.NormalizeEmail(createUser.Email)
+
+	if createUser.RoleId == 0 {
+		createUser.RoleId = model.PersonRole
+	}
+
 	if err := databaseConn.Create(&createUser).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return 0, utils.NewError("email already registered", EmailAlreadyRegisteredErrorCode)
+		}
+
 		return 0, userRepoError("failed to create the user", "01")
 	}
 
 	return createUser.Id, utils.Error{}
 }
 
+// ListUsers returns every user without pagination
+//
+// Deprecated: use ListUsersPaginated, listing every user doesn't scale
 func (n *userRepo) ListUsers() ([]model.User, utils.Error) {
 	var users []model.User
 
@@ -65,10 +93,43 @@ func (n *userRepo) ListUsers() ([]model.User, utils.Error) {
 	return users, utils.Error{}
 }
 
+func (n *userRepo) ListUsersPaginated(filters model.UserFilters, page int, perPage int) ([]model.User, int, utils.Error) {
+	var users []model.User
+	var total int64
+
+	query := n.db.Model(model.User{})
+
+	if filters.Role != "" {
+		query = query.Where("role_id = ?", model.RoleIdFromUserRole(filters.Role))
+	}
+
+	if email := strings.TrimSpace(filters.Email); email != "" {
+		query = query.Where("email LIKE ?", "%"+strings.ToLower(email)+"%")
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return users, 0, userRepoError("failed to count the users", "12")
+	}
+
+	err := query.
+		Preload("Role").
+		Order("id").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&users).Error
+	if err != nil {
+		return users, 0, userRepoError("failed to list the users", "11")
+	}
+
+	return users, int(total), utils.Error{}
+}
+
+// GetUserByEmail compares the normalized email, the emails are normalized on write so the
+// lookup ignores the case and still uses the email index
 func (n *userRepo) GetUserByEmail(email string) (model.User, utils.Error) {
 	var user model.User
 
-	err := n.db.Model(model.User{}).Preload("Role").Where("email = ?", email).Find(&user).Error
+	err := n.db.Model(model.User{}).Preload("Role").Where("email = ?", utils.NormalizeEmail(email)).Find(&user).Error
 	if err != nil {
 		return user, userRepoError("failed to get the user", "03")
 	}
@@ -88,21 +149,50 @@ func (n *userRepo) GetUserById(id int) (model.User, utils.Error) {
 }
 
 func (n *userRepo) UpdateUser(user model.User, userId int) utils.Error {
+	user.Email = utils.NormalizeEmail(user.Email)
+
 	if err := n.db.Model(model.User{}).Where("id = ?", userId).Updates(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.NewError("email already registered", EmailAlreadyRegisteredErrorCode)
+		}
+
 		return userRepoError("failed to update the user", "05")
 	}
 
 	return utils.Error{}
 }
 
+// SetUserActive updates the flag alone, a struct update would skip the false value
+func (n *userRepo) SetUserActive(userId int, active bool) utils.Error {
+	if err := n.db.Model(model.User{}).Where("id = ?", userId).Update("active", active).Error; err != nil {
+		return userRepoError("failed to update the user status", "13")
+	}
+
+	return utils.Error{}
+}
+
 func (n *userRepo) UpdateUserConfig(configUrl string, userEmail string) utils.Error {
-	if err := n.db.Model(model.User{}).Where("email = ?", userEmail).Update("config_url", configUrl).Error; err != nil {
+	if err := n.db.Model(model.User{}).Where("email = ?", utils.NormalizeEmail(userEmail)).Update("config_url", configUrl).Error; err != nil {
 		return userRepoError("failed to update the user config", "07")
 	}
 
 	return utils.Error{}
 }
 
+func (n *userRepo) UpdateUserPassword(userId int, hashedPassword string, tx *gorm.DB) utils.Error {
+	databaseConn := n.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Model(model.User{}).Where("id = ?", userId).Update("password", hashedPassword).Error; err != nil {
+		return userRepoError("failed to update the user password", "10")
+	}
+
+	return utils.Error{}
+}
+
 func (n *userRepo) DeleteUser(userId int) utils.Error {
 	err := n.db.Model(model.User{}).Where("id = ?", userId).Unscoped().Delete(&model.User{}).Error
 	if err != nil {
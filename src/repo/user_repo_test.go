@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"cij_api/src/model"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type capturedStatement struct {
+	sql  string
+	vars []interface{}
+}
+
+// dryRunDb builds the statements without running them and records the queries and
+// inserts, the connection is never opened
+func dryRunDb(t *testing.T) (*gorm.DB, *[]capturedStatement) {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:password@tcp(127.0.0.1:3306)/test?parseTime=true",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open the dry run database: %v", err)
+	}
+
+	statements := []capturedStatement{}
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, capturedStatement{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}
+
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture_query", capture); err != nil {
+		t.Fatalf("failed to register the query callback: %v", err)
+	}
+
+	if err := db.Callback().Create().After("gorm:create").Register("test:capture_create", capture); err != nil {
+		t.Fatalf("failed to register the create callback: %v", err)
+	}
+
+	return db, &statements
+}
+
+func TestGetUserByEmailMatchesMixedCase(t *testing.T) {
+	db, statements := dryRunDb(t)
+
+	if _, err := NewUserRepo(db).GetUserByEmail("  John.Doe@Example.COM "); err.IsError() {
+		t.Fatalf("GetUserByEmail returned an error: %v", err)
+	}
+
+	if len(*statements) == 0 {
+		t.Fatal("no query was built")
+	}
+
+	lookup := (*statements)[0]
+
+	if !strings.Contains(lookup.sql, "email = ?") || strings.Contains(lookup.sql, "LOWER(") {
+		t.Errorf("the lookup should compare the stored email as is, got %q", lookup.sql)
+	}
+
+	if len(lookup.vars) == 0 || lookup.vars[0] != "john.doe@example.com" {
+		t.Errorf("the lookup should use the normalized email, got %v", lookup.vars)
+	}
+}
+
+func TestCreateUserNormalizesEmail(t *testing.T) {
+	db, statements := dryRunDb(t)
+
+	user := model.User{Email: " John.Doe@Example.COM", Password: "Sup3rSecret"}
+
+	if _, err := NewUserRepo(db).CreateUser(user, nil); err.IsError() {
+		t.Fatalf("CreateUser returned an error: %v", err)
+	}
+
+	for _, statement := range *statements {
+		if !strings.HasPrefix(statement.sql, "INSERT INTO `users`") {
+			continue
+		}
+
+		for _, value := range statement.vars {
+			if value == "john.doe@example.com" {
+				return
+			}
+		}
+
+		t.Fatalf("the stored email should be normalized, got %v", statement.vars)
+	}
+
+	t.Fatal("no insert of the user was built")
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	model "cij_api/src/model/vacancy"
+	"cij_api/src/repo"
+	"cij_api/src/utils"
+
+	"gorm.io/gorm"
+)
+
+type AccommodationsRepo interface {
+	repo.BaseRepoMethods
+
+	ReplaceAccommodations(vacancyId int, accommodations []string, tx *gorm.DB) utils.Error
+	DeleteAccommodationsByVacancyId(vacancyId int, tx *gorm.DB) utils.Error
+}
+
+type accommodationsRepo struct {
+	repo.BaseRepo
+	db *gorm.DB
+}
+
+func NewAccommodationsRepo(db *gorm.DB) AccommodationsRepo {
+	repo := &accommodationsRepo{
+		db: db,
+	}
+
+	repo.SetRepo(repo.db)
+
+	return repo
+}
+
+func accommodationsRepoError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.VacancyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+// ReplaceAccommodations swaps the accommodations of the vacancy for the given ones,
+// which are expected to be normalized already
+func (a *accommodationsRepo) ReplaceAccommodations(vacancyId int, accommodations []string, tx *gorm.DB) utils.Error {
+	databaseConn := a.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Unscoped().Where("vacancy_id = ?", vacancyId).Delete(&model.VacancyAccommodation{}).Error; err != nil {
+		return accommodationsRepoError("failed to replace the accommodations", "01").WithCause(err)
+	}
+
+	if len(accommodations) == 0 {
+		return utils.Error{}
+	}
+
+	accommodationModels := make([]model.VacancyAccommodation, 0, len(accommodations))
+	for _, accommodation := range accommodations {
+		accommodationModels = append(accommodationModels, model.VacancyAccommodation{
+			Accommodation: accommodation,
+			VacancyId:     vacancyId,
+		})
+	}
+
+	if err := databaseConn.Create(&accommodationModels).Error; err != nil {
+		return accommodationsRepoError("failed to replace the accommodations", "02").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (a *accommodationsRepo) DeleteAccommodationsByVacancyId(vacancyId int, tx *gorm.DB) utils.Error {
+	databaseConn := a.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Delete(&model.VacancyAccommodation{}).Error; err != nil {
+		return accommodationsRepoError("failed to delete the accommodations", "03").WithCause(err)
+	}
+
+	return utils.Error{}
+}
@@ -0,0 +1,89 @@
+package repo
+
+import (
+	model "cij_api/src/model/vacancy"
+	"cij_api/src/repo"
+	"cij_api/src/utils"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyKeyRepo interface {
+	repo.BaseRepoMethods
+
+	GetIdempotencyKey(key string, tx *gorm.DB) (model.VacancyIdempotencyKey, utils.Error)
+	CreateIdempotencyKey(idempotencyKey model.VacancyIdempotencyKey, tx *gorm.DB) utils.Error
+	DeleteExpiredIdempotencyKey(key string, tx *gorm.DB) utils.Error
+}
+
+type idempotencyKeyRepo struct {
+	repo.BaseRepo
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepo(db *gorm.DB) IdempotencyKeyRepo {
+	repo := &idempotencyKeyRepo{
+		db: db,
+	}
+
+	repo.SetRepo(repo.db)
+
+	return repo
+}
+
+func idempotencyKeyRepoError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.VacancyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+// GetIdempotencyKey returns the key only while it has not expired
+func (i *idempotencyKeyRepo) GetIdempotencyKey(key string, tx *gorm.DB) (model.VacancyIdempotencyKey, utils.Error) {
+	var idempotencyKey model.VacancyIdempotencyKey
+
+	databaseConn := i.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("`key` = ? AND expires_at > ?", key, time.Now()).Find(&idempotencyKey).Error; err != nil {
+		return model.VacancyIdempotencyKey{}, idempotencyKeyRepoError("failed to get the idempotency key", "01").WithCause(err)
+	}
+
+	return idempotencyKey, utils.Error{}
+}
+
+func (i *idempotencyKeyRepo) CreateIdempotencyKey(idempotencyKey model.VacancyIdempotencyKey, tx *gorm.DB) utils.Error {
+	databaseConn := i.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Create(&idempotencyKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return idempotencyKeyRepoError("the idempotency key was already used", "02")
+		}
+
+		return idempotencyKeyRepoError("failed to create the idempotency key", "03").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (i *idempotencyKeyRepo) DeleteExpiredIdempotencyKey(key string, tx *gorm.DB) utils.Error {
+	databaseConn := i.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("`key` = ? AND expires_at <= ?", key, time.Now()).Delete(&model.VacancyIdempotencyKey{}).Error; err != nil {
+		return idempotencyKeyRepoError("failed to delete the expired idempotency key", "04").WithCause(err)
+	}
+
+	return utils.Error{}
+}
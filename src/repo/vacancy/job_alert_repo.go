@@ -0,0 +1,105 @@
+package repo
+
+import (
+	model "cij_api/src/model/vacancy"
+	"cij_api/src/repo"
+	"cij_api/src/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JobAlertRepo interface {
+	repo.BaseRepoMethods
+
+	CreateJobAlert(jobAlert model.JobAlert) (int, utils.Error)
+	GetJobAlertById(id int) (model.JobAlert, utils.Error)
+	ListJobAlertsByCandidateId(candidateId int) ([]model.JobAlert, utils.Error)
+	ListActiveJobAlerts() ([]model.JobAlert, utils.Error)
+	UpdateJobAlertPaused(id int, paused bool) utils.Error
+	UpdateJobAlertLastRun(id int, lastRunAt time.Time) utils.Error
+	DeleteJobAlert(id int) utils.Error
+}
+
+type jobAlertRepo struct {
+	repo.BaseRepo
+	db *gorm.DB
+}
+
+func NewJobAlertRepo(db *gorm.DB) JobAlertRepo {
+	repo := &jobAlertRepo{
+		db: db,
+	}
+
+	repo.SetRepo(repo.db)
+
+	return repo
+}
+
+func jobAlertRepoError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.JobAlertErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (j *jobAlertRepo) CreateJobAlert(jobAlert model.JobAlert) (int, utils.Error) {
+	if err := j.db.Create(&jobAlert).Error; err != nil {
+		return 0, jobAlertRepoError("failed to create the job alert", "01").WithCause(err)
+	}
+
+	return jobAlert.Id, utils.Error{}
+}
+
+func (j *jobAlertRepo) GetJobAlertById(id int) (model.JobAlert, utils.Error) {
+	var jobAlert model.JobAlert
+
+	if err := j.db.Where("id = ?", id).Find(&jobAlert).Error; err != nil {
+		return model.JobAlert{}, jobAlertRepoError("failed to get the job alert", "02").WithCause(err)
+	}
+
+	return jobAlert, utils.Error{}
+}
+
+func (j *jobAlertRepo) ListJobAlertsByCandidateId(candidateId int) ([]model.JobAlert, utils.Error) {
+	var jobAlerts []model.JobAlert
+
+	if err := j.db.Where("candidate_id = ?", candidateId).Order("id").Find(&jobAlerts).Error; err != nil {
+		return jobAlerts, jobAlertRepoError("failed to list the job alerts", "03").WithCause(err)
+	}
+
+	return jobAlerts, utils.Error{}
+}
+
+func (j *jobAlertRepo) ListActiveJobAlerts() ([]model.JobAlert, utils.Error) {
+	var jobAlerts []model.JobAlert
+
+	if err := j.db.Preload("Candidate.User").Where("paused = ?", false).Find(&jobAlerts).Error; err != nil {
+		return jobAlerts, jobAlertRepoError("failed to list the active job alerts", "04").WithCause(err)
+	}
+
+	return jobAlerts, utils.Error{}
+}
+
+func (j *jobAlertRepo) UpdateJobAlertPaused(id int, paused bool) utils.Error {
+	if err := j.db.Model(&model.JobAlert{}).Where("id = ?", id).Update("paused", paused).Error; err != nil {
+		return jobAlertRepoError("failed to update the job alert", "05").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (j *jobAlertRepo) UpdateJobAlertLastRun(id int, lastRunAt time.Time) utils.Error {
+	if err := j.db.Model(&model.JobAlert{}).Where("id = ?", id).Update("last_run_at", lastRunAt).Error; err != nil {
+		return jobAlertRepoError("failed to update the job alert last run", "06").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (j *jobAlertRepo) DeleteJobAlert(id int) utils.Error {
+	if err := j.db.Where("id = ?", id).Delete(&model.JobAlert{}).Error; err != nil {
+		return jobAlertRepoError("failed to delete the job alert", "07").WithCause(err)
+	}
+
+	return utils.Error{}
+}
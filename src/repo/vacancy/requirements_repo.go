@@ -12,8 +12,10 @@ type RequirementsRepo interface {
 	repo.BaseRepoMethods
 
 	CreateRequirement(createRequirement model.VacancyRequirement, tx *gorm.DB) (int, utils.Error)
-	ListRequirementsByVacancyId(vacancyId int) ([]model.VacancyRequirement, utils.Error)
+	CreateRequirements(createRequirements []model.VacancyRequirement, tx *gorm.DB) utils.Error
+	ListRequirementsByVacancyId(vacancyId int, tx *gorm.DB) ([]model.VacancyRequirement, utils.Error)
 	UpdateRequirement(requirement model.VacancyRequirement, requirementId int, tx *gorm.DB) utils.Error
+	DeleteRequirement(requirementId int, tx *gorm.DB) utils.Error
 	DeleteRequirementsByVacancyId(vacancyId int, tx *gorm.DB) utils.Error
 }
 
@@ -46,17 +48,43 @@ func (r *requirementsRepo) CreateRequirement(createRequirement model.VacancyRequ
 	}
 
 	if err := databaseConn.Create(&createRequirement).Error; err != nil {
-		return 0, requirementsRepoError("failed to create the requirement", "01")
+		return 0, requirementsRepoError("failed to create the requirement", "01").WithCause(err)
 	}
 
 	return createRequirement.Id, utils.Error{}
 }
 
-func (r *requirementsRepo) ListRequirementsByVacancyId(vacancyId int) ([]model.VacancyRequirement, utils.Error) {
+// CreateRequirements inserts the requirements in batches of createBatchSize rows, setting
+// their ids
+func (r *requirementsRepo) CreateRequirements(createRequirements []model.VacancyRequirement, tx *gorm.DB) utils.Error {
+	if len(createRequirements) == 0 {
+		return utils.Error{}
+	}
+
+	databaseConn := r.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.CreateInBatches(&createRequirements, createBatchSize).Error; err != nil {
+		return requirementsRepoError("failed to create the requirements", "06").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (r *requirementsRepo) ListRequirementsByVacancyId(vacancyId int, tx *gorm.DB) ([]model.VacancyRequirement, utils.Error) {
 	var requirements []model.VacancyRequirement
 
-	if err := r.db.Where("vacancy_id = ?", vacancyId).Find(&requirements).Error; err != nil {
-		return []model.VacancyRequirement{}, requirementsRepoError("failed to list the requirements", "02")
+	databaseConn := r.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Find(&requirements).Error; err != nil {
+		return []model.VacancyRequirement{}, requirementsRepoError("failed to list the requirements", "02").WithCause(err)
 	}
 
 	return requirements, utils.Error{}
@@ -70,7 +98,7 @@ func (r *requirementsRepo) UpdateRequirement(requirement model.VacancyRequiremen
 	}
 
 	if err := databaseConn.Model(model.VacancyRequirement{}).Where("id = ?", requirementId).Updates(requirement).Error; err != nil {
-		return requirementsRepoError("failed to update the requirement", "03")
+		return requirementsRepoError("failed to update the requirement", "03").WithCause(err)
 	}
 
 	return utils.Error{}
@@ -84,7 +112,21 @@ func (r *requirementsRepo) DeleteRequirementsByVacancyId(vacancyId int, tx *gorm
 	}
 
 	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Delete(&model.VacancyRequirement{}).Error; err != nil {
-		return requirementsRepoError("failed to delete the requirements", "04")
+		return requirementsRepoError("failed to delete the requirements", "04").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (r *requirementsRepo) DeleteRequirement(requirementId int, tx *gorm.DB) utils.Error {
+	databaseConn := r.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("id = ?", requirementId).Delete(&model.VacancyRequirement{}).Error; err != nil {
+		return requirementsRepoError("failed to delete the requirement", "05").WithCause(err)
 	}
 
 	return utils.Error{}
@@ -12,8 +12,10 @@ type ResponsabilitiesRepo interface {
 	repo.BaseRepoMethods
 
 	CreateResponsability(createResponsability model.VacancyResponsability, tx *gorm.DB) (int, utils.Error)
-	ListResponsabilitiesByVacancyId(vacancyId int) ([]model.VacancyResponsability, utils.Error)
+	CreateResponsabilities(createResponsabilities []model.VacancyResponsability, tx *gorm.DB) utils.Error
+	ListResponsabilitiesByVacancyId(vacancyId int, tx *gorm.DB) ([]model.VacancyResponsability, utils.Error)
 	UpdateResponsability(responsability model.VacancyResponsability, responsabilityId int, tx *gorm.DB) utils.Error
+	DeleteResponsability(responsabilityId int, tx *gorm.DB) utils.Error
 	DeleteResponsabilitiesByVacancyId(vacancyId int, tx *gorm.DB) utils.Error
 }
 
@@ -46,17 +48,43 @@ func (r *responsabilitiesRepo) CreateResponsability(createResponsability model.V
 	}
 
 	if err := databaseConn.Create(&createResponsability).Error; err != nil {
-		return 0, responsabilitiesRepoError("failed to create the responsability", "01")
+		return 0, responsabilitiesRepoError("failed to create the responsability", "01").WithCause(err)
 	}
 
 	return createResponsability.Id, utils.Error{}
 }
 
-func (r *responsabilitiesRepo) ListResponsabilitiesByVacancyId(vacancyId int) ([]model.VacancyResponsability, utils.Error) {
+// CreateResponsabilities inserts the responsabilities in batches of createBatchSize rows, setting
+// their ids
+func (r *responsabilitiesRepo) CreateResponsabilities(createResponsabilities []model.VacancyResponsability, tx *gorm.DB) utils.Error {
+	if len(createResponsabilities) == 0 {
+		return utils.Error{}
+	}
+
+	databaseConn := r.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.CreateInBatches(&createResponsabilities, createBatchSize).Error; err != nil {
+		return responsabilitiesRepoError("failed to create the responsabilities", "06").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (r *responsabilitiesRepo) ListResponsabilitiesByVacancyId(vacancyId int, tx *gorm.DB) ([]model.VacancyResponsability, utils.Error) {
 	var responsabilities []model.VacancyResponsability
 
-	if err := r.db.Where("vacancy_id = ?", vacancyId).Find(&responsabilities).Error; err != nil {
-		return []model.VacancyResponsability{}, responsabilitiesRepoError("failed to list the responsabilities", "02")
+	databaseConn := r.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Find(&responsabilities).Error; err != nil {
+		return []model.VacancyResponsability{}, responsabilitiesRepoError("failed to list the responsabilities", "02").WithCause(err)
 	}
 
 	return responsabilities, utils.Error{}
@@ -70,7 +98,7 @@ func (r *responsabilitiesRepo) UpdateResponsability(responsability model.Vacancy
 	}
 
 	if err := databaseConn.Model(model.VacancyResponsability{}).Where("id = ?", responsabilityId).Updates(responsability).Error; err != nil {
-		return responsabilitiesRepoError("failed to update the responsability", "03")
+		return responsabilitiesRepoError("failed to update the responsability", "03").WithCause(err)
 	}
 
 	return utils.Error{}
@@ -84,7 +112,21 @@ func (r *responsabilitiesRepo) DeleteResponsabilitiesByVacancyId(vacancyId int,
 	}
 
 	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Delete(&model.VacancyResponsability{}).Error; err != nil {
-		return responsabilitiesRepoError("failed to delete the responsabilities", "04")
+		return responsabilitiesRepoError("failed to delete the responsabilities", "04").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (r *responsabilitiesRepo) DeleteResponsability(responsabilityId int, tx *gorm.DB) utils.Error {
+	databaseConn := r.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("id = ?", responsabilityId).Delete(&model.VacancyResponsability{}).Error; err != nil {
+		return responsabilitiesRepoError("failed to delete the responsability", "05").WithCause(err)
 	}
 
 	return utils.Error{}
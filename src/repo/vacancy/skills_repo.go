@@ -8,12 +8,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// createBatchSize is how many rows each insert of the batch creations holds
+const createBatchSize = 100
+
 type SkillsRepo interface {
 	repo.BaseRepoMethods
 
 	CreateSkill(createSkill model.VacancySkill, tx *gorm.DB) (int, utils.Error)
-	ListSkillsByVacancyId(vacancyId int) ([]model.VacancySkill, utils.Error)
+	CreateSkills(createSkills []model.VacancySkill, tx *gorm.DB) utils.Error
+	ListSkillsByVacancyId(vacancyId int, tx *gorm.DB) ([]model.VacancySkill, utils.Error)
 	UpdateSkill(skill model.VacancySkill, skillId int, tx *gorm.DB) utils.Error
+	DeleteSkill(skillId int, tx *gorm.DB) utils.Error
 	DeleteSkillsByVacancyId(vacancyId int, tx *gorm.DB) utils.Error
 }
 
@@ -46,17 +51,43 @@ func (s *skillsRepo) CreateSkill(createSkill model.VacancySkill, tx *gorm.DB) (i
 	}
 
 	if err := databaseConn.Create(&createSkill).Error; err != nil {
-		return 0, skillsRepoError("failed to create the skill", "01")
+		return 0, skillsRepoError("failed to create the skill", "01").WithCause(err)
 	}
 
 	return createSkill.Id, utils.Error{}
 }
 
-func (s *skillsRepo) ListSkillsByVacancyId(vacancyId int) ([]model.VacancySkill, utils.Error) {
+// CreateSkills inserts the skills in batches of createBatchSize rows, setting
+// their ids
+func (s *skillsRepo) CreateSkills(createSkills []model.VacancySkill, tx *gorm.DB) utils.Error {
+	if len(createSkills) == 0 {
+		return utils.Error{}
+	}
+
+	databaseConn := s.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.CreateInBatches(&createSkills, createBatchSize).Error; err != nil {
+		return skillsRepoError("failed to create the skills", "06").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (s *skillsRepo) ListSkillsByVacancyId(vacancyId int, tx *gorm.DB) ([]model.VacancySkill, utils.Error) {
 	var skills []model.VacancySkill
 
-	if err := s.db.Where("vacancy_id = ?", vacancyId).Find(&skills).Error; err != nil {
-		return []model.VacancySkill{}, skillsRepoError("failed to list the skills", "02")
+	databaseConn := s.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Find(&skills).Error; err != nil {
+		return []model.VacancySkill{}, skillsRepoError("failed to list the skills", "02").WithCause(err)
 	}
 
 	return skills, utils.Error{}
@@ -70,7 +101,7 @@ func (s *skillsRepo) UpdateSkill(skill model.VacancySkill, skillId int, tx *gorm
 	}
 
 	if err := databaseConn.Model(&model.VacancySkill{}).Where("id = ?", skillId).Updates(&skill).Error; err != nil {
-		return skillsRepoError("failed to update the skill", "03")
+		return skillsRepoError("failed to update the skill", "03").WithCause(err)
 	}
 
 	return utils.Error{}
@@ -84,7 +115,21 @@ func (s *skillsRepo) DeleteSkillsByVacancyId(vacancyId int, tx *gorm.DB) utils.E
 	}
 
 	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Delete(&model.VacancySkill{}).Error; err != nil {
-		return skillsRepoError("failed to delete the skills", "04")
+		return skillsRepoError("failed to delete the skills", "04").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (s *skillsRepo) DeleteSkill(skillId int, tx *gorm.DB) utils.Error {
+	databaseConn := s.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("id = ?", skillId).Delete(&model.VacancySkill{}).Error; err != nil {
+		return skillsRepoError("failed to delete the skill", "05").WithCause(err)
 	}
 
 	return utils.Error{}
@@ -5,8 +5,11 @@ import (
 	model "cij_api/src/model/vacancy"
 	"cij_api/src/repo"
 	"cij_api/src/utils"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type VacancyApplyRepo interface {
@@ -14,10 +17,13 @@ type VacancyApplyRepo interface {
 
 	CreateVacancyApply(createVacancyApply model.VacancyApply) (int, utils.Error)
 	GetVacancyApply(vacancyId int, candidateId int) (model.VacancyApply, utils.Error)
-	ListVacancyAppliesByVacancyId(vacancyId int) ([]model.VacancyApply, utils.Error)
+	GetVacancyApplyById(vacancyApplyId int) (model.VacancyApply, utils.Error)
+	ListVacancyAppliesByVacancyId(vacancyId int, status enum.VacancyApplyStatus, page int, perPage int) ([]model.VacancyApply, int, utils.Error)
+	ListVacancyAppliesByCandidateId(candidateId int) ([]model.VacancyApply, utils.Error)
 	ListVacancyAppliesByVacancyIdAndCandidateId(vacancyId int, candidateId int) ([]model.VacancyApply, utils.Error)
-	UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus) utils.Error
-	DeleteVacancyAppliesByVacancyId(vacancyId int, tx *gorm.DB) utils.Error
+	ListApplicationsByCandidateId(candidateId int, status enum.VacancyApplyStatus, page int, perPage int) ([]model.CandidateApplication, int, utils.Error)
+	ListVacancyAppliesByIds(ids []int, tx *gorm.DB) ([]model.VacancyApply, utils.Error)
+	UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus, tx *gorm.DB) utils.Error
 }
 
 type vacancyApplyRepo struct {
@@ -43,7 +49,11 @@ func vacancyApplyRepoError(message string, code string) utils.Error {
 
 func (v *vacancyApplyRepo) CreateVacancyApply(createVacancyApply model.VacancyApply) (int, utils.Error) {
 	if err := v.db.Create(&createVacancyApply).Error; err != nil {
-		return 0, vacancyApplyRepoError("failed to create the vacancy apply", "01")
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return 0, vacancyApplyRepoError("the candidate already applied to the vacancy", "05").WithCause(err)
+		}
+
+		return 0, vacancyApplyRepoError("failed to create the vacancy apply", "01").WithCause(err)
 	}
 
 	return createVacancyApply.Id, utils.Error{}
@@ -52,18 +62,72 @@ func (v *vacancyApplyRepo) CreateVacancyApply(createVacancyApply model.VacancyAp
 func (v *vacancyApplyRepo) GetVacancyApply(vacancyId int, candidateId int) (model.VacancyApply, utils.Error) {
 	var vacancyApply model.VacancyApply
 
-	if err := v.db.Where("vacancy_id = ? AND candidate_id = ?", vacancyId, candidateId).Preload("Vacancy").Preload("Candidate").First(&vacancyApply).Error; err != nil {
-		return model.VacancyApply{}, vacancyApplyRepoError("failed to get the vacancy apply", "02")
+	err := v.db.Where("vacancy_id = ? AND candidate_id = ?", vacancyId, candidateId).Preload("Vacancy").Preload("Candidate").First(&vacancyApply).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.VacancyApply{}, utils.Error{}
+	}
+
+	if err != nil {
+		return model.VacancyApply{}, vacancyApplyRepoError("failed to get the vacancy apply", "02").WithCause(err)
+	}
+
+	return vacancyApply, utils.Error{}
+}
+
+func (v *vacancyApplyRepo) GetVacancyApplyById(vacancyApplyId int) (model.VacancyApply, utils.Error) {
+	var vacancyApply model.VacancyApply
+
+	if err := v.db.Where("id = ?", vacancyApplyId).Find(&vacancyApply).Error; err != nil {
+		return model.VacancyApply{}, vacancyApplyRepoError("failed to get the vacancy apply", "02").WithCause(err)
 	}
 
 	return vacancyApply, utils.Error{}
 }
 
-func (v *vacancyApplyRepo) ListVacancyAppliesByVacancyId(vacancyId int) ([]model.VacancyApply, utils.Error) {
+// ListVacancyAppliesByVacancyId returns a page of the vacancy applies, newest first,
+// and the total matching the status filter, an empty status doesn't filter
+func (v *vacancyApplyRepo) ListVacancyAppliesByVacancyId(vacancyId int, status enum.VacancyApplyStatus, page int, perPage int) ([]model.VacancyApply, int, utils.Error) {
+	var vacancyApplies []model.VacancyApply
+	var total int64
+
+	query := v.db.Model(&model.VacancyApply{}).Where("vacancy_id = ?", vacancyId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return []model.VacancyApply{}, 0, vacancyApplyRepoError("failed to count the vacancy applies", "07").WithCause(err)
+	}
+
+	err := query.
+		Preload("Vacancy").
+		Preload("Candidate").
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&vacancyApplies).Error
+	if err != nil {
+		return []model.VacancyApply{}, 0, vacancyApplyRepoError("failed to list the vacancy applies", "02").WithCause(err)
+	}
+
+	return vacancyApplies, int(total), utils.Error{}
+}
+
+// ListVacancyAppliesByCandidateId loads the deleted vacancies too, so the history of the
+// candidate keeps the applies to postings removed since
+func (v *vacancyApplyRepo) ListVacancyAppliesByCandidateId(candidateId int) ([]model.VacancyApply, utils.Error) {
 	var vacancyApplies []model.VacancyApply
 
-	if err := v.db.Where("vacancy_id = ?", vacancyId).Preload("Vacancy").Preload("Candidate").Find(&vacancyApplies).Error; err != nil {
-		return []model.VacancyApply{}, vacancyApplyRepoError("failed to list the vacancy applies", "02")
+	err := v.db.Where("candidate_id = ?", candidateId).
+		Preload("Vacancy", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Vacancy.Company").
+		Preload("Vacancy.Disabilities").
+		Order("created_at DESC").
+		Find(&vacancyApplies).Error
+	if err != nil {
+		return []model.VacancyApply{}, vacancyApplyRepoError("failed to list the vacancy applies", "02").WithCause(err)
 	}
 
 	return vacancyApplies, utils.Error{}
@@ -73,29 +137,85 @@ func (v *vacancyApplyRepo) ListVacancyAppliesByVacancyIdAndCandidateId(vacancyId
 	var vacancyApplies []model.VacancyApply
 
 	if err := v.db.Where("vacancy_id = ? AND candidate_id = ?", vacancyId, candidateId).Preload("Vacancy").Preload("Candidate").Find(&vacancyApplies).Error; err != nil {
-		return []model.VacancyApply{}, vacancyApplyRepoError("failed to list the vacancy applies", "02")
+		return []model.VacancyApply{}, vacancyApplyRepoError("failed to list the vacancy applies", "02").WithCause(err)
 	}
 
 	return vacancyApplies, utils.Error{}
 }
 
-func (v *vacancyApplyRepo) UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus) utils.Error {
-	if err := v.db.Model(model.VacancyApply{}).Where("id = ?", vacancyApplyId).Update("status", status).Error; err != nil {
-		return vacancyApplyRepoError("failed to update the vacancy apply status", "03")
+// ListApplicationsByCandidateId joins the vacancy and company in a single query, most
+// recently applied first, returning the page and the total matching the status filter
+func (v *vacancyApplyRepo) ListApplicationsByCandidateId(candidateId int, status enum.VacancyApplyStatus, page int, perPage int) ([]model.CandidateApplication, int, utils.Error) {
+	applications := []model.CandidateApplication{}
+	var total int64
+
+	query := v.db.Table("vacancy_applies").
+		Joins("JOIN vacancies ON vacancies.id = vacancy_applies.vacancy_id").
+		Joins("JOIN companies ON companies.id = vacancies.company_id").
+		Where("vacancy_applies.candidate_id = ?", candidateId)
+	if status != "" {
+		query = query.Where("vacancy_applies.status = ?", status)
 	}
 
-	return utils.Error{}
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return []model.CandidateApplication{}, 0, vacancyApplyRepoError("failed to count the candidate applications", "08").WithCause(err)
+	}
+
+	err := query.
+		Select(`vacancy_applies.id, vacancy_applies.vacancy_id, vacancies.title AS vacancy_title, companies.name AS company_name,
+			vacancy_applies.status, vacancy_applies.created_at AS applied_at,
+			COALESCE(vacancy_applies.status_updated_at, vacancy_applies.created_at) AS updated_at`).
+		Order("vacancy_applies.created_at DESC, vacancy_applies.id DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Scan(&applications).Error
+	if err != nil {
+		return []model.CandidateApplication{}, 0, vacancyApplyRepoError("failed to list the candidate applications", "06").WithCause(err)
+	}
+
+	return applications, int(total), utils.Error{}
 }
 
-func (v *vacancyApplyRepo) DeleteVacancyAppliesByVacancyId(vacancyId int, tx *gorm.DB) utils.Error {
+// ListVacancyAppliesByIds locks the applies for update, so the status they are checked
+// against can't change before the transaction ends. The vacancy is loaded for its company,
+// even when deleted
+func (v *vacancyApplyRepo) ListVacancyAppliesByIds(ids []int, tx *gorm.DB) ([]model.VacancyApply, utils.Error) {
+	vacancyApplies := []model.VacancyApply{}
+
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	err := databaseConn.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Vacancy", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Where("id IN ?", ids).
+		Find(&vacancyApplies).Error
+	if err != nil {
+		return []model.VacancyApply{}, vacancyApplyRepoError("failed to list the vacancy applies", "09").WithCause(err)
+	}
+
+	return vacancyApplies, utils.Error{}
+}
+
+func (v *vacancyApplyRepo) UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus, tx *gorm.DB) utils.Error {
+	updates := map[string]interface{}{
+		"status":            status,
+		"status_updated_at": time.Now(),
+	}
+
 	databaseConn := v.db
 
 	if tx != nil {
 		databaseConn = tx
 	}
 
-	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Unscoped().Delete(&model.VacancyApply{}).Error; err != nil {
-		return vacancyApplyRepoError("failed to delete the vacancy applies", "04")
+	if err := databaseConn.Model(model.VacancyApply{}).Where("id = ?", vacancyApplyId).Updates(updates).Error; err != nil {
+		return vacancyApplyRepoError("failed to update the vacancy apply status", "03").WithCause(err)
 	}
 
 	return utils.Error{}
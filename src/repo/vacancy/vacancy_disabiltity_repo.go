@@ -12,8 +12,9 @@ import (
 type VacancyDisabilityRepo interface {
 	repo.BaseRepoMethods
 
-	GetVacancyDisabilities(vacancyId int) ([]model.VacancyDisability, utils.Error)
+	GetVacancyDisabilities(vacancyId int, tx *gorm.DB) ([]model.VacancyDisability, utils.Error)
 	UpsertVacancyDisability(disability model.VacancyDisability, tx *gorm.DB) utils.Error
+	DeleteVacancyDisability(vacancyId int, disabilityId int, tx *gorm.DB) utils.Error
 	ClearVacancyDisability(vacancyId int, tx *gorm.DB) utils.Error
 }
 
@@ -38,12 +39,18 @@ func vacancyDisabilityRepoError(message string, code string) utils.Error {
 	return utils.NewError(message, errorCode)
 }
 
-func (v *vacancyDisabilityRepo) GetVacancyDisabilities(vacancyId int) ([]model.VacancyDisability, utils.Error) {
+func (v *vacancyDisabilityRepo) GetVacancyDisabilities(vacancyId int, tx *gorm.DB) ([]model.VacancyDisability, utils.Error) {
 	var disabilities []model.VacancyDisability
 
-	err := v.db.Model(model.VacancyDisability{}).Preload("Disability").Where("vacancy_id = ?", vacancyId).Find(&disabilities).Error
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	err := databaseConn.Model(model.VacancyDisability{}).Preload("Disability").Where("vacancy_id = ?", vacancyId).Find(&disabilities).Error
 	if err != nil {
-		return disabilities, vacancyDisabilityRepoError("failed to get the vacancy disabilities", "01")
+		return disabilities, vacancyDisabilityRepoError("failed to get the vacancy disabilities", "01").WithCause(err)
 	}
 
 	return disabilities, utils.Error{}
@@ -60,7 +67,21 @@ func (v *vacancyDisabilityRepo) UpsertVacancyDisability(disability model.Vacancy
 		Columns:   []clause.Column{{Name: "vacancy_id"}, {Name: "disability_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"disability_id", "vacancy_id"}),
 	}).Create(&disability).Error; err != nil {
-		return vacancyDisabilityRepoError("failed to upsert the vacancy disability", "02")
+		return vacancyDisabilityRepoError("failed to upsert the vacancy disability", "02").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (v *vacancyDisabilityRepo) DeleteVacancyDisability(vacancyId int, disabilityId int, tx *gorm.DB) utils.Error {
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("vacancy_id = ? AND disability_id = ?", vacancyId, disabilityId).Delete(&model.VacancyDisability{}).Error; err != nil {
+		return vacancyDisabilityRepoError("failed to delete the vacancy disability", "04").WithCause(err)
 	}
 
 	return utils.Error{}
@@ -74,7 +95,7 @@ func (v *vacancyDisabilityRepo) ClearVacancyDisability(vacancyId int, tx *gorm.D
 	}
 
 	if err := databaseConn.Where("vacancy_id = ?", vacancyId).Delete(&model.VacancyDisability{}).Error; err != nil {
-		return vacancyDisabilityRepoError("failed to clear the vacancy disability", "03")
+		return vacancyDisabilityRepoError("failed to clear the vacancy disability", "03").WithCause(err)
 	}
 
 	return utils.Error{}
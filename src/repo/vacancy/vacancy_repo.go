@@ -5,6 +5,11 @@ import (
 	model "cij_api/src/model/vacancy"
 	"cij_api/src/repo"
 	"cij_api/src/utils"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,15 +18,35 @@ type VacancyRepo interface {
 	repo.BaseRepoMethods
 
 	GetVacancyById(id int) (model.Vacancy, utils.Error)
-	ListVacancies(
-		companyId int,
-		area string,
-		contractType enum.VacancyContractType,
-		searchText string,
-	) ([]model.Vacancy, utils.Error)
+	GetVacancyWithAssociationsById(id int) (model.Vacancy, utils.Error)
+	GetVacancyByIdUnscoped(id int) (model.Vacancy, utils.Error)
+	GetVacancyBySlug(slug string) (model.Vacancy, utils.Error)
+	VacancySlugExists(slug string, tx *gorm.DB) (bool, utils.Error)
+	ListVacancies(filters model.VacancyFilters, page int, perPage int) ([]model.Vacancy, int, utils.Error)
+	ListAllVacancies(filters model.VacancyFilters) ([]model.Vacancy, utils.Error)
+	ListVacanciesBatch(filters model.VacancyFilters, offset int, limit int) ([]model.Vacancy, utils.Error)
+	ListVacanciesByIds(ids []int) ([]model.Vacancy, utils.Error)
+	FindRecentDuplicateVacancy(companyId int, title string, area string, since time.Time, tx *gorm.DB) (model.Vacancy, utils.Error)
 	UpsertVacancy(vacancy model.Vacancy, tx *gorm.DB) (int, utils.Error)
-	UpdateVacancy(vacancy model.Vacancy, tx *gorm.DB) utils.Error
-	DeleteVacancy(id int) utils.Error
+	DeleteVacancy(id int, tx *gorm.DB) utils.Error
+	CloseVacancy(id int) utils.Error
+	IncrementVacancyView(id int) utils.Error
+	ListPopularVacancies(since time.Time, limit int) ([]model.Vacancy, utils.Error)
+	VacancyStats(from time.Time, to time.Time, interval enum.VacancyStatsInterval) ([]model.VacancyStatsBucket, utils.Error)
+	FeatureVacancy(id int, featured bool, featuredUntil *time.Time, tx *gorm.DB) utils.Error
+	ReopenVacancy(id int, expiresAt *string, tx *gorm.DB) utils.Error
+	PublishVacancy(id int, tx *gorm.DB) utils.Error
+	ListVacancyIdsByCompany(companyId int, tx *gorm.DB) ([]int, utils.Error)
+	ListCompanyIdsWithOpenVacancies(page int, perPage int) ([]int, int, utils.Error)
+	ListOpenVacanciesByCompanies(companyIds []int, perCompany int) ([]model.Vacancy, utils.Error)
+	CountOpenVacanciesByCompanies(companyIds []int) (map[int]int, utils.Error)
+	TransferVacancies(fromCompanyId int, toCompanyId int, tx *gorm.DB) (int, utils.Error)
+
+	CountVacanciesByCompany(companyId int, tx *gorm.DB) (int, utils.Error)
+	CountVacanciesGroupedByArea() (map[string]int, utils.Error)
+	CountVacanciesGroupedByContractType(filters model.VacancyFilters) (map[enum.VacancyContractType]int, utils.Error)
+	ListVacancyAreas() ([]string, utils.Error)
+	ListVacancyCities() ([]string, utils.Error)
 }
 
 type vacancyRepo struct {
@@ -39,58 +64,400 @@ func NewVacancyRepo(db *gorm.DB) VacancyRepo {
 	return repo
 }
 
+// VacancyModifiedErrorCode is the code UpsertVacancy returns when the vacancy version
+// changed since it was read, so the services can tell the conflict from a failure
+var VacancyModifiedErrorCode = utils.NewErrorCode(utils.DatabaseErrorCode, utils.VacancyErrorType, "15")
+
 func vacancyRepoError(message string, code string) utils.Error {
 	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.VacancyErrorType, code)
 
 	return utils.NewError(message, errorCode)
 }
 
+const (
+	earthRadiusKm       = 6371.0
+	kmPerLatitudeDegree = 111.045
+)
+
+// activeCompanyCondition hides the vacancies of the companies whose account is deactivated
+const activeCompanyCondition = "EXISTS (SELECT 1 FROM companies JOIN users ON users.id = companies.user_id WHERE companies.id = vacancies.company_id AND users.active)"
+
+const openVacancyCondition = "vacancies.status = 'published' AND vacancies.closed_at IS NULL AND (vacancies.expires_at IS NULL OR vacancies.expires_at >= CURDATE()) AND " + activeCompanyCondition
+
+func openVacancies(db *gorm.DB) *gorm.DB {
+	return db.Where(openVacancyCondition)
+}
+
+// GetVacancyById also counts the applies of the vacancy in the same query
 func (v *vacancyRepo) GetVacancyById(id int) (model.Vacancy, utils.Error) {
 	var vacancy model.Vacancy
 
-	if err := v.db.Where("id = ?", id).Preload("Company").First(&vacancy).Error; err != nil {
-		return model.Vacancy{}, vacancyRepoError("failed to get the vacancy", "01")
+	err := v.db.
+		Select("vacancies.*, (SELECT COUNT(*) FROM vacancy_applies WHERE vacancy_applies.vacancy_id = vacancies.id) AS applies_count").
+		Where("vacancies.id = ?", id).
+		Preload("Company").
+		First(&vacancy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Vacancy{}, utils.Error{}
+	}
+
+	if err != nil {
+		return model.Vacancy{}, vacancyRepoError("failed to get the vacancy", "01").WithCause(err)
+	}
+
+	return vacancy, utils.Error{}
+}
+
+// GetVacancyWithAssociationsById loads the vacancy together with its company,
+// disabilities, skills, requirements and responsabilities
+func (v *vacancyRepo) GetVacancyWithAssociationsById(id int) (model.Vacancy, utils.Error) {
+	var vacancy model.Vacancy
+
+	err := v.db.
+		Select("vacancies.*, (SELECT COUNT(*) FROM vacancy_applies WHERE vacancy_applies.vacancy_id = vacancies.id) AS applies_count").
+		Where("vacancies.id = ?", id).
+		Preload("Company").
+		Preload("Disabilities").
+		Preload("Skills").
+		Preload("Requirements").
+		Preload("Responsabilities").
+		Preload("Accommodations").
+		Find(&vacancy).Error
+	if err != nil {
+		return model.Vacancy{}, vacancyRepoError("failed to get the vacancy", "19").WithCause(err)
+	}
+
+	return vacancy, utils.Error{}
+}
+
+// GetVacancyByIdUnscoped also finds soft deleted vacancies
+func (v *vacancyRepo) GetVacancyByIdUnscoped(id int) (model.Vacancy, utils.Error) {
+	var vacancy model.Vacancy
+
+	if err := v.db.Unscoped().Where("id = ?", id).Find(&vacancy).Error; err != nil {
+		return model.Vacancy{}, vacancyRepoError("failed to get the vacancy", "13").WithCause(err)
 	}
 
 	return vacancy, utils.Error{}
 }
 
-func (v *vacancyRepo) ListVacancies(
-	companyId int,
-	area string,
-	contractType enum.VacancyContractType,
-	searchText string,
-) ([]model.Vacancy, utils.Error) {
+func (v *vacancyRepo) GetVacancyBySlug(slug string) (model.Vacancy, utils.Error) {
+	var vacancy model.Vacancy
+
+	if err := v.db.Where("slug = ?", slug).Preload("Company").Find(&vacancy).Error; err != nil {
+		return model.Vacancy{}, vacancyRepoError("failed to get the vacancy", "11").WithCause(err)
+	}
+
+	return vacancy, utils.Error{}
+}
+
+// VacancySlugExists also looks at deleted vacancies, since they keep their slug
+func (v *vacancyRepo) VacancySlugExists(slug string, tx *gorm.DB) (bool, utils.Error) {
+	var total int64
+
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Unscoped().Model(&model.Vacancy{}).Where("slug = ?", slug).Count(&total).Error; err != nil {
+		return false, vacancyRepoError("failed to check the vacancy slug", "12").WithCause(err)
+	}
+
+	return total > 0, utils.Error{}
+}
+
+func (v *vacancyRepo) ListVacancies(filters model.VacancyFilters, page int, perPage int) ([]model.Vacancy, int, utils.Error) {
 	var vacancies []model.Vacancy
+	var total int64
 
-	query := v.db.Model(&model.Vacancy{}).
+	query := v.db.Model(&model.Vacancy{}).Scopes(filterVacancies(filters))
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return vacancies, 0, vacancyRepoError("failed to count the vacancies", "05").WithCause(err)
+	}
+
+	err := query.
 		Preload("Disabilities").
-		Preload("Company")
+		Preload("Company").
+		Preload("Accommodations").
+		Order(featuredFirstClause).
+		Order(vacancyOrderClause(filters.SortBy, filters.SortOrder)).
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&vacancies).Error
+	if err != nil {
+		return vacancies, 0, vacancyRepoError("failed to list the vacancies", "02").WithCause(err)
+	}
+
+	return vacancies, int(total), utils.Error{}
+}
 
-	if area != "" {
-		query = query.Where("vacancies.area = ?", area)
+// FindRecentDuplicateVacancy finds an open vacancy of the company created since the given
+// time with the same title and area, ignoring case
+func (v *vacancyRepo) FindRecentDuplicateVacancy(companyId int, title string, area string, since time.Time, tx *gorm.DB) (model.Vacancy, utils.Error) {
+	var vacancy model.Vacancy
+
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
 	}
 
-	if companyId > 0 {
-		query = query.Where("vacancies.company_id = ?", companyId)
+	err := databaseConn.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Where("vacancies.company_id = ?", companyId).
+		Where("LOWER(vacancies.title) = LOWER(?) AND LOWER(vacancies.area) = LOWER(?)", title, area).
+		Where("vacancies.created_at >= ?", since).
+		Order("vacancies.created_at DESC").
+		Limit(1).
+		Find(&vacancy).Error
+	if err != nil {
+		return model.Vacancy{}, vacancyRepoError("failed to find a duplicate vacancy", "20").WithCause(err)
 	}
 
-	if contractType != "" {
-		query = query.Where("vacancies.contract_type = ?", contractType)
+	return vacancy, utils.Error{}
+}
+
+func (v *vacancyRepo) ListVacanciesByIds(ids []int) ([]model.Vacancy, utils.Error) {
+	var vacancies []model.Vacancy
+
+	err := v.db.Model(&model.Vacancy{}).
+		Preload("Disabilities").
+		Preload("Company").
+		Preload("Accommodations").
+		Where("vacancies.id IN ?", ids).
+		Find(&vacancies).Error
+	if err != nil {
+		return vacancies, vacancyRepoError("failed to list the vacancies by ids", "16").WithCause(err)
 	}
 
-	if searchText != "" {
-		query = query.Where("(vacancies.code LIKE ? OR vacancies.title LIKE ?)", "%"+searchText+"%", "%"+searchText+"%")
+	return vacancies, utils.Error{}
+}
+
+// filterVacancies applies the listing filters shared by the paginated listing and the export
+func filterVacancies(filters model.VacancyFilters) func(query *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if filters.IncludeDeleted {
+			query = query.Unscoped()
+		}
+
+		switch {
+		case !filters.IncludeExpired && filters.IncludeDrafts:
+			query = query.Where("vacancies.status = ? OR ("+openVacancyCondition+")", enum.VacancyDraft)
+		case !filters.IncludeExpired:
+			query = openVacancies(query)
+		case !filters.IncludeDrafts:
+			query = query.Where("vacancies.status <> ?", enum.VacancyDraft)
+		}
+
+		// areas are compared ignoring case, the stored casing is kept for display
+		if area := utils.CollapseSpaces(filters.Area); area != "" {
+			query = query.Where("LOWER(vacancies.area) = LOWER(?)", area)
+		}
+
+		if city := utils.CollapseSpaces(filters.City); city != "" {
+			query = query.Where("LOWER(vacancies.city) = LOWER(?)", city)
+		}
+
+		if state := strings.TrimSpace(filters.State); state != "" {
+			query = query.Where("LOWER(vacancies.state) = LOWER(?)", state)
+		}
+
+		if filters.CompanyId > 0 {
+			query = query.Where("vacancies.company_id = ?", filters.CompanyId)
+		}
+
+		if filters.ContractType != "" {
+			query = query.Where("vacancies.contract_type = ?", filters.ContractType)
+		}
+
+		if filters.VerifiedOnly {
+			query = query.Where("EXISTS (SELECT 1 FROM companies WHERE companies.id = vacancies.company_id AND companies.verified)")
+		}
+
+		if filters.PublishedAfter != nil {
+			query = query.Where("vacancies.published_at > ?", *filters.PublishedAfter)
+		}
+
+		if filters.PostedAfter != nil {
+			query = query.Where("vacancies.created_at >= ?", *filters.PostedAfter)
+		}
+
+		if filters.PostedBefore != nil {
+			query = query.Where("vacancies.created_at <= ?", *filters.PostedBefore)
+		}
+
+		if filters.WorkMode != "" {
+			query = query.Where("vacancies.work_mode = ?", filters.WorkMode)
+		}
+
+		// search text matches the vacancy code, title and description, the text of
+		// its requirements and the owning company name, ignoring case
+		if searchText := strings.TrimSpace(filters.SearchText); searchText != "" {
+			query = query.Where(
+				`(LOWER(vacancies.code) LIKE @search OR LOWER(vacancies.title) LIKE @search OR LOWER(vacancies.description) LIKE @search
+				OR EXISTS (SELECT 1 FROM vacancy_requirements WHERE vacancy_requirements.vacancy_id = vacancies.id AND vacancy_requirements.deleted_at IS NULL AND LOWER(vacancy_requirements.requirement) LIKE @search)
+				OR EXISTS (SELECT 1 FROM companies WHERE companies.id = vacancies.company_id AND LOWER(companies.name) LIKE @search))`,
+				sql.Named("search", "%"+strings.ToLower(searchText)+"%"),
+			)
+		}
+
+		if filters.SalaryMin != nil {
+			query = query.Where("COALESCE(vacancies.salary_max, vacancies.salary_min) >= ?", *filters.SalaryMin)
+		}
+
+		if filters.SalaryMax != nil {
+			query = query.Where("COALESCE(vacancies.salary_min, vacancies.salary_max) <= ?", *filters.SalaryMax)
+		}
+
+		if filters.DisabilityId > 0 {
+			query = query.Where(
+				"EXISTS (SELECT 1 FROM vacancy_disabilities WHERE vacancy_disabilities.vacancy_id = vacancies.id AND vacancy_disabilities.disability_id = ?)",
+				filters.DisabilityId,
+			)
+		}
+
+		if len(filters.DisabilityCategories) > 0 {
+			query = query.Where(
+				"EXISTS (SELECT 1 FROM vacancy_disabilities JOIN disabilities ON disabilities.id = vacancy_disabilities.disability_id WHERE vacancy_disabilities.vacancy_id = vacancies.id AND disabilities.category IN ?)",
+				filters.DisabilityCategories,
+			)
+		}
+
+		if filters.EligibleCandidateId > 0 {
+			query = query.Where(
+				`EXISTS (SELECT 1 FROM vacancy_disabilities
+				JOIN disabilities vacancy_disability ON vacancy_disability.id = vacancy_disabilities.disability_id
+				JOIN disabilities candidate_disability ON candidate_disability.category = vacancy_disability.category
+				JOIN person_disabilities ON person_disabilities.disability_id = candidate_disability.id
+				WHERE vacancy_disabilities.vacancy_id = vacancies.id AND person_disabilities.person_id = ?)`,
+				filters.EligibleCandidateId,
+			)
+		}
+
+		if filters.ExcludeAppliedCandidateId > 0 {
+			query = query.Where(
+				"NOT EXISTS (SELECT 1 FROM vacancy_applies WHERE vacancy_applies.vacancy_id = vacancies.id AND vacancy_applies.candidate_id = ?)",
+				filters.ExcludeAppliedCandidateId,
+			)
+		}
+
+		if filters.CandidateId > 0 {
+			query = query.Where(
+				"EXISTS (SELECT 1 FROM vacancy_applies WHERE vacancy_applies.vacancy_id = vacancies.id AND vacancy_applies.candidate_id = ?)",
+				filters.CandidateId,
+			)
+		}
+
+		// every required accommodation must be offered
+		if len(filters.Accommodations) > 0 {
+			query = query.Where(
+				"(SELECT COUNT(DISTINCT vacancy_accommodations.accommodation) FROM vacancy_accommodations WHERE vacancy_accommodations.vacancy_id = vacancies.id AND vacancy_accommodations.deleted_at IS NULL AND vacancy_accommodations.accommodation IN ?) = ?",
+				filters.Accommodations, len(filters.Accommodations),
+			)
+		}
+
+		if filters.RadiusKm > 0 && filters.Latitude != nil && filters.Longitude != nil {
+			query = withinRadius(query, *filters.Latitude, *filters.Longitude, filters.RadiusKm)
+		}
+
+		return query
 	}
+}
+
+// withinRadius keeps the vacancies up to radiusKm from the point. The bounding box lets
+// mysql use the coordinates index before the haversine distance is computed
+func withinRadius(query *gorm.DB, latitude float64, longitude float64, radiusKm float64) *gorm.DB {
+	latitudeDelta := radiusKm / kmPerLatitudeDegree
+
+	query = query.Where(
+		"vacancies.latitude BETWEEN ? AND ? AND vacancies.longitude IS NOT NULL",
+		latitude-latitudeDelta, latitude+latitudeDelta,
+	)
+
+	// near the poles or across the antimeridian the longitude box would wrap around,
+	// so only the distance below filters
+	cosLatitude := math.Cos(latitude * math.Pi / 180)
+	if cosLatitude > 0.01 {
+		longitudeDelta := latitudeDelta / cosLatitude
+		if longitude-longitudeDelta >= -180 && longitude+longitudeDelta <= 180 {
+			query = query.Where("vacancies.longitude BETWEEN ? AND ?", longitude-longitudeDelta, longitude+longitudeDelta)
+		}
+	}
+
+	return query.Where(
+		`? * 2 * ASIN(SQRT(
+			POWER(SIN(RADIANS(vacancies.latitude - ?) / 2), 2) +
+			COS(RADIANS(?)) * COS(RADIANS(vacancies.latitude)) * POWER(SIN(RADIANS(vacancies.longitude - ?) / 2), 2)
+		)) <= ?`,
+		earthRadiusKm, latitude, latitude, longitude, radiusKm,
+	)
+}
+
+func (v *vacancyRepo) ListAllVacancies(filters model.VacancyFilters) ([]model.Vacancy, utils.Error) {
+	var vacancies []model.Vacancy
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(filterVacancies(filters)).
+		Preload("Disabilities").
+		Preload("Company").
+		Preload("Accommodations").
+		Order(vacancyOrderClause(filters.SortBy, filters.SortOrder)).
+		Find(&vacancies).Error
+	if err != nil {
+		return vacancies, vacancyRepoError("failed to list the vacancies", "09").WithCause(err)
+	}
+
+	return vacancies, utils.Error{}
+}
 
-	err := query.Find(&vacancies).Error
+// ListVacanciesBatch lists up to limit of the vacancies matching the filters after skipping
+// offset of them, the order ends on the id so the consecutive batches don't overlap
+func (v *vacancyRepo) ListVacanciesBatch(filters model.VacancyFilters, offset int, limit int) ([]model.Vacancy, utils.Error) {
+	var vacancies []model.Vacancy
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(filterVacancies(filters)).
+		Preload("Disabilities").
+		Preload("Company").
+		Order(vacancyOrderClause(filters.SortBy, filters.SortOrder)).
+		Offset(offset).
+		Limit(limit).
+		Find(&vacancies).Error
 	if err != nil {
-		return vacancies, vacancyRepoError("failed to list the vacancies", "02")
+		return vacancies, vacancyRepoError("failed to list the vacancies batch", "33").WithCause(err)
 	}
 
 	return vacancies, utils.Error{}
 }
 
+// featuredFirstClause sorts the featured vacancies that did not expire their featuring
+// before the others
+const featuredFirstClause = "CASE WHEN vacancies.featured AND (vacancies.featured_until IS NULL OR vacancies.featured_until > NOW()) THEN 0 ELSE 1 END"
+
+func vacancyOrderClause(sortBy enum.VacancySortBy, sortOrder enum.SortOrderEnum) string {
+	column := "vacancies.created_at"
+
+	switch sortBy {
+	case enum.VacancySortByTitle:
+		column = "vacancies.title"
+	case enum.VacancySortBySalary:
+		column = "COALESCE(vacancies.salary_min, vacancies.salary_max)"
+	}
+
+	direction := "DESC"
+	if sortOrder == enum.Asc {
+		direction = "ASC"
+	}
+
+	return column + " " + direction + ", vacancies.id " + direction
+}
+
+// UpsertVacancy creates the vacancy when it has no id. Otherwise the vacancy is only
+// updated while its stored version is still the version it carries, bumping the version
+// so concurrent updates can't silently overwrite each other
 func (v *vacancyRepo) UpsertVacancy(vacancy model.Vacancy, tx *gorm.DB) (int, utils.Error) {
 	databaseConn := v.db
 
@@ -98,31 +465,394 @@ func (v *vacancyRepo) UpsertVacancy(vacancy model.Vacancy, tx *gorm.DB) (int, ut
 		databaseConn = tx
 	}
 
-	if err := databaseConn.Create(&vacancy).Error; err != nil {
-		return 0, vacancyRepoError("failed to create the vacancy", "03")
+	if vacancy.Id == 0 {
+		if err := databaseConn.Create(&vacancy).Error; err != nil {
+			return 0, vacancyRepoError("failed to create the vacancy", "03").WithCause(err)
+		}
+
+		return vacancy.Id, utils.Error{}
+	}
+
+	expectedVersion := vacancy.Version
+	vacancy.Version = expectedVersion + 1
+
+	result := databaseConn.Model(model.Vacancy{}).Where("id = ? AND version = ?", vacancy.Id, expectedVersion).Updates(vacancy)
+	if result.Error != nil {
+		return 0, vacancyRepoError("failed to update the vacancy", "04").WithCause(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return 0, utils.NewError("the vacancy was modified by another request", VacancyModifiedErrorCode)
 	}
 
 	return vacancy.Id, utils.Error{}
 }
 
-func (v *vacancyRepo) UpdateVacancy(vacancy model.Vacancy, tx *gorm.DB) utils.Error {
+func (v *vacancyRepo) DeleteVacancy(id int, tx *gorm.DB) utils.Error {
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Where("id = ?", id).Delete(&model.Vacancy{}).Error; err != nil {
+		return vacancyRepoError("failed to delete the vacancy", "04").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+// FeatureVacancy sets the featured flag with a map, so unsetting it is not skipped as
+// a zero value
+func (v *vacancyRepo) FeatureVacancy(id int, featured bool, featuredUntil *time.Time, tx *gorm.DB) utils.Error {
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Model(&model.Vacancy{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"featured":       featured,
+		"featured_until": featuredUntil,
+	}).Error; err != nil {
+		return vacancyRepoError("failed to feature the vacancy", "28").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+// ListPopularVacancies ranks the open vacancies by their applies since the given time,
+// counting them in the same grouped query
+func (v *vacancyRepo) ListPopularVacancies(since time.Time, limit int) ([]model.Vacancy, utils.Error) {
+	var vacancies []model.Vacancy
+
+	err := v.db.Model(&model.Vacancy{}).
+		Select("vacancies.*, COUNT(vacancy_applies.id) AS applies_count").
+		Joins("JOIN vacancy_applies ON vacancy_applies.vacancy_id = vacancies.id AND vacancy_applies.created_at >= ?", since).
+		Scopes(openVacancies).
+		Group("vacancies.id").
+		Order("applies_count DESC, vacancies.id DESC").
+		Limit(limit).
+		Preload("Disabilities").
+		Preload("Company").
+		Preload("Accommodations").
+		Find(&vacancies).Error
+	if err != nil {
+		return vacancies, vacancyRepoError("failed to list the popular vacancies", "30").WithCause(err)
+	}
+
+	return vacancies, utils.Error{}
+}
+
+// vacancyStatsPeriods truncates the event date to the start of its bucket, the intervals
+// are validated by the service so only these expressions reach the query
+var vacancyStatsPeriods = map[enum.VacancyStatsInterval]string{
+	enum.VacancyStatsDay:   "DATE_FORMAT(events.happened_at, '%Y-%m-%d')",
+	enum.VacancyStatsWeek:  "DATE_FORMAT(DATE_SUB(DATE(events.happened_at), INTERVAL WEEKDAY(events.happened_at) DAY), '%Y-%m-%d')",
+	enum.VacancyStatsMonth: "DATE_FORMAT(events.happened_at, '%Y-%m-01')",
+}
+
+// VacancyStats counts the vacancies created and closed in the range, from inclusive and
+// to exclusive, grouped by the period of the interval in a single query
+func (v *vacancyRepo) VacancyStats(from time.Time, to time.Time, interval enum.VacancyStatsInterval) ([]model.VacancyStatsBucket, utils.Error) {
+	buckets := []model.VacancyStatsBucket{}
+
+	period, ok := vacancyStatsPeriods[interval]
+	if !ok {
+		return buckets, vacancyRepoError("invalid vacancy stats interval", "32")
+	}
+
+	err := v.db.Raw(
+		`SELECT `+period+` AS period, SUM(events.kind = 'created') AS created, SUM(events.kind = 'closed') AS closed
+		FROM (
+			SELECT created_at AS happened_at, 'created' AS kind FROM vacancies WHERE deleted_at IS NULL AND created_at >= @from AND created_at < @to
+			UNION ALL
+			SELECT closed_at AS happened_at, 'closed' AS kind FROM vacancies WHERE deleted_at IS NULL AND closed_at >= @from AND closed_at < @to
+		) AS events
+		GROUP BY period
+		ORDER BY period`,
+		sql.Named("from", from),
+		sql.Named("to", to),
+	).Scan(&buckets).Error
+	if err != nil {
+		return []model.VacancyStatsBucket{}, vacancyRepoError("failed to count the vacancy stats", "31").WithCause(err)
+	}
+
+	return buckets, utils.Error{}
+}
+
+// IncrementVacancyView adds the view in the update itself, so concurrent views are not
+// lost to a read-modify-write. The updated at is kept, a view is not an edit
+func (v *vacancyRepo) IncrementVacancyView(id int) utils.Error {
+	if err := v.db.Model(&model.Vacancy{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error; err != nil {
+		return vacancyRepoError("failed to increment the vacancy views", "29").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (v *vacancyRepo) CloseVacancy(id int) utils.Error {
+	if err := v.db.Model(&model.Vacancy{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"closed_at": time.Now(),
+		"status":    enum.VacancyClosed,
+		"version":   gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return vacancyRepoError("failed to close the vacancy", "08").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (v *vacancyRepo) ReopenVacancy(id int, expiresAt *string, tx *gorm.DB) utils.Error {
 	databaseConn := v.db
 
 	if tx != nil {
 		databaseConn = tx
 	}
 
-	if err := databaseConn.Model(model.Vacancy{}).Where("id = ?", vacancy.Id).Updates(vacancy).Error; err != nil {
-		return vacancyRepoError("failed to update the vacancy", "04")
+	updates := map[string]interface{}{
+		"closed_at":  nil,
+		"expires_at": expiresAt,
+		"status":     enum.VacancyPublished,
+		"version":    gorm.Expr("version + 1"),
+	}
+
+	if err := databaseConn.Model(&model.Vacancy{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return vacancyRepoError("failed to reopen the vacancy", "14").WithCause(err)
 	}
 
 	return utils.Error{}
 }
 
-func (v *vacancyRepo) DeleteVacancy(id int) utils.Error {
-	if err := v.db.Where("id = ?", id).Delete(&model.Vacancy{}).Error; err != nil {
-		return vacancyRepoError("failed to delete the vacancy", "04")
+func (v *vacancyRepo) PublishVacancy(id int, tx *gorm.DB) utils.Error {
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	updates := map[string]interface{}{
+		"status":       enum.VacancyPublished,
+		"published_at": time.Now(),
+		"version":      gorm.Expr("version + 1"),
+	}
+
+	if err := databaseConn.Model(&model.Vacancy{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return vacancyRepoError("failed to publish the vacancy", "18").WithCause(err)
 	}
 
 	return utils.Error{}
 }
+
+func (v *vacancyRepo) ListVacancyIdsByCompany(companyId int, tx *gorm.DB) ([]int, utils.Error) {
+	ids := []int{}
+
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Model(&model.Vacancy{}).Where("company_id = ?", companyId).Pluck("id", &ids).Error; err != nil {
+		return []int{}, vacancyRepoError("failed to list the company vacancy ids", "21").WithCause(err)
+	}
+
+	return ids, utils.Error{}
+}
+
+// ListCompanyIdsWithOpenVacancies pages through the companies having open vacancies,
+// ordered by the company name
+func (v *vacancyRepo) ListCompanyIdsWithOpenVacancies(page int, perPage int) ([]int, int, utils.Error) {
+	companyIds := []int{}
+	var total int64
+
+	query := v.db.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Joins("JOIN companies ON companies.id = vacancies.company_id AND companies.deleted_at IS NULL")
+
+	if err := query.Session(&gorm.Session{}).Distinct("vacancies.company_id").Count(&total).Error; err != nil {
+		return []int{}, 0, vacancyRepoError("failed to count the companies with open vacancies", "23").WithCause(err)
+	}
+
+	err := query.
+		Group("vacancies.company_id, companies.name").
+		Order("companies.name, vacancies.company_id").
+		Offset((page-1)*perPage).
+		Limit(perPage).
+		Pluck("vacancies.company_id", &companyIds).Error
+	if err != nil {
+		return []int{}, 0, vacancyRepoError("failed to list the companies with open vacancies", "24").WithCause(err)
+	}
+
+	return companyIds, int(total), utils.Error{}
+}
+
+// ListOpenVacanciesByCompanies returns up to perCompany of the newest open vacancies of
+// each company in a single query
+func (v *vacancyRepo) ListOpenVacanciesByCompanies(companyIds []int, perCompany int) ([]model.Vacancy, utils.Error) {
+	var vacancies []model.Vacancy
+
+	ranked := v.db.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Where("vacancies.company_id IN ?", companyIds).
+		Select("vacancies.id, ROW_NUMBER() OVER (PARTITION BY vacancies.company_id ORDER BY vacancies.created_at DESC, vacancies.id DESC) AS position")
+
+	err := v.db.Model(&model.Vacancy{}).
+		Joins("JOIN (?) AS ranked ON ranked.id = vacancies.id", ranked).
+		Where("ranked.position <= ?", perCompany).
+		Preload("Disabilities").
+		Preload("Company").
+		Preload("Accommodations").
+		Order("vacancies.created_at DESC, vacancies.id DESC").
+		Find(&vacancies).Error
+	if err != nil {
+		return vacancies, vacancyRepoError("failed to list the company vacancies", "25").WithCause(err)
+	}
+
+	return vacancies, utils.Error{}
+}
+
+func (v *vacancyRepo) CountOpenVacanciesByCompanies(companyIds []int) (map[int]int, utils.Error) {
+	var rows []struct {
+		CompanyId int
+		Total     int
+	}
+
+	totals := map[int]int{}
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Where("vacancies.company_id IN ?", companyIds).
+		Select("vacancies.company_id AS company_id, COUNT(*) AS total").
+		Group("vacancies.company_id").
+		Scan(&rows).Error
+	if err != nil {
+		return totals, vacancyRepoError("failed to count the company vacancies", "26").WithCause(err)
+	}
+
+	for _, row := range rows {
+		totals[row.CompanyId] = row.Total
+	}
+
+	return totals, utils.Error{}
+}
+
+// TransferVacancies moves every vacancy of a company to another one, returning how
+// many were moved
+func (v *vacancyRepo) TransferVacancies(fromCompanyId int, toCompanyId int, tx *gorm.DB) (int, utils.Error) {
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	updates := map[string]interface{}{
+		"company_id": toCompanyId,
+		"version":    gorm.Expr("version + 1"),
+	}
+
+	result := databaseConn.Model(&model.Vacancy{}).Where("company_id = ?", fromCompanyId).Updates(updates)
+	if result.Error != nil {
+		return 0, vacancyRepoError("failed to transfer the vacancies", "22").WithCause(result.Error)
+	}
+
+	return int(result.RowsAffected), utils.Error{}
+}
+
+func (v *vacancyRepo) CountVacanciesByCompany(companyId int, tx *gorm.DB) (int, utils.Error) {
+	var total int64
+
+	databaseConn := v.db
+
+	if tx != nil {
+		databaseConn = tx
+	}
+
+	if err := databaseConn.Model(&model.Vacancy{}).Scopes(openVacancies).Where("vacancies.company_id = ?", companyId).Count(&total).Error; err != nil {
+		return 0, vacancyRepoError("failed to count the company vacancies", "06").WithCause(err)
+	}
+
+	return int(total), utils.Error{}
+}
+
+func (v *vacancyRepo) CountVacanciesGroupedByArea() (map[string]int, utils.Error) {
+	var rows []struct {
+		Area  string
+		Total int
+	}
+
+	totals := map[string]int{}
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Select("MIN(vacancies.area) AS area, COUNT(*) AS total").
+		Group("LOWER(vacancies.area)").
+		Scan(&rows).Error
+	if err != nil {
+		return totals, vacancyRepoError("failed to count the vacancies by area", "07").WithCause(err)
+	}
+
+	for _, row := range rows {
+		totals[row.Area] = row.Total
+	}
+
+	return totals, utils.Error{}
+}
+
+func (v *vacancyRepo) CountVacanciesGroupedByContractType(filters model.VacancyFilters) (map[enum.VacancyContractType]int, utils.Error) {
+	var rows []struct {
+		ContractType enum.VacancyContractType
+		Total        int
+	}
+
+	totals := map[enum.VacancyContractType]int{}
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(filterVacancies(filters)).
+		Select("vacancies.contract_type AS contract_type, COUNT(*) AS total").
+		Group("vacancies.contract_type").
+		Scan(&rows).Error
+	if err != nil {
+		return totals, vacancyRepoError("failed to count the vacancies by contract type", "17").WithCause(err)
+	}
+
+	for _, row := range rows {
+		totals[row.ContractType] = row.Total
+	}
+
+	return totals, utils.Error{}
+}
+
+func (v *vacancyRepo) ListVacancyAreas() ([]string, utils.Error) {
+	areas := []string{}
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Where("vacancies.area <> ''").
+		Select("MIN(vacancies.area) AS area").
+		Group("LOWER(vacancies.area)").
+		Order("area").
+		Scan(&areas).Error
+	if err != nil {
+		return []string{}, vacancyRepoError("failed to list the vacancy areas", "10").WithCause(err)
+	}
+
+	return areas, utils.Error{}
+}
+
+func (v *vacancyRepo) ListVacancyCities() ([]string, utils.Error) {
+	cities := []string{}
+
+	err := v.db.Model(&model.Vacancy{}).
+		Scopes(openVacancies).
+		Where("vacancies.city IS NOT NULL AND vacancies.city <> ''").
+		Select("MIN(vacancies.city) AS city").
+		Group("LOWER(vacancies.city)").
+		Order("city").
+		Scan(&cities).Error
+	if err != nil {
+		return []string{}, vacancyRepoError("failed to list the vacancy cities", "27").WithCause(err)
+	}
+
+	return cities, utils.Error{}
+}
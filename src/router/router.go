@@ -2,12 +2,18 @@ package router
 
 import (
 	"cij_api/src/auth"
+	"cij_api/src/config"
 	"cij_api/src/controller"
+	"cij_api/src/health"
+	"cij_api/src/integration"
+	"cij_api/src/metrics"
 	"cij_api/src/middleware"
 	"cij_api/src/repo"
 	vacancy "cij_api/src/repo/vacancy"
 	"cij_api/src/service"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "cij_api/docs"
 
@@ -18,7 +24,17 @@ import (
 )
 
 func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
+	router.Use(newCors().Handler)
+	router.Use(middleware.RequestId)
+	router.Use(newBodyLimit().Handler)
+
+	mailer := newMailer()
+
 	userRepo := repo.NewUserRepo(db)
+	router.Use(middleware.NewActiveUser(userRepo).Handler)
+	passwordResetRepo := repo.NewPasswordResetRepo(db)
+	userService := service.NewUserService(userRepo, passwordResetRepo)
+	userController := controller.NewUserController(userService)
 	activityRepo := repo.NewActivityRepo(db)
 
 	addressRepo := repo.NewAddressRepo(db)
@@ -27,11 +43,16 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 	personDisabilityRepo := repo.NewPersonDisabilityRepo(db)
 
 	personRepo := repo.NewPersonRepo(db)
-	personService := service.NewPersonService(personRepo, userRepo, addressRepo, personDisabilityRepo, activityRepo)
+	personService := service.NewPersonService(personRepo, userRepo, addressRepo, personDisabilityRepo, activityRepo, mailer)
 	personController := controller.NewPersonController(personService)
 
+	vacancyApplyRepo := vacancy.NewVacancyApplyRepo(db)
+
+	candidateService := service.NewCandidateService(personService, personRepo, personDisabilityRepo, vacancyApplyRepo)
+	candidateController := controller.NewCandidateController(candidateService)
+
 	companyRepo := repo.NewCompanyRepo(db)
-	companyService := service.NewCompanyService(companyRepo, userRepo, addressRepo, activityRepo)
+	companyService := service.NewCompanyService(companyRepo, userRepo, addressRepo, activityRepo, mailer)
 	companyController := controller.NewCompanyController(companyService)
 
 	newsRepo := repo.NewNewsRepo(db)
@@ -55,25 +76,45 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 	vacancySkillsRepo := vacancy.NewSkillsRepo(db)
 	vacancyRequirementsRepo := vacancy.NewRequirementsRepo(db)
 	vacancyResponsabilitiesRepo := vacancy.NewResponsabilitiesRepo(db)
+	vacancyAccommodationsRepo := vacancy.NewAccommodationsRepo(db)
 	vacancyDisabilitiesRepo := vacancy.NewVacancyDisabilityRepo(db)
-	vacancyApplyRepo := vacancy.NewVacancyApplyRepo(db)
+	vacancyIdempotencyKeyRepo := vacancy.NewIdempotencyKeyRepo(db)
+
+	auditLogRepo := repo.NewAuditLogRepo(db)
+	auditLogService := service.NewAuditLogService(auditLogRepo)
+	auditLogController := controller.NewAuditLogController(auditLogService)
 
-	vacancyService := service.NewVacancyService(
+	vacancyService := service.InstrumentVacancyService(service.NewVacancyService(
 		vacancyRepo, vacancySkillsRepo, vacancyRequirementsRepo,
-		vacancyResponsabilitiesRepo, vacancyDisabilitiesRepo, vacancyApplyRepo, personRepo,
-		personDisabilityRepo,
-	)
+		vacancyResponsabilitiesRepo, vacancyAccommodationsRepo, vacancyDisabilitiesRepo, vacancyApplyRepo, vacancyIdempotencyKeyRepo, personRepo,
+		personDisabilityRepo, disabilityRepo, companyRepo, auditLogRepo, mailer,
+	), metrics.Default)
 	vacancyController := controller.NewVacancyController(vacancyService, companyService)
 
+	jobAlertRepo := vacancy.NewJobAlertRepo(db)
+	jobAlertService := service.NewJobAlertService(jobAlertRepo, vacancyRepo, personRepo, mailer)
+	jobAlertController := controller.NewJobAlertController(jobAlertService)
+
+	go runJobAlerts(jobAlertService)
+
 	reportsService := service.NewReportsService(personDisabilityRepo, activityRepo)
 	reportsController := controller.NewReportsController(reportsService)
 
+	healthService := health.NewHealthService(db)
+	healthController := health.NewHealthController(healthService)
+
 	router.Get("/health", HealthCheck)
+	router.Get("/health/live", healthController.Liveness)
+	router.Get("/health/ready", healthController.Readiness)
+	router.Get("/version", healthController.Version)
+	router.Get("/metrics", metrics.Handler)
 
 	router.Get("/swagger/*", swagger.HandlerDefault)
 
-	router.Post("/login", authController.Authenticate)
+	router.Post("/login", newLoginRateLimiter().Handler, authController.Authenticate)
 	router.Post("/get-user-data", authController.GetUserData)
+	router.Post("/password-reset", userController.RequestPasswordReset)
+	router.Post("/password-reset/confirm", userController.ResetPassword)
 
 	api := router.Group("/people")
 	{
@@ -89,14 +130,40 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 		api.Post("/:id/curriculum", personController.UploadCurriculum)
 	}
 
+	api = router.Group("/users")
+	{
+		api.Use(middleware.Authenticated)
+		api.Get("/", middleware.AuthAdmin, userController.ListUsers)
+		api.Get("/me", userController.Me)
+		api.Patch("/:id", userController.UpdateUser)
+		api.Patch("/:id/deactivate", middleware.AuthAdmin, userController.DeactivateUser)
+		api.Patch("/:id/reactivate", middleware.AuthAdmin, userController.ReactivateUser)
+	}
+
+	api = router.Group("/candidates")
+	{
+		api.Post("/:id/resume", middleware.AuthUser, candidateController.UploadResume)
+		api.Get("/:id/data", middleware.AuthUser, candidateController.ExportCandidateData)
+		api.Delete("/:id/data", middleware.AuthUser, candidateController.DeleteCandidateData)
+
+		api.Use(middleware.AuthCompany)
+		api.Get("/", candidateController.ListCandidates)
+		api.Get("/search", candidateController.SearchCandidates)
+		api.Get("/:id", candidateController.GetCandidate)
+	}
+
 	api = router.Group("/companies")
 	{
-		api.Get("/", companyController.ListCompanies)
-		api.Get("/:id", companyController.GetCompany)
+		api.Get("/", middleware.OptionalAuth, companyController.ListCompanies)
+		api.Get("/by-cnpj", middleware.OptionalAuth, companyController.GetCompanyByCnpj)
+		api.Get("/:id", middleware.OptionalAuth, companyController.GetCompany)
+		api.Post("/:id/logo", middleware.AuthCompany, companyController.UploadCompanyLogo)
 
 		api.Use(middleware.AuthAdmin)
 		api.Post("/", companyController.CreateCompany)
 		api.Put("/:id", companyController.UpdateCompany)
+		api.Patch("/:id/verify", companyController.VerifyCompany)
+		api.Patch("/:id/unverify", companyController.UnverifyCompany)
 		api.Delete("/:id", companyController.DeleteCompany)
 	}
 
@@ -113,6 +180,7 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 
 	api = router.Group("/disabilities")
 	{
+		api.Get("/", disabilityController.ListDisabilities)
 		api.Post("/", disabilityController.CreateDisability)
 	}
 
@@ -124,21 +192,62 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 		api.Post("/", activityController.CreateActivity)
 	}
 
+	api = router.Group("/audit-logs")
+	{
+		api.Use(middleware.AuthAdmin)
+		api.Get("/", auditLogController.ListAuditLogs)
+	}
+
 	api = router.Group("/vacancies")
 	{
 		api.Get("/", vacancyController.ListVacancies)
-		api.Get("/:id", vacancyController.GetVacancyById)
+		api.Get("/admin", middleware.AuthAdmin, vacancyController.ListVacanciesAdmin)
+		api.Get("/export", middleware.AuthAdmin, vacancyController.ExportVacancies)
+		api.Get("/areas", vacancyController.ListVacancyAreas)
+		api.Get("/cities", vacancyController.ListVacancyCities)
+		api.Get("/popular", vacancyController.ListPopularVacancies)
+		api.Get("/facets", vacancyController.VacancyFacets)
+		api.Get("/stats/areas", vacancyController.CountVacanciesGroupedByArea)
+		api.Get("/stats/timeline", middleware.AuthAdmin, vacancyController.VacancyStats)
+		api.Get("/stats/companies/:id", middleware.AuthCompany, vacancyController.CountVacanciesByCompany)
+		api.Get("/slug/:slug", middleware.OptionalAuth, vacancyController.GetVacancyBySlug)
+		api.Get("/batch", vacancyController.GetVacanciesByIds)
+		api.Get("/companies", vacancyController.ListCompaniesWithVacancies)
+		api.Get("/:id", middleware.OptionalAuth, vacancyController.GetVacancyById)
 		api.Post("/apply", vacancyController.CandidateApply)
+		api.Get("/apply/candidate/:id", middleware.AuthUser, vacancyController.ListCandidateApplies)
+		api.Get("/applications/candidate/:id", middleware.AuthUser, vacancyController.ListApplicationsByCandidate)
+		api.Patch("/apply/:id/withdraw", middleware.AuthUser, vacancyController.WithdrawApplication)
+		api.Get("/candidate/:id/eligible", middleware.AuthUser, vacancyController.ListVacanciesForCandidate)
 
 		api.Use(middleware.AuthCompany)
 		api.Post("/", vacancyController.CreateVacancy)
 		api.Put("/:id", vacancyController.UpdateVacancy)
 		api.Delete("/:id", vacancyController.DeleteVacancy)
-
+		api.Patch("/:id/close", vacancyController.CloseVacancy)
+		api.Patch("/:id/reopen", vacancyController.ReopenVacancy)
+		api.Patch("/:id/publish", vacancyController.PublishVacancy)
+		api.Post("/:id/duplicate", vacancyController.DuplicateVacancy)
+		api.Patch("/:id/featured", middleware.AuthAdmin, vacancyController.FeatureVacancy)
+		api.Get("/companies/:id", vacancyController.ListCompanyVacancies)
+		api.Post("/companies/:id/import", vacancyController.ImportVacancies)
+		api.Post("/companies/:id/transfer", middleware.AuthAdmin, vacancyController.TransferVacancies)
+
+		api.Patch("/apply/bulk-status", vacancyController.BulkUpdateApplicationStatus)
 		api.Get("/apply/:id", vacancyController.ListVacancyApplies)
 		api.Patch("/apply/:id", vacancyController.UpdateVacancyApplyStatus)
 	}
 
+	api = router.Group("/job-alerts")
+	{
+		api.Use(middleware.AuthUser)
+		api.Post("/", jobAlertController.CreateJobAlert)
+		api.Get("/candidate/:id", jobAlertController.ListJobAlerts)
+		api.Patch("/:id/pause", jobAlertController.PauseJobAlert)
+		api.Patch("/:id/resume", jobAlertController.ResumeJobAlert)
+		api.Delete("/:id", jobAlertController.DeleteJobAlert)
+	}
+
 	api = router.Group("/reports")
 	{
 		api.Get("/disabilities", reportsController.GetDisabilityTotals)
@@ -150,7 +259,7 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 	fmt.Printf("API Routes:\n")
 
 	for _, r := range router.GetRoutes() {
-		if (r.Method == "GET" || r.Method == "POST" || r.Method == "PUT" || r.Method == "DELETE") && r.Path != "/health" && r.Path != "/" {
+		if (r.Method == "GET" || r.Method == "POST" || r.Method == "PUT" || r.Method == "DELETE") && !strings.HasPrefix(r.Path, "/health") && r.Path != "/" {
 			fullPath := basePath + r.Path
 			paintMethod(r.Method)
 			paintPath(fullPath)
@@ -160,6 +269,104 @@ func NewRouter(router *fiber.App, db *gorm.DB) *fiber.App {
 	return router
 }
 
+func newLoginRateLimiter() *middleware.RateLimiter {
+	attempts, window, keyFunc := 5, 15*time.Minute, middleware.IpRateLimitKey
+
+	rateLimitConfig, err := config.LoadRateLimitConfig(".")
+	if err == nil {
+		if rateLimitConfig.LoginAttempts > 0 {
+			attempts = rateLimitConfig.LoginAttempts
+		}
+
+		if rateLimitConfig.LoginWindow > 0 {
+			window = rateLimitConfig.LoginWindow
+		}
+
+		if rateLimitConfig.LoginByEmail {
+			keyFunc = middleware.IpAndEmailRateLimitKey
+		}
+	}
+
+	return middleware.NewRateLimiter(attempts, window, keyFunc)
+}
+
+func newBodyLimit() *middleware.BodyLimit {
+	maxBodySize := 1 << 20
+
+	requestConfig, err := config.LoadRequestConfig(".")
+	if err == nil && requestConfig.MaxBodySize > 0 {
+		maxBodySize = requestConfig.MaxBodySize
+	}
+
+	return middleware.NewBodyLimit(maxBodySize)
+}
+
+func newCors() *middleware.Cors {
+	corsConfig, err := config.LoadCorsConfig(".")
+	if err != nil {
+		// without a config only the production frontend is allowed
+		corsConfig = config.CorsConfig{
+			AllowedOrigins:   "https://conexao-inclusao.com",
+			AllowedMethods:   "GET,POST,PUT,PATCH,DELETE",
+			AllowedHeaders:   "Origin,Content-Type,Accept,Authorization,Idempotency-Key,X-Request-Id",
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		}
+	}
+
+	return middleware.NewCors(
+		splitConfigList(corsConfig.AllowedOrigins),
+		splitConfigList(corsConfig.AllowedMethods),
+		splitConfigList(corsConfig.AllowedHeaders),
+		corsConfig.AllowCredentials,
+		corsConfig.MaxAge,
+	)
+}
+
+func splitConfigList(value string) []string {
+	items := []string{}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// runJobAlerts runs the job alerts on the configured interval for as long as the api is up
+func runJobAlerts(jobAlertService service.JobAlertService) {
+	interval := time.Hour
+
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err == nil && vacancyConfig.JobAlertsInterval > 0 {
+		interval = vacancyConfig.JobAlertsInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := jobAlertService.RunJobAlerts(); err.IsError() {
+			fmt.Println("Error: ", err)
+		}
+	}
+}
+
+func newMailer() integration.Mailer {
+	mailerConfig, err := config.LoadMailerConfig(".")
+	if err != nil {
+		panic("failed to load mailer config")
+	}
+
+	if !mailerConfig.Enabled {
+		return integration.NewNoopMailer()
+	}
+
+	return integration.NewSmtpMailer(mailerConfig)
+}
+
 func getBasePath() string {
 	return "http://localhost:3040"
 }
@@ -30,7 +30,7 @@ func (a *activityService) GetActivitiesByTypeAndPeriod(activityType string, star
 	endDateStr := utils.GetFormattedDate(endDate)
 
 	activities, err := a.activityRepo.GetActivitiesByTypeAndPeriod(activityType, startDateStr, endDateStr)
-	if err.Code != "" {
+	if err.IsError() {
 		return nil, err
 	}
 
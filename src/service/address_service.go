@@ -22,7 +22,7 @@ func NewAddressService(addressRepo repo.AddressRepo) AddressService {
 
 func (n *addressService) GetAddressById(id int) (model.Address, utils.Error) {
 	address, err := n.addressRepo.GetAddressById(id)
-	if err.Code != "" {
+	if err.IsError() {
 		return address, err
 	}
 
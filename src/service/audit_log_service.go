@@ -0,0 +1,39 @@
+package service
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/model"
+	"cij_api/src/repo"
+	"cij_api/src/utils"
+)
+
+type AuditLogService interface {
+	ListAuditLogs(entity string, entityId int) ([]model.AuditLog, utils.Error)
+}
+
+type auditLogService struct {
+	auditLogRepo repo.AuditLogRepo
+}
+
+func NewAuditLogService(auditLogRepo repo.AuditLogRepo) AuditLogService {
+	return &auditLogService{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+func (a *auditLogService) ListAuditLogs(entity string, entityId int) ([]model.AuditLog, utils.Error) {
+	if !enum.AuditEntity(entity).IsValid() {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.AuditLogErrorType, "01")
+
+		return []model.AuditLog{}, utils.NewErrorWithFields("invalid entity", errorCode, []model.Field{{Name: "entity", Message: "invalid entity"}})
+	}
+
+	auditLogs, err := a.auditLogRepo.ListAuditLogs(enum.AuditEntity(entity), entityId)
+	if err.IsError() {
+		errorCode := utils.NewErrorCode(utils.ServiceErrorCode, utils.AuditLogErrorType, "01")
+
+		return []model.AuditLog{}, utils.NewError("failed to list the audit logs", errorCode).WithCause(err)
+	}
+
+	return auditLogs, utils.Error{}
+}
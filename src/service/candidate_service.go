@@ -0,0 +1,331 @@
+package service
+
+import (
+	"bytes"
+	"cij_api/src/config"
+	"cij_api/src/enum"
+	"cij_api/src/model"
+	"cij_api/src/repo"
+	repoVacancy "cij_api/src/repo/vacancy"
+	"cij_api/src/utils"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultMaxResumeSize = 5 * 1024 * 1024
+
+const (
+	defaultCandidatesPerPage = 20
+	maxCandidatesPerPage     = 100
+)
+
+type CandidateService interface {
+	CreateCandidate(candidate model.PersonRequest) utils.Error
+	GetCandidateById(candidateId int) (model.CandidateResponse, utils.Error)
+	ListCandidates() ([]model.CandidateResponse, utils.Error)
+	SearchCandidates(disabilityCategory string, area string, page int, perPage int, caller model.UserClaims) (model.PaginatedResponse[model.CandidateResponse], utils.Error)
+
+	UploadResume(candidateId int, file io.Reader, contentType string) (string, utils.Error)
+	ExportCandidateData(candidateId int, caller model.UserClaims) ([]byte, utils.Error)
+	DeleteCandidateData(candidateId int, caller model.UserClaims) utils.Error
+}
+
+type candidateService struct {
+	personService        PersonService
+	personRepo           repo.PersonRepo
+	personDisabilityRepo repo.PersonDisabilityRepo
+	vacancyApplyRepo     repoVacancy.VacancyApplyRepo
+}
+
+func NewCandidateService(
+	personService PersonService,
+	personRepo repo.PersonRepo,
+	personDisabilityRepo repo.PersonDisabilityRepo,
+	vacancyApplyRepo repoVacancy.VacancyApplyRepo,
+) CandidateService {
+	return &candidateService{
+		personService:        personService,
+		personRepo:           personRepo,
+		personDisabilityRepo: personDisabilityRepo,
+		vacancyApplyRepo:     vacancyApplyRepo,
+	}
+}
+
+func candidateServiceError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ServiceErrorCode, utils.CandidateErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (c *candidateService) CreateCandidate(candidate model.PersonRequest) utils.Error {
+	return c.personService.CreatePerson(candidate)
+}
+
+func (c *candidateService) GetCandidateById(candidateId int) (model.CandidateResponse, utils.Error) {
+	person, err := c.personRepo.GetPersonById(candidateId, nil)
+	if err.IsError() {
+		return model.CandidateResponse{}, candidateServiceError("failed to get the candidate", "01")
+	}
+
+	if person.Id == 0 {
+		return model.CandidateResponse{}, candidateServiceError("candidate not found", "02")
+	}
+
+	return c.candidateToResponse(person)
+}
+
+func (c *candidateService) ListCandidates() ([]model.CandidateResponse, utils.Error) {
+	candidatesResponse := []model.CandidateResponse{}
+
+	people, err := c.personRepo.ListPeople()
+	if err.IsError() {
+		return candidatesResponse, candidateServiceError("failed to list the candidates", "03")
+	}
+
+	for _, person := range people {
+		candidateResponse, err := c.candidateToResponse(person)
+		if err.IsError() {
+			return []model.CandidateResponse{}, err
+		}
+
+		candidatesResponse = append(candidatesResponse, candidateResponse)
+	}
+
+	return candidatesResponse, utils.Error{}
+}
+
+// SearchCandidates lets companies and admins browse the candidate pool by disability
+// category and area. The list view leaves out the cpf of the candidates
+func (c *candidateService) SearchCandidates(disabilityCategory string, area string, page int, perPage int, caller model.UserClaims) (model.PaginatedResponse[model.CandidateResponse], utils.Error) {
+	if caller.Role != string(enum.CompanyRole) && caller.Role != string(enum.AdminRole) {
+		errorCode := utils.NewErrorCode(utils.ForbiddenErrorCode, utils.CandidateErrorType, "09")
+
+		return model.PaginatedResponse[model.CandidateResponse]{}, utils.NewError("only companies and admins can search candidates", errorCode)
+	}
+
+	if page < 0 || perPage < 0 {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.CandidateErrorType, "10")
+
+		return model.PaginatedResponse[model.CandidateResponse]{}, utils.NewError("page and per page must not be negative", errorCode)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if perPage == 0 {
+		perPage = defaultCandidatesPerPage
+	}
+
+	if perPage > maxCandidatesPerPage {
+		perPage = maxCandidatesPerPage
+	}
+
+	filters := model.CandidateFilters{DisabilityCategory: disabilityCategory, Area: area}
+
+	people, total, err := c.personRepo.SearchCandidates(filters, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[model.CandidateResponse]{}, candidateServiceError("failed to search the candidates", "11")
+	}
+
+	candidatesResponse := []model.CandidateResponse{}
+	for _, person := range people {
+		disabilitiesResponse := []model.DisabilityResponse{}
+		for _, personDisability := range person.Disabilities {
+			if personDisability.Disability != nil {
+				disabilitiesResponse = append(disabilitiesResponse, personDisability.Disability.ToResponse())
+			}
+		}
+
+		var address model.Address
+		if person.Address != nil {
+			address = *person.Address
+		}
+
+		candidateResponse := person.ToCandidateResponse(disabilitiesResponse, address)
+		candidateResponse.Cpf = ""
+
+		candidatesResponse = append(candidatesResponse, candidateResponse)
+	}
+
+	return model.NewPaginatedResponse(candidatesResponse, total, page, perPage), utils.Error{}
+}
+
+func (c *candidateService) UploadResume(candidateId int, file io.Reader, contentType string) (string, utils.Error) {
+	if contentType != "application/pdf" {
+		return "", candidateServiceError("invalid resume type. the resume must be a pdf", "05")
+	}
+
+	maxResumeSize := int64(defaultMaxResumeSize)
+
+	filesConfig, err := config.LoadFilesConfig(".")
+	if err == nil && filesConfig.MaxResumeSize > 0 {
+		maxResumeSize = filesConfig.MaxResumeSize
+	}
+
+	resume, err := io.ReadAll(io.LimitReader(file, maxResumeSize+1))
+	if err != nil {
+		return "", candidateServiceError("failed to read the resume", "06")
+	}
+
+	if int64(len(resume)) > maxResumeSize {
+		return "", candidateServiceError("resume exceeds the maximum size of "+strconv.FormatInt(maxResumeSize/1024, 10)+"KB", "07")
+	}
+
+	if !bytes.HasPrefix(resume, []byte("%PDF-")) {
+		return "", candidateServiceError("invalid resume type. the resume must be a pdf", "05")
+	}
+
+	person, personError := c.personRepo.GetPersonById(candidateId, nil)
+	if personError.IsError() {
+		return "", candidateServiceError("failed to get the candidate", "01")
+	}
+
+	if person.Id == 0 {
+		return "", candidateServiceError("candidate not found", "02")
+	}
+
+	filesService := NewFilesService()
+	url, uploadError := filesService.UploadFile(bytes.NewReader(resume), "cij/curriculum/"+person.Cpf)
+	if uploadError != nil {
+		return "", candidateServiceError("failed to upload the resume", "08")
+	}
+
+	personError = c.personRepo.UploadCurriculum(candidateId, url)
+	if personError.IsError() {
+		return "", personError
+	}
+
+	return url, utils.Error{}
+}
+
+// candidateOwner returns the candidate when the caller is the candidate or an admin
+func (c *candidateService) candidateOwner(candidateId int, caller model.UserClaims) (model.Person, utils.Error) {
+	person, err := c.personRepo.GetPersonById(candidateId, nil)
+	if err.IsError() {
+		return model.Person{}, candidateServiceError("failed to get the candidate", "01")
+	}
+
+	if person.Id == 0 || person.AnonymizedAt != nil {
+		errorCode := utils.NewErrorCode(utils.NotFoundErrorCode, utils.CandidateErrorType, "02")
+
+		return model.Person{}, utils.NewError("candidate not found", errorCode)
+	}
+
+	if caller.Role != string(enum.AdminRole) && caller.Id != person.UserId {
+		errorCode := utils.NewErrorCode(utils.ForbiddenErrorCode, utils.CandidateErrorType, "12")
+
+		return model.Person{}, utils.NewError("only the candidate or an admin can manage the candidate data", errorCode)
+	}
+
+	return person, utils.Error{}
+}
+
+// ExportCandidateData returns as json the profile, applications and uploaded files of
+// the candidate, for the LGPD data access requests
+func (c *candidateService) ExportCandidateData(candidateId int, caller model.UserClaims) ([]byte, utils.Error) {
+	person, err := c.candidateOwner(candidateId, caller)
+	if err.IsError() {
+		return nil, err
+	}
+
+	profile, err := c.candidateToResponse(person)
+	if err.IsError() {
+		return nil, err
+	}
+
+	vacancyApplies, err := c.vacancyApplyRepo.ListVacancyAppliesByCandidateId(candidateId)
+	if err.IsError() {
+		return nil, candidateServiceError("failed to get the candidate applications", "13")
+	}
+
+	applications := []model.CandidateDataApplication{}
+	for _, vacancyApply := range vacancyApplies {
+		application := model.CandidateDataApplication{
+			VacancyId:       vacancyApply.VacancyId,
+			Status:          vacancyApply.Status,
+			AppliedAt:       vacancyApply.CreatedAt,
+			StatusUpdatedAt: vacancyApply.StatusUpdatedAt,
+		}
+
+		if vacancyApply.Vacancy != nil {
+			application.VacancyTitle = vacancyApply.Vacancy.Title
+			application.CompanyName = vacancyApply.Vacancy.Company.Name
+		}
+
+		applications = append(applications, application)
+	}
+
+	files := []string{}
+	if person.Curriculum != "" {
+		files = append(files, person.Curriculum)
+	}
+
+	export := model.CandidateDataExport{
+		ExportedAt:   time.Now(),
+		Profile:      profile,
+		Applications: applications,
+		Files:        files,
+	}
+
+	if person.User != nil {
+		export.Email = person.User.Email
+
+		if person.User.ConfigUrl != "" {
+			export.Files = append(export.Files, person.User.ConfigUrl)
+		}
+	}
+
+	data, jsonErr := json.Marshal(export)
+	if jsonErr != nil {
+		return nil, candidateServiceError("failed to export the candidate data", "14")
+	}
+
+	return data, utils.Error{}
+}
+
+// DeleteCandidateData anonymizes the personal data of the candidate, for the LGPD
+// erasure requests. The applications stay, without the candidate identity, so the
+// reports keep their totals
+func (c *candidateService) DeleteCandidateData(candidateId int, caller model.UserClaims) utils.Error {
+	person, err := c.candidateOwner(candidateId, caller)
+	if err.IsError() {
+		return err
+	}
+
+	errTx := c.personRepo.BeginTransaction(func(tx *gorm.DB) error {
+		if err := c.personRepo.AnonymizePerson(person, tx); err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+	if errTx != nil {
+		return candidateServiceError("failed to delete the candidate data", "15")
+	}
+
+	return utils.Error{}
+}
+
+func (c *candidateService) candidateToResponse(person model.Person) (model.CandidateResponse, utils.Error) {
+	personDisabilities, err := c.personDisabilityRepo.GetPersonDisabilities(person.Id)
+	if err.IsError() {
+		return model.CandidateResponse{}, candidateServiceError("failed to get the candidate disabilities", "04")
+	}
+
+	disabilitiesResponse := []model.DisabilityResponse{}
+	for _, personDisability := range personDisabilities {
+		disabilitiesResponse = append(disabilitiesResponse, personDisability.Disability.ToResponse())
+	}
+
+	var address model.Address
+	if person.Address != nil {
+		address = *person.Address
+	}
+
+	return person.ToCandidateResponse(disabilitiesResponse, address), utils.Error{}
+}
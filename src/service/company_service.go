@@ -1,30 +1,44 @@
 package service
 
 import (
+	"bytes"
+	"cij_api/src/config"
+	"cij_api/src/integration"
+	"cij_api/src/logger"
 	"cij_api/src/model"
 	"cij_api/src/repo"
 	"cij_api/src/utils"
+	"context"
 	"fmt"
+	"io"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
 type CompanyService interface {
 	CreateCompany(createCompany model.CompanyRequest) utils.Error
-	ListCompanies() ([]model.CompanyResponse, utils.Error)
+	ListCompanies(page int, perPage int) ([]model.CompanyResponse, utils.Error)
 	GetCompanyByUserId(userId int) (model.Company, utils.Error)
-	GetCompanyByCnpj(cnpj string) (model.Company, utils.Error)
-	GetCompanyById(companyId int) (model.Company, utils.Error)
+	GetCompanyByCnpj(cnpj string) (model.CompanyResponse, utils.Error)
+	GetCompanyById(companyId int) (model.CompanyResponse, utils.Error)
 	GetUserByEmail(email string) (model.User, utils.Error)
 	UpdateCompany(company model.CompanyRequest, companyId int) utils.Error
 	DeleteCompany(companyId int) utils.Error
+	VerifyCompany(companyId int) utils.Error
+	UnverifyCompany(companyId int) utils.Error
+
+	UploadCompanyLogo(companyId int, file io.Reader, contentType string) (string, utils.Error)
 }
 
+const maxCompanyLogoSize = 2 * 1024 * 1024
+
 type companyService struct {
 	companyRepo  repo.CompanyRepo
 	userRepo     repo.UserRepo
 	addressRepo  repo.AddressRepo
 	activityRepo repo.ActivityRepo
+	mailer       integration.Mailer
 }
 
 func NewCompanyService(
@@ -32,12 +46,14 @@ func NewCompanyService(
 	userRepo repo.UserRepo,
 	addressRepo repo.AddressRepo,
 	activityRepo repo.ActivityRepo,
+	mailer integration.Mailer,
 ) CompanyService {
 	return &companyService{
 		companyRepo:  companyRepo,
 		userRepo:     userRepo,
 		addressRepo:  addressRepo,
 		activityRepo: activityRepo,
+		mailer:       mailer,
 	}
 }
 
@@ -47,64 +63,77 @@ func companyServiceError(message string, code string) utils.Error {
 	return utils.NewError(message, errorCode)
 }
 
-func (s *companyService) ListCompanies() ([]model.CompanyResponse, utils.Error) {
+func companyNotFoundError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.NotFoundErrorCode, utils.CompanyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func companyConflictError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ConflictErrorCode, utils.CompanyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (s *companyService) ListCompanies(page int, perPage int) ([]model.CompanyResponse, utils.Error) {
 	companiesResponse := []model.CompanyResponse{}
 
-	companies, err := s.companyRepo.ListCompanies()
-	if err.Code != "" {
+	companies, err := s.companyRepo.ListCompanies(page, perPage)
+	if err.IsError() {
 		return companiesResponse, err
 	}
 
 	for _, company := range companies {
-		user, err := s.userRepo.GetUserById(company.User.Id)
-		if err.Code != "" {
-			return companiesResponse, err
-		}
+		companiesResponse = append(companiesResponse, s.companyToResponse(company))
+	}
 
-		companyResponse := company.ToResponse(user)
+	return companiesResponse, utils.Error{}
+}
 
-		address, err := s.addressRepo.GetAddressById(*company.AddressId)
-		if err.Code != "" {
-			return companiesResponse, err
-		}
+func (s *companyService) companyToResponse(company model.Company) model.CompanyResponse {
+	var user model.User
+	if company.User != nil {
+		user = *company.User
+	}
 
-		if address.Id != 0 {
-			addressResponse := address.ToResponse()
-			companyResponse.Address = addressResponse
-		}
+	companyResponse := company.ToResponse(user)
 
-		var userConfig interface{}
-		userConfig = model.DefaultConfig
+	var userConfig interface{}
+	userConfig = model.DefaultConfig
 
-		if user.ConfigUrl != "" {
-			configService := NewConfigService(s.userRepo)
-			userConfig, err = configService.GetUserConfig(user.ConfigUrl)
-			if err.Code != "" {
-				fmt.Println("Error:", err)
-			}
+	if user.ConfigUrl != "" {
+		configService := NewConfigService(s.userRepo)
+		config, err := configService.GetUserConfig(user.ConfigUrl)
+		if err.IsError() {
+			fmt.Println("Error:", err)
+		} else {
+			userConfig = config
 		}
-
-		companyResponse.User.Config = userConfig
-		companiesResponse = append(companiesResponse, companyResponse)
 	}
 
-	return companiesResponse, utils.Error{}
+	companyResponse.User.Config = userConfig
+
+	return companyResponse
 }
 
+// CreateCompany creates the company user and the company in a single transaction,
+// so a failure on any step rolls back the user as well
 func (n *companyService) CreateCompany(createCompany model.CompanyRequest) utils.Error {
-	userInfo := createCompany.ToUser()
+	companyDb, err := n.companyRepo.GetCompanyByCnpj(createCompany.Cnpj)
+	if err.IsError() {
+		return companyServiceError("failed to get the company", "11")
+	}
 
-	hashedPassword, err := utils.EncryptPassword(userInfo.Password)
-	if err != nil {
-		return companyServiceError("failed to encrypt the password", "01")
+	if companyDb.Id != 0 {
+		return companyConflictError("cnpj already registered", "10")
 	}
 
-	userInfo.Password = hashedPassword
+	userInfo := createCompany.ToUser()
 	userInfo.RoleId = model.CompanyRole
 
 	errTx := n.userRepo.BeginTransaction(func(tx *gorm.DB) error {
 		userId, userError := n.userRepo.CreateUser(userInfo, tx)
-		if userError.Code != "" {
+		if userError.IsError() {
 			fmt.Println("Error: ", userError)
 			return userError
 		}
@@ -112,7 +141,7 @@ func (n *companyService) CreateCompany(createCompany model.CompanyRequest) utils
 		addressInfo := createCompany.ToAddress()
 
 		addressId, addresError := n.addressRepo.UpsertAddress(addressInfo, tx)
-		if addresError.Code != "" {
+		if addresError.IsError() {
 			fmt.Println("Error: ", addresError)
 			return addresError
 		}
@@ -122,7 +151,7 @@ func (n *companyService) CreateCompany(createCompany model.CompanyRequest) utils
 		companyInfo.AddressId = &addressId
 
 		companyError := n.companyRepo.CreateCompany(companyInfo, tx)
-		if companyError.Code != "" {
+		if companyError.IsError() {
 			fmt.Println("Error: ", companyError)
 			return companyError
 		}
@@ -131,9 +160,21 @@ func (n *companyService) CreateCompany(createCompany model.CompanyRequest) utils
 	})
 
 	if errTx != nil {
+		if txError, ok := errTx.(utils.Error); ok {
+			switch txError.Code {
+			case repo.EmailAlreadyRegisteredErrorCode:
+				return companyConflictError(txError.Message, "09")
+			// a concurrent registration may have passed the cnpj check above
+			case repo.CnpjAlreadyRegisteredErrorCode:
+				return companyConflictError(txError.Message, "10")
+			}
+		}
+
 		return companyServiceError("failed to create the company", "02")
 	}
 
+	go n.sendWelcomeEmail(userInfo.Email, createCompany.Name)
+
 	activityService := NewActivityService(n.activityRepo)
 	activity := model.Activity{
 		Type:        "register_company",
@@ -142,7 +183,7 @@ func (n *companyService) CreateCompany(createCompany model.CompanyRequest) utils
 	}
 
 	activityError := activityService.CreateActivity(&activity)
-	if activityError.Code != "" {
+	if activityError.IsError() {
 		return activityError
 	}
 
@@ -151,35 +192,60 @@ func (n *companyService) CreateCompany(createCompany model.CompanyRequest) utils
 
 func (n *companyService) GetCompanyByUserId(userId int) (model.Company, utils.Error) {
 	company, err := n.companyRepo.GetCompanyByUserId(userId)
-	if err.Code != "" {
+	if err.IsError() {
 		return company, err
 	}
 
 	return company, utils.Error{}
 }
 
-func (n *companyService) GetCompanyByCnpj(cnpj string) (model.Company, utils.Error) {
+func (n *companyService) GetCompanyByCnpj(cnpj string) (model.CompanyResponse, utils.Error) {
+	if len(utils.NormalizeCnpj(cnpj)) != 14 {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.CompanyErrorType, "12")
+
+		return model.CompanyResponse{}, utils.NewErrorWithFields("the cnpj must have 14 digits", errorCode, []model.Field{{Name: "cnpj", Message: "the cnpj must have 14 digits"}})
+	}
+
 	company, err := n.companyRepo.GetCompanyByCnpj(cnpj)
-	if err.Code != "" {
-		return company, err
+	if err.IsError() {
+		return model.CompanyResponse{}, companyServiceError("failed to get the company", "12")
 	}
 
-	return company, utils.Error{}
+	if company.Id == 0 {
+		errorCode := utils.NewErrorCode(utils.NotFoundErrorCode, utils.CompanyErrorType, "13")
+
+		return model.CompanyResponse{}, utils.NewError("no company registered with the cnpj", errorCode)
+	}
+
+	return n.companyToResponse(company), utils.Error{}
 }
 
-func (n *companyService) GetCompanyById(companyId int) (model.Company, utils.Error) {
+func (n *companyService) GetCompanyById(companyId int) (model.CompanyResponse, utils.Error) {
 	company, err := n.companyRepo.GetCompanyById(companyId)
-	if err.Code != "" {
-		return company, err
+	if err.IsError() {
+		return model.CompanyResponse{}, err
 	}
 
-	return company, utils.Error{}
+	if company.Id == 0 {
+		return model.CompanyResponse{}, utils.Error{}
+	}
+
+	return n.companyToResponse(company), utils.Error{}
 }
 
 func (n *companyService) UpdateCompany(updateCompany model.CompanyRequest, companyId int) utils.Error {
 	userInfo := updateCompany.ToUser()
 
-	if userInfo.Password == "" {
+	company, companyError := n.companyRepo.GetCompanyById(companyId)
+	if companyError.IsError() {
+		return companyError
+	}
+
+	if company.Id == 0 {
+		return companyServiceError("company not found", "03")
+	}
+
+	if userInfo.Password != "" {
 		hashedPassword, err := utils.EncryptPassword(userInfo.Password)
 		if err != nil {
 			return companyServiceError("failed to encrypt the password", "02")
@@ -187,23 +253,18 @@ func (n *companyService) UpdateCompany(updateCompany model.CompanyRequest, compa
 
 		userInfo.Password = hashedPassword
 
-		userError := n.userRepo.UpdateUser(userInfo, companyId)
-		if userError.Code != "" {
+		userError := n.userRepo.UpdateUser(userInfo, company.UserId)
+		if userError.IsError() {
 			return userError
 		}
 	}
 
 	addressInfo := updateCompany.ToAddress()
 
-	company, companyError := n.companyRepo.GetCompanyById(companyId)
-	if companyError.Code != "" {
-		return companyError
-	}
-
 	addressInfo.Id = *company.AddressId
 
 	addressId, addresError := n.addressRepo.UpsertAddress(addressInfo, nil)
-	if addresError.Code != "" {
+	if addresError.IsError() {
 		return addresError
 	}
 
@@ -211,7 +272,7 @@ func (n *companyService) UpdateCompany(updateCompany model.CompanyRequest, compa
 	companyInfo.AddressId = &addressId
 
 	companyError = n.companyRepo.UpdateCompany(companyInfo, companyId)
-	if companyError.Code != "" {
+	if companyError.IsError() {
 		return companyError
 	}
 
@@ -220,33 +281,117 @@ func (n *companyService) UpdateCompany(updateCompany model.CompanyRequest, compa
 
 func (n *companyService) DeleteCompany(companyId int) utils.Error {
 	company, err := n.companyRepo.GetCompanyById(companyId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.companyRepo.DeleteCompany(companyId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.userRepo.DeleteUser(company.UserId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.addressRepo.DeleteAddress(*company.AddressId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	return utils.Error{}
 }
 
+// VerifyCompany marks the company as verified, the badge shows on the company and on
+// its vacancies
+func (n *companyService) VerifyCompany(companyId int) utils.Error {
+	return n.setCompanyVerified(companyId, true)
+}
+
+func (n *companyService) UnverifyCompany(companyId int) utils.Error {
+	return n.setCompanyVerified(companyId, false)
+}
+
+func (n *companyService) setCompanyVerified(companyId int, verified bool) utils.Error {
+	company, err := n.companyRepo.GetCompanyById(companyId)
+	if err.IsError() {
+		return err
+	}
+
+	if company.Id == 0 {
+		return companyNotFoundError("company not found", "08")
+	}
+
+	if err := n.companyRepo.SetCompanyVerified(companyId, verified); err.IsError() {
+		return companyServiceError("failed to update the company verification", "09")
+	}
+
+	return utils.Error{}
+}
+
+func (n *companyService) UploadCompanyLogo(companyId int, file io.Reader, contentType string) (string, utils.Error) {
+	if contentType != "image/png" && contentType != "image/jpeg" {
+		return "", companyServiceError("invalid logo type. valid types are: 'image/png', 'image/jpeg'", "04")
+	}
+
+	logo, err := io.ReadAll(io.LimitReader(file, maxCompanyLogoSize+1))
+	if err != nil {
+		return "", companyServiceError("failed to read the logo", "05")
+	}
+
+	if len(logo) > maxCompanyLogoSize {
+		return "", companyServiceError("logo exceeds the maximum size of 2MB", "06")
+	}
+
+	company, companyError := n.companyRepo.GetCompanyById(companyId)
+	if companyError.IsError() {
+		return "", companyError
+	}
+
+	if company.Id == 0 {
+		return "", companyServiceError("company not found", "03")
+	}
+
+	filesService := NewFilesService()
+	url, uploadError := filesService.UploadFile(bytes.NewReader(logo), "cij/logos/"+company.Cnpj)
+	if uploadError != nil {
+		return "", companyServiceError("failed to upload the logo", "07")
+	}
+
+	companyError = n.companyRepo.UpdateCompanyLogo(companyId, url)
+	if companyError.IsError() {
+		return "", companyError
+	}
+
+	return url, utils.Error{}
+}
+
 func (n *companyService) GetUserByEmail(email string) (model.User, utils.Error) {
 	user, err := n.userRepo.GetUserByEmail(email)
-	if err.Code != "" {
+	if err.IsError() {
 		return user, err
 	}
 
 	return user, utils.Error{}
 }
+
+// sendWelcomeEmail is only called once the registration transaction has committed
+func (n *companyService) sendWelcomeEmail(email string, name string) {
+	appUrl := "https://conexao-inclusao.com"
+
+	mailerConfig, err := config.LoadMailerConfig(".")
+	if err == nil && mailerConfig.AppUrl != "" {
+		appUrl = strings.TrimSuffix(mailerConfig.AppUrl, "/")
+	}
+
+	subject := "Bem-vindo(a) ao Conexão Inclusão"
+	body := fmt.Sprintf(
+		"Olá, %s!\n\nO cadastro da sua empresa no Conexão Inclusão foi realizado com sucesso.\n\nPublique sua primeira vaga em %s/vacancies/new",
+		name, appUrl,
+	)
+
+	if err := n.mailer.Send(email, subject, body); err != nil {
+		logger.FromContext(context.Background()).Error("failed to send the welcome email", "email", email, "cause", err.Error())
+	}
+}
@@ -68,7 +68,7 @@ func (s *configService) UploadUserConfig(email string, config *interface{}) util
 	}
 
 	updateUserErr := s.userRepo.UpdateUserConfig(fileUrl, email)
-	if updateUserErr.Code != "" {
+	if updateUserErr.IsError() {
 		return updateUserErr
 	}
 
@@ -9,6 +9,7 @@ import (
 
 type DisabilityService interface {
 	CreateDisability(disability []model.DisabilityRequest) utils.Error
+	ListDisabilities() ([]model.DisabilityResponse, utils.Error)
 }
 
 type disabilityService struct {
@@ -31,10 +32,27 @@ func (s *disabilityService) CreateDisability(disabilities []model.DisabilityRequ
 	}
 
 	err := s.disabilityRepo.BatchInsertDisabilities(disabilitiesToInsert)
-	if err.Code != "" {
+	if err.IsError() {
 		fmt.Println("Error", err.Message)
 		return err
 	}
 
 	return utils.Error{}
 }
+
+// ListDisabilities returns the catalog ordered by category, so the disabilities of
+// the same category come together
+func (s *disabilityService) ListDisabilities() ([]model.DisabilityResponse, utils.Error) {
+	disabilitiesResponse := []model.DisabilityResponse{}
+
+	disabilities, err := s.disabilityRepo.ListDisabilities()
+	if err.IsError() {
+		return disabilitiesResponse, err
+	}
+
+	for _, disability := range disabilities {
+		disabilitiesResponse = append(disabilitiesResponse, disability.ToResponse())
+	}
+
+	return disabilitiesResponse, utils.Error{}
+}
@@ -4,7 +4,7 @@ import (
 	"cij_api/src/config"
 	"cij_api/src/integration"
 	"context"
-	"mime/multipart"
+	"io"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
@@ -27,7 +27,7 @@ func NewFilesService() *filesService {
 	}
 }
 
-func (f *filesService) UploadFile(file multipart.File, filePath string) (string, error) {
+func (f *filesService) UploadFile(file io.Reader, filePath string) (string, error) {
 	ctx := context.Background()
 
 	uploadResult, err := f.cloudinaryIntegration.Upload.Upload(
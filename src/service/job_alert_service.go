@@ -0,0 +1,212 @@
+package service
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/integration"
+	"cij_api/src/logger"
+	"cij_api/src/model"
+	modelVacancy "cij_api/src/model/vacancy"
+	"cij_api/src/repo"
+	repoVacancy "cij_api/src/repo/vacancy"
+	"cij_api/src/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type JobAlertService interface {
+	CreateJobAlert(jobAlert modelVacancy.JobAlertRequest, caller model.UserClaims) (int, utils.Error)
+	ListJobAlerts(candidateId int, caller model.UserClaims) ([]modelVacancy.JobAlertResponse, utils.Error)
+	SetJobAlertPaused(id int, paused bool, caller model.UserClaims) utils.Error
+	DeleteJobAlert(id int, caller model.UserClaims) utils.Error
+
+	RunJobAlerts() utils.Error
+}
+
+type jobAlertService struct {
+	jobAlertRepo repoVacancy.JobAlertRepo
+	vacancyRepo  repoVacancy.VacancyRepo
+	personRepo   repo.PersonRepo
+	mailer       integration.Mailer
+}
+
+func NewJobAlertService(
+	jobAlertRepo repoVacancy.JobAlertRepo,
+	vacancyRepo repoVacancy.VacancyRepo,
+	personRepo repo.PersonRepo,
+	mailer integration.Mailer,
+) JobAlertService {
+	return &jobAlertService{
+		jobAlertRepo: jobAlertRepo,
+		vacancyRepo:  vacancyRepo,
+		personRepo:   personRepo,
+		mailer:       mailer,
+	}
+}
+
+func jobAlertServiceError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ServiceErrorCode, utils.JobAlertErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func jobAlertNotFoundError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.NotFoundErrorCode, utils.JobAlertErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func jobAlertForbiddenError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ForbiddenErrorCode, utils.JobAlertErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+// authorizeJobAlertCandidate checks the candidate exists and is the caller, only the
+// candidate or an admin can manage the alerts of the candidate
+func (j *jobAlertService) authorizeJobAlertCandidate(candidateId int, caller model.UserClaims) utils.Error {
+	candidate, err := j.personRepo.GetPersonById(candidateId, nil)
+	if err.IsError() {
+		return jobAlertServiceError("failed to get the candidate", "01").WithCause(err)
+	}
+
+	if candidate.Id == 0 {
+		return jobAlertNotFoundError("candidate not found", "02")
+	}
+
+	if caller.Role != string(enum.AdminRole) && caller.Id != candidate.UserId {
+		return jobAlertForbiddenError("only the candidate or an admin can manage the job alerts", "10")
+	}
+
+	return utils.Error{}
+}
+
+func (j *jobAlertService) CreateJobAlert(jobAlert modelVacancy.JobAlertRequest, caller model.UserClaims) (int, utils.Error) {
+	if jobAlert.ContractType != "" && !jobAlert.ContractType.IsValid() {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.JobAlertErrorType, "01")
+		message := "invalid contract type. valid values are: 'clt', 'pj', 'trainee'"
+
+		return 0, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "contract_type", Message: message}})
+	}
+
+	if err := j.authorizeJobAlertCandidate(jobAlert.CandidateId, caller); err.IsError() {
+		return 0, err
+	}
+
+	jobAlertId, err := j.jobAlertRepo.CreateJobAlert(*jobAlert.ToModel())
+	if err.IsError() {
+		return 0, jobAlertServiceError("failed to create the job alert", "03").WithCause(err)
+	}
+
+	return jobAlertId, utils.Error{}
+}
+
+func (j *jobAlertService) ListJobAlerts(candidateId int, caller model.UserClaims) ([]modelVacancy.JobAlertResponse, utils.Error) {
+	jobAlertsResponse := []modelVacancy.JobAlertResponse{}
+
+	if err := j.authorizeJobAlertCandidate(candidateId, caller); err.IsError() {
+		return jobAlertsResponse, err
+	}
+
+	jobAlerts, err := j.jobAlertRepo.ListJobAlertsByCandidateId(candidateId)
+	if err.IsError() {
+		return jobAlertsResponse, jobAlertServiceError("failed to list the job alerts", "04").WithCause(err)
+	}
+
+	for _, jobAlert := range jobAlerts {
+		jobAlertsResponse = append(jobAlertsResponse, jobAlert.ToResponse())
+	}
+
+	return jobAlertsResponse, utils.Error{}
+}
+
+func (j *jobAlertService) SetJobAlertPaused(id int, paused bool, caller model.UserClaims) utils.Error {
+	if err := j.findJobAlert(id, caller); err.IsError() {
+		return err
+	}
+
+	if err := j.jobAlertRepo.UpdateJobAlertPaused(id, paused); err.IsError() {
+		return jobAlertServiceError("failed to update the job alert", "07").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+func (j *jobAlertService) DeleteJobAlert(id int, caller model.UserClaims) utils.Error {
+	if err := j.findJobAlert(id, caller); err.IsError() {
+		return err
+	}
+
+	if err := j.jobAlertRepo.DeleteJobAlert(id); err.IsError() {
+		return jobAlertServiceError("failed to delete the job alert", "08").WithCause(err)
+	}
+
+	return utils.Error{}
+}
+
+// findJobAlert checks the alert exists and belongs to the caller
+func (j *jobAlertService) findJobAlert(id int, caller model.UserClaims) utils.Error {
+	jobAlert, err := j.jobAlertRepo.GetJobAlertById(id)
+	if err.IsError() {
+		return jobAlertServiceError("failed to get the job alert", "05").WithCause(err)
+	}
+
+	if jobAlert.Id == 0 {
+		return jobAlertNotFoundError("job alert not found", "06")
+	}
+
+	return j.authorizeJobAlertCandidate(jobAlert.CandidateId, caller)
+}
+
+// RunJobAlerts looks for the vacancies published since the last run of each active alert and
+// queues an email to the candidate. A failing alert is logged and doesn't stop the others
+func (j *jobAlertService) RunJobAlerts() utils.Error {
+	log := logger.FromContext(context.Background())
+
+	jobAlerts, err := j.jobAlertRepo.ListActiveJobAlerts()
+	if err.IsError() {
+		return jobAlertServiceError("failed to list the active job alerts", "09").WithCause(err)
+	}
+
+	for _, jobAlert := range jobAlerts {
+		runAt := time.Now()
+
+		since := runAt
+		if jobAlert.LastRunAt != nil {
+			since = *jobAlert.LastRunAt
+		} else if jobAlert.Model != nil {
+			since = jobAlert.CreatedAt
+		}
+
+		vacancies, err := j.vacancyRepo.ListAllVacancies(jobAlert.ToFilters(since))
+		if err.IsError() {
+			log.Error("failed to list the vacancies of the job alert", "job_alert_id", jobAlert.Id, "cause", err.Error())
+			continue
+		}
+
+		if len(vacancies) > 0 && jobAlert.Candidate != nil && jobAlert.Candidate.User != nil {
+			go j.sendJobAlertEmail(jobAlert.Candidate.User.Email, jobAlert.Candidate.Name, vacancies)
+		}
+
+		if err := j.jobAlertRepo.UpdateJobAlertLastRun(jobAlert.Id, runAt); err.IsError() {
+			log.Error("failed to update the job alert last run", "job_alert_id", jobAlert.Id, "cause", err.Error())
+		}
+	}
+
+	return utils.Error{}
+}
+
+func (j *jobAlertService) sendJobAlertEmail(email string, name string, vacancies []modelVacancy.Vacancy) {
+	titles := []string{}
+	for _, vacancy := range vacancies {
+		titles = append(titles, "- "+vacancy.Title+" ("+vacancy.Company.Name+")")
+	}
+
+	subject := "Novas vagas para você no Conexão Inclusão"
+	body := fmt.Sprintf("Olá, %s!\n\nEncontramos novas vagas que combinam com o seu alerta:\n\n%s", name, strings.Join(titles, "\n"))
+
+	if err := j.mailer.Send(email, subject, body); err != nil {
+		logger.FromContext(context.Background()).Error("failed to send the job alert email", "email", email, "cause", err.Error())
+	}
+}
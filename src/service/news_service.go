@@ -34,7 +34,7 @@ func (n *newsService) ListNews() ([]model.NewsResponse, utils.Error) {
 	newsResponse := []model.NewsResponse{}
 
 	news, err := n.newsRepo.ListNews()
-	if err.Code != "" {
+	if err.IsError() {
 		return newsResponse, err
 	}
 
@@ -80,7 +80,7 @@ func (n *newsService) CreateNews(createNews model.NewsRequest, images map[string
 	}
 
 	err := n.newsRepo.CreateNews(news)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"cij_api/src/integration"
+	"cij_api/src/logger"
 	"cij_api/src/model"
 	"cij_api/src/repo"
 	"cij_api/src/utils"
+	"context"
 	"fmt"
 	"mime/multipart"
 
@@ -32,6 +35,7 @@ type personService struct {
 	addressRepo          repo.AddressRepo
 	personDisabilityRepo repo.PersonDisabilityRepo
 	activityRepo         repo.ActivityRepo
+	mailer               integration.Mailer
 }
 
 func NewPersonService(
@@ -40,6 +44,7 @@ func NewPersonService(
 	addressRepo repo.AddressRepo,
 	personDisabilityRepo repo.PersonDisabilityRepo,
 	activityRepo repo.ActivityRepo,
+	mailer integration.Mailer,
 ) PersonService {
 	return &personService{
 		personRepo:           personRepo,
@@ -47,6 +52,7 @@ func NewPersonService(
 		addressRepo:          addressRepo,
 		personDisabilityRepo: personDisabilityRepo,
 		activityRepo:         activityRepo,
+		mailer:               mailer,
 	}
 }
 
@@ -60,7 +66,7 @@ func (s *personService) ListPeople() ([]model.PersonResponse, utils.Error) {
 	peopleResponse := []model.PersonResponse{}
 
 	people, err := s.personRepo.ListPeople()
-	if err.Code != "" {
+	if err.IsError() {
 		return peopleResponse, err
 	}
 
@@ -78,17 +84,9 @@ func (s *personService) ListPeople() ([]model.PersonResponse, utils.Error) {
 func (n *personService) CreatePerson(createPerson model.PersonRequest) utils.Error {
 	userInfo := createPerson.ToUser()
 
-	hashedPassword, err := utils.EncryptPassword(userInfo.Password)
-	if err != nil {
-		return personServiceError("failed to encrypt the password", "01")
-	}
-
-	userInfo.Password = hashedPassword
-	userInfo.RoleId = model.PersonRole
-
 	errTx := n.userRepo.BeginTransaction(func(tx *gorm.DB) error {
 		userId, userError := n.userRepo.CreateUser(userInfo, tx)
-		if userError.Code != "" {
+		if userError.IsError() {
 			fmt.Print("Error: ", userError)
 			return userError
 		}
@@ -99,19 +97,19 @@ func (n *personService) CreatePerson(createPerson model.PersonRequest) utils.Err
 		personInfo.UserId = userId
 
 		personId, personError := n.personRepo.CreatePerson(personInfo, tx)
-		if personError.Code != "" {
+		if personError.IsError() {
 			fmt.Print("Error: ", personError)
 			return personError
 		}
 
 		addressError := n.UpdatePersonAddress(createPerson.Address, personId, tx)
-		if addressError.Code != "" {
+		if addressError.IsError() {
 			fmt.Println("Error: ", addressError)
 			return addressError
 		}
 
 		disabilityError := n.UpdatePersonDisabilities(createPerson.Disabilities, personId, tx)
-		if disabilityError.Code != "" {
+		if disabilityError.IsError() {
 			fmt.Print("Error: ", disabilityError)
 			return disabilityError
 		}
@@ -123,6 +121,8 @@ func (n *personService) CreatePerson(createPerson model.PersonRequest) utils.Err
 		return personServiceError("failed to create the person", "02")
 	}
 
+	go n.sendWelcomeEmail(userInfo.Email, createPerson.Name)
+
 	activityService := NewActivityService(n.activityRepo)
 	activity := model.Activity{
 		Type:        "register_person",
@@ -131,14 +131,14 @@ func (n *personService) CreatePerson(createPerson model.PersonRequest) utils.Err
 	}
 
 	activityError := activityService.CreateActivity(&activity)
-	if activityError.Code != "" {
+	if activityError.IsError() {
 		return activityError
 	}
 
 	configService := NewConfigService(n.userRepo)
 
 	uploadErr := configService.UploadUserConfig(userInfo.Email, nil)
-	if uploadErr.Code != "" {
+	if uploadErr.IsError() {
 		fmt.Print("Error: ", uploadErr)
 		return uploadErr
 	}
@@ -148,7 +148,7 @@ func (n *personService) CreatePerson(createPerson model.PersonRequest) utils.Err
 
 func (n *personService) GetPersonByUserId(userId int) (model.Person, utils.Error) {
 	person, err := n.personRepo.GetPersonByUserId(userId)
-	if err.Code != "" {
+	if err.IsError() {
 		return person, err
 	}
 
@@ -159,7 +159,7 @@ func (s *personService) GetPersonById(personId int) (model.PersonResponse, utils
 	personResponse := model.PersonResponse{}
 
 	person, err := s.personRepo.GetPersonById(personId, nil)
-	if err.Code != "" {
+	if err.IsError() {
 		return personResponse, err
 	}
 
@@ -174,7 +174,7 @@ func (s *personService) GetPersonById(personId int) (model.PersonResponse, utils
 
 func (n *personService) GetPersonByCpf(cpf string) (model.Person, utils.Error) {
 	person, err := n.personRepo.GetPersonByCpf(cpf)
-	if err.Code != "" {
+	if err.IsError() {
 		return person, err
 	}
 
@@ -183,7 +183,7 @@ func (n *personService) GetPersonByCpf(cpf string) (model.Person, utils.Error) {
 
 func (n *personService) GetUserByEmail(email string) (model.User, utils.Error) {
 	user, err := n.userRepo.GetUserByEmail(email)
-	if err.Code != "" {
+	if err.IsError() {
 		return user, err
 	}
 
@@ -202,7 +202,7 @@ func (n *personService) UpdatePerson(updatePerson model.PersonRequest, personId
 		userInfo.Password = hashedPassword
 
 		userError := n.userRepo.UpdateUser(userInfo, personId)
-		if userError.Code != "" {
+		if userError.IsError() {
 			return userError
 		}
 	}
@@ -210,7 +210,7 @@ func (n *personService) UpdatePerson(updatePerson model.PersonRequest, personId
 	personInfo := updatePerson.ToModel(userInfo)
 
 	personError := n.personRepo.UpdatePerson(personInfo, personId, nil)
-	if personError.Code != "" {
+	if personError.IsError() {
 		return personError
 	}
 
@@ -221,7 +221,7 @@ func (n *personService) UpdatePersonAddress(updateAddress model.AddressRequest,
 	addressInfo := updateAddress.ToModel()
 
 	person, err := n.personRepo.GetPersonById(personId, tx)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -230,14 +230,14 @@ func (n *personService) UpdatePersonAddress(updateAddress model.AddressRequest,
 	}
 
 	addressId, err := n.addressRepo.UpsertAddress(addressInfo, tx)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	person.AddressId = &addressId
 
 	err = n.personRepo.UpdatePerson(person, personId, tx)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -246,7 +246,7 @@ func (n *personService) UpdatePersonAddress(updateAddress model.AddressRequest,
 
 func (n *personService) GetDisabilityById(id int) (model.Disability, utils.Error) {
 	disability, err := n.personDisabilityRepo.GetDisabilityById(id)
-	if err.Code != "" {
+	if err.IsError() {
 		return disability, err
 	}
 
@@ -255,12 +255,12 @@ func (n *personService) GetDisabilityById(id int) (model.Disability, utils.Error
 
 func (n *personService) UpdatePersonDisabilities(disabilities []model.PersonDisabilityRequest, personId int, tx *gorm.DB) utils.Error {
 	person, err := n.personRepo.GetPersonById(personId, tx)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.personDisabilityRepo.ClearPersonDisability(personId, tx)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -272,13 +272,13 @@ func (n *personService) UpdatePersonDisabilities(disabilities []model.PersonDisa
 		}
 
 		err = n.personDisabilityRepo.UpsertPersonDisability(disability, tx)
-		if err.Code != "" {
+		if err.IsError() {
 			return err
 		}
 	}
 
 	err = n.personRepo.UpdatePerson(person, personId, tx)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -287,27 +287,27 @@ func (n *personService) UpdatePersonDisabilities(disabilities []model.PersonDisa
 
 func (n *personService) DeletePerson(personId int) utils.Error {
 	person, err := n.personRepo.GetPersonById(personId, nil)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.personDisabilityRepo.ClearPersonDisability(personId, nil)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.personRepo.DeletePerson(personId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.userRepo.DeleteUser(person.UserId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
 	err = n.addressRepo.DeleteAddress(*person.AddressId)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -316,7 +316,7 @@ func (n *personService) DeletePerson(personId int) utils.Error {
 
 func (n *personService) UploadCurriculum(curriculum multipart.FileHeader, personId int) utils.Error {
 	person, err := n.personRepo.GetPersonById(personId, nil)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -334,7 +334,7 @@ func (n *personService) UploadCurriculum(curriculum multipart.FileHeader, person
 	}
 
 	err = n.personRepo.UploadCurriculum(personId, url)
-	if err.Code != "" {
+	if err.IsError() {
 		return err
 	}
 
@@ -343,7 +343,7 @@ func (n *personService) UploadCurriculum(curriculum multipart.FileHeader, person
 
 func (n *personService) personToResponse(personResponse *model.PersonResponse, person model.Person) (model.PersonResponse, utils.Error) {
 	user, err := n.userRepo.GetUserById(person.UserId)
-	if err.Code != "" {
+	if err.IsError() {
 		return *personResponse, err
 	}
 
@@ -351,7 +351,7 @@ func (n *personService) personToResponse(personResponse *model.PersonResponse, p
 
 	if person.AddressId != nil {
 		address, err := n.addressRepo.GetAddressById(*person.AddressId)
-		if err.Code != "" {
+		if err.IsError() {
 			return *personResponse, err
 		}
 
@@ -362,7 +362,7 @@ func (n *personService) personToResponse(personResponse *model.PersonResponse, p
 	}
 
 	disabilities, err := n.personDisabilityRepo.GetPersonDisabilities(person.Id)
-	if err.Code != "" {
+	if err.IsError() {
 		return *personResponse, err
 	}
 
@@ -383,7 +383,7 @@ func (n *personService) personToResponse(personResponse *model.PersonResponse, p
 	if user.ConfigUrl != "" {
 		configService := NewConfigService(n.userRepo)
 		userConfig, err = configService.GetUserConfig(user.ConfigUrl)
-		if err.Code != "" {
+		if err.IsError() {
 			return *personResponse, err
 		}
 	}
@@ -392,3 +392,13 @@ func (n *personService) personToResponse(personResponse *model.PersonResponse, p
 
 	return *personResponse, utils.Error{}
 }
+
+// sendWelcomeEmail is only called once the registration transaction has committed
+func (n *personService) sendWelcomeEmail(email string, name string) {
+	subject := "Bem-vindo(a) ao Conexão Inclusão"
+	body := fmt.Sprintf("Olá, %s!\n\nSeu cadastro no Conexão Inclusão foi realizado com sucesso.", name)
+
+	if err := n.mailer.Send(email, subject, body); err != nil {
+		logger.FromContext(context.Background()).Error("failed to send the welcome email", "email", email, "cause", err.Error())
+	}
+}
@@ -28,7 +28,7 @@ func NewReportsService(personDisabilityRepo repo.PersonDisabilityRepo, activityR
 
 func (s *reportsService) GetDisabilityTotals() (model.DisabilityTotals, utils.Error) {
 	disabilityTotals, err := s.personDisabilityRepo.CountDisability()
-	if err.Code != "" {
+	if err.IsError() {
 		return model.DisabilityTotals{}, err
 	}
 
@@ -37,7 +37,7 @@ func (s *reportsService) GetDisabilityTotals() (model.DisabilityTotals, utils.Er
 
 func (s *reportsService) GetDisabilityTotalsByNeighborhood(neighborhood string) (model.DisabilityTotalsByNeighborhood, utils.Error) {
 	disabilityTotals, err := s.personDisabilityRepo.CountDisabilityByNeighborhood(neighborhood)
-	if err.Code != "" {
+	if err.IsError() {
 		return model.DisabilityTotalsByNeighborhood{}, err
 	}
 
@@ -51,7 +51,7 @@ func (s *reportsService) CountActivitiesByPeriod(activityType string, period enu
 	endDate := time.Now()
 
 	activities, err := s.activityRepo.GetActivitiesByTypeAndPeriod(activityType, utils.GetFormattedDate(startDate.Unix()), utils.GetFormattedDate(endDate.Unix()))
-	if err.Code != "" {
+	if err.IsError() {
 		return model.CountActivitiesByPeriod{}, err
 	}
 
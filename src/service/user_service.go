@@ -0,0 +1,318 @@
+package service
+
+import (
+	"cij_api/src/model"
+	"cij_api/src/repo"
+	"cij_api/src/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const passwordResetTokenTTL = time.Hour
+
+const (
+	defaultUsersPerPage = 20
+	maxUsersPerPage     = 100
+)
+
+type UserService interface {
+	GetUserById(id int) (model.User, utils.Error)
+	ListUsers(filters model.UserFilters, page int, perPage int) (model.PaginatedResponse[model.UserResponse], utils.Error)
+	RequestPasswordReset(email string) (string, utils.Error)
+	ResetPassword(token string, newPassword string) utils.Error
+	UpdateUser(id int, request model.UserUpdateRequest) (model.UserResponse, utils.Error)
+	Me(ctx context.Context) (model.UserResponse, utils.Error)
+	DeactivateUser(id int) utils.Error
+	ReactivateUser(id int) utils.Error
+}
+
+type userService struct {
+	userRepo          repo.UserRepo
+	passwordResetRepo repo.PasswordResetRepo
+}
+
+func NewUserService(userRepo repo.UserRepo, passwordResetRepo repo.PasswordResetRepo) UserService {
+	return &userService{
+		userRepo:          userRepo,
+		passwordResetRepo: passwordResetRepo,
+	}
+}
+
+func userServiceError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ServiceErrorCode, utils.UserErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func userNotFoundError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.NotFoundErrorCode, utils.UserErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (s *userService) GetUserById(id int) (model.User, utils.Error) {
+	user, err := s.userRepo.GetUserById(id)
+	if err.IsError() {
+		return model.User{}, userServiceError("failed to get the user", "01")
+	}
+
+	if user.Id == 0 {
+		return model.User{}, userNotFoundError("user not found", "02")
+	}
+
+	return user, utils.Error{}
+}
+
+// ListUsers pages through the users, optionally filtering by role and by a part of
+// the email. A zero page or per page falls back to the first page of the default size
+func (s *userService) ListUsers(filters model.UserFilters, page int, perPage int) (model.PaginatedResponse[model.UserResponse], utils.Error) {
+	if page < 0 || perPage < 0 {
+		return model.PaginatedResponse[model.UserResponse]{}, userValidationError("page and per page must not be negative", "06")
+	}
+
+	if filters.Role != "" && !filters.Role.IsValid() {
+		return model.PaginatedResponse[model.UserResponse]{}, userValidationError("invalid role", "07")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if perPage == 0 {
+		perPage = defaultUsersPerPage
+	}
+
+	if perPage > maxUsersPerPage {
+		perPage = maxUsersPerPage
+	}
+
+	users, total, err := s.userRepo.ListUsersPaginated(filters, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[model.UserResponse]{}, userServiceError("failed to list the users", "17")
+	}
+
+	usersResponse := make([]model.UserResponse, 0, len(users))
+	for _, user := range users {
+		usersResponse = append(usersResponse, user.ToResponse())
+	}
+
+	return model.NewPaginatedResponse(usersResponse, total, page, perPage), utils.Error{}
+}
+
+// Me returns the profile of the authenticated user, telling an unauthenticated request
+// apart from a user that no longer exists
+func (s *userService) Me(ctx context.Context) (model.UserResponse, utils.Error) {
+	currentUser, ok := model.CurrentUser(ctx)
+	if !ok {
+		errorCode := utils.NewErrorCode(utils.UnauthorizedErrorCode, utils.UserErrorType, "16")
+
+		return model.UserResponse{}, utils.NewError("the request is not authenticated", errorCode)
+	}
+
+	user, err := s.GetUserById(currentUser.Id)
+	if err.IsError() {
+		return model.UserResponse{}, err
+	}
+
+	return user.ToResponse(), utils.Error{}
+}
+
+func hashPasswordResetToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// RequestPasswordReset generates a single use token for the user and returns it so it
+// can be delivered to them. An unknown email returns an empty token and no error, so
+// callers never reveal whether the email is registered
+func (s *userService) RequestPasswordReset(email string) (string, utils.Error) {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err.IsError() {
+		return "", userServiceError("failed to get the user", "03")
+	}
+
+	if user.Id == 0 {
+		return "", utils.Error{}
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", userServiceError("failed to generate the password reset token", "04")
+	}
+
+	token := hex.EncodeToString(tokenBytes)
+
+	passwordResetToken := model.PasswordResetToken{
+		UserId:    user.Id,
+		TokenHash: hashPasswordResetToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+
+	err = s.passwordResetRepo.CreatePasswordResetToken(passwordResetToken)
+	if err.IsError() {
+		return "", userServiceError("failed to create the password reset token", "05")
+	}
+
+	return token, utils.Error{}
+}
+
+func (s *userService) ResetPassword(token string, newPassword string) utils.Error {
+	if newPassword == "" {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.UserErrorType, "03")
+
+		return utils.NewError("the new password is required", errorCode)
+	}
+
+	if err := utils.ValidatePasswordStrength(newPassword); err.IsError() {
+		return err
+	}
+
+	passwordResetToken, err := s.passwordResetRepo.GetPasswordResetTokenByHash(hashPasswordResetToken(token))
+	if err.IsError() {
+		return userServiceError("failed to get the password reset token", "06")
+	}
+
+	if passwordResetToken.Id == 0 {
+		return userServiceError("invalid password reset token", "07")
+	}
+
+	if passwordResetToken.UsedAt != nil {
+		return userServiceError("the password reset token was already used", "08")
+	}
+
+	if time.Now().After(passwordResetToken.ExpiresAt) {
+		return userServiceError("the password reset token has expired", "09")
+	}
+
+	hashedPassword, hashErr := utils.EncryptPassword(newPassword)
+	if hashErr != nil {
+		return userServiceError("failed to encrypt the password", "10")
+	}
+
+	errTx := s.userRepo.BeginTransaction(func(tx *gorm.DB) error {
+		if err := s.passwordResetRepo.MarkPasswordResetTokenUsed(passwordResetToken.Id, tx); err.IsError() {
+			return err
+		}
+
+		if err := s.userRepo.UpdateUserPassword(passwordResetToken.UserId, hashedPassword, tx); err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		if errors.Is(errTx, repo.ErrPasswordResetTokenUsed) {
+			return userServiceError("the password reset token was already used", "08")
+		}
+
+		return userServiceError("failed to reset the password", "11")
+	}
+
+	return utils.Error{}
+}
+
+func userValidationError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.UserErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func userConflictError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ConflictErrorCode, utils.UserErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func (s *userService) UpdateUser(id int, request model.UserUpdateRequest) (model.UserResponse, utils.Error) {
+	user, err := s.GetUserById(id)
+	if err.IsError() {
+		return model.UserResponse{}, err
+	}
+
+	userUpdate := model.User{}
+
+	if request.Email != nil {
+		email := utils.NormalizeEmail(*request.Email)
+		if !utils.ValidateEmail(email) {
+			return model.UserResponse{}, userValidationError("invalid email", "04")
+		}
+
+		userDb, err := s.userRepo.GetUserByEmail(email)
+		if err.IsError() {
+			return model.UserResponse{}, userServiceError("failed to get the user", "12")
+		}
+
+		if userDb.Id != 0 && userDb.Id != user.Id {
+			return model.UserResponse{}, userConflictError("email already registered", "13")
+		}
+
+		userUpdate.Email = email
+	}
+
+	if request.Password != nil {
+		if *request.Password == "" {
+			return model.UserResponse{}, userValidationError("the password must not be empty", "05")
+		}
+
+		if err := utils.ValidatePasswordStrength(*request.Password); err.IsError() {
+			return model.UserResponse{}, err
+		}
+
+		hashedPassword, hashErr := utils.EncryptPassword(*request.Password)
+		if hashErr != nil {
+			return model.UserResponse{}, userServiceError("failed to encrypt the password", "14")
+		}
+
+		userUpdate.Password = hashedPassword
+	}
+
+	if userUpdate.Email == "" && userUpdate.Password == "" {
+		return user.ToResponse(), utils.Error{}
+	}
+
+	err = s.userRepo.UpdateUser(userUpdate, user.Id)
+	if err.Code == repo.EmailAlreadyRegisteredErrorCode {
+		return model.UserResponse{}, userConflictError(err.Message, "13")
+	}
+
+	if err.IsError() {
+		return model.UserResponse{}, userServiceError("failed to update the user", "15")
+	}
+
+	updatedUser, err := s.GetUserById(user.Id)
+	if err.IsError() {
+		return model.UserResponse{}, err
+	}
+
+	return updatedUser.ToResponse(), utils.Error{}
+}
+
+// DeactivateUser disables the account instead of deleting it, the user can't log in
+// and the vacancies of their company leave the listings until it is reactivated
+func (s *userService) DeactivateUser(id int) utils.Error {
+	return s.setUserActive(id, false)
+}
+
+func (s *userService) ReactivateUser(id int) utils.Error {
+	return s.setUserActive(id, true)
+}
+
+func (s *userService) setUserActive(id int, active bool) utils.Error {
+	if _, err := s.GetUserById(id); err.IsError() {
+		return err
+	}
+
+	if err := s.userRepo.SetUserActive(id, active); err.IsError() {
+		return userServiceError("failed to update the user status", "18")
+	}
+
+	return utils.Error{}
+}
@@ -1,38 +1,87 @@
 package service
 
 import (
+	"cij_api/src/config"
 	"cij_api/src/enum"
+	"cij_api/src/integration"
+	"cij_api/src/logger"
 	"cij_api/src/model"
 	modelVacancy "cij_api/src/model/vacancy"
 	"cij_api/src/repo"
 	repoVacancy "cij_api/src/repo/vacancy"
 	"cij_api/src/utils"
-	"slices"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 )
 
 type vacancyService struct {
+	ctx                     context.Context
 	vacancyRepo             repoVacancy.VacancyRepo
 	skillsRepo              repoVacancy.SkillsRepo
 	requirementsRepo        repoVacancy.RequirementsRepo
 	responsabilitiesRepo    repoVacancy.ResponsabilitiesRepo
+	accommodationsRepo      repoVacancy.AccommodationsRepo
 	vacancyDisabilitiesRepo repoVacancy.VacancyDisabilityRepo
 	vacancyAppliesRepo      repoVacancy.VacancyApplyRepo
+	idempotencyKeyRepo      repoVacancy.IdempotencyKeyRepo
 	personRepo              repo.PersonRepo
 	personDisabilitiesRepo  repo.PersonDisabilityRepo
+	disabilityRepo          repo.DisabilityRepo
+	companyRepo             repo.CompanyRepo
+	auditLogRepo            repo.AuditLogRepo
+	mailer                  integration.Mailer
+	viewThrottle            *viewThrottle
 }
 
 type VacancyService interface {
-	CreateVacancy(vacancy modelVacancy.VacancyRequest) utils.Error
-	ListVacancies(perPage int, companyId int, disabilityId int, candidateId int, area string, contractType enum.VacancyContractType, searchText string) ([]modelVacancy.VacancySimpleResponse, utils.Error)
+	WithContext(ctx context.Context) VacancyService
+
+	CreateVacancy(vacancy modelVacancy.VacancyRequest, idempotencyKey string) (int, utils.Error)
+	ListVacancies(filters modelVacancy.VacancyFilters, page int, perPage int) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error)
+	ListVacanciesForCandidate(candidateId int, page int, perPage int, excludeApplied bool) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error)
+	ListCompanyVacancies(companyId int, filters modelVacancy.VacancyFilters, page int, perPage int, caller model.UserClaims) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error)
+	ListCompaniesWithVacancies(page int, perPage int) (model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse], utils.Error)
 	GetVacancyById(id int, candidateId int) (modelVacancy.VacancyResponse, utils.Error)
-	UpdateVacancy(vacancy modelVacancy.VacancyRequest, id int) utils.Error
-	DeleteVacancy(id int) utils.Error
+	GetVacancyBySlug(slug string) (modelVacancy.VacancyResponse, utils.Error)
+	RecordVacancyView(id int, viewer string) utils.Error
+	GetVacanciesByIds(ids []int) ([]modelVacancy.VacancySimpleResponse, utils.Error)
+	UpdateVacancy(vacancy modelVacancy.VacancyRequest, id int, caller model.UserClaims) utils.Error
+	DeleteVacancy(id int, caller model.UserClaims) utils.Error
+	CloseVacancy(id int, caller model.UserClaims) utils.Error
+	FeatureVacancy(id int, request modelVacancy.VacancyFeatureRequest) utils.Error
+	ReopenVacancy(id int, newExpiresAt *time.Time, caller model.UserClaims) utils.Error
+	PublishVacancy(id int, caller model.UserClaims) utils.Error
+	DuplicateVacancy(id int, caller model.UserClaims) (int, utils.Error)
+	TransferVacancies(fromCompanyId int, toCompanyId int) (int, utils.Error)
+	CountVacanciesByCompany(companyId int) (int, utils.Error)
+	CountVacanciesGroupedByArea() (map[string]int, utils.Error)
+	VacancyStats(from time.Time, to time.Time, interval string) ([]modelVacancy.VacancyStatsBucket, utils.Error)
+	VacancyFacets(filters modelVacancy.VacancyFilters) (map[enum.VacancyContractType]int, utils.Error)
+	ListVacancyAreas() ([]string, utils.Error)
+	ListVacancyCities() ([]string, utils.Error)
+	ListPopularVacancies(limit int) ([]modelVacancy.VacancySimpleResponse, utils.Error)
+	ImportVacancies(companyId int, r io.Reader, caller model.UserClaims) (modelVacancy.ImportResult, utils.Error)
+	ExportVacancies(filters modelVacancy.VacancyFilters) (io.Reader, utils.Error)
 
 	CandidateApplyVacancy(candidateId int, vacancyId int) utils.Error
-	GetVacancyAppliesByVacancyId(vacancyId int) ([]modelVacancy.VacancyApplyResponse, utils.Error)
+	GetVacancyAppliesByVacancyId(vacancyId int, status enum.VacancyApplyStatus, page int, perPage int) (model.PaginatedResponse[modelVacancy.VacancyApplyResponse], utils.Error)
+	GetVacancyAppliesByCandidateId(candidateId int) ([]modelVacancy.CandidateApplyResponse, utils.Error)
+	ListApplicationsByCandidate(candidateId int, status enum.VacancyApplyStatus, page int, perPage int) (model.PaginatedResponse[modelVacancy.CandidateApplication], utils.Error)
 	UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus) utils.Error
+	BulkUpdateApplicationStatus(ids []int, status enum.VacancyApplyStatus, caller model.UserClaims) (modelVacancy.VacancyApplyBulkStatusResult, utils.Error)
+	WithdrawApplication(applicationId int, caller model.UserClaims) utils.Error
 }
 
 func NewVacancyService(
@@ -40,269 +89,418 @@ func NewVacancyService(
 	skillsRepo repoVacancy.SkillsRepo,
 	requirementsRepo repoVacancy.RequirementsRepo,
 	responsabilitiesRepo repoVacancy.ResponsabilitiesRepo,
+	accommodationsRepo repoVacancy.AccommodationsRepo,
 	vacancyDisabilitiesRepo repoVacancy.VacancyDisabilityRepo,
 	vacancyAppliesRepo repoVacancy.VacancyApplyRepo,
+	idempotencyKeyRepo repoVacancy.IdempotencyKeyRepo,
 	personRepo repo.PersonRepo,
 	personDisabilitiesRepo repo.PersonDisabilityRepo,
+	disabilityRepo repo.DisabilityRepo,
+	companyRepo repo.CompanyRepo,
+	auditLogRepo repo.AuditLogRepo,
+	mailer integration.Mailer,
 ) VacancyService {
 	return &vacancyService{
 		vacancyRepo:             vacancyRepo,
 		skillsRepo:              skillsRepo,
 		requirementsRepo:        requirementsRepo,
 		responsabilitiesRepo:    responsabilitiesRepo,
+		accommodationsRepo:      accommodationsRepo,
 		vacancyDisabilitiesRepo: vacancyDisabilitiesRepo,
 		vacancyAppliesRepo:      vacancyAppliesRepo,
+		idempotencyKeyRepo:      idempotencyKeyRepo,
 		personRepo:              personRepo,
 		personDisabilitiesRepo:  personDisabilitiesRepo,
+		disabilityRepo:          disabilityRepo,
+		companyRepo:             companyRepo,
+		auditLogRepo:            auditLogRepo,
+		mailer:                  mailer,
+		viewThrottle:            newViewThrottle(),
 	}
 }
 
-func vacancyServiceError(message string, code string) utils.Error {
+// serviceError logs the failure and its underlying cause with the request id, returning
+// only the sanitized message to the caller
+func (v *vacancyService) serviceError(message string, code string, causes ...error) utils.Error {
 	errorCode := utils.NewErrorCode(utils.DatabaseErrorCode, utils.VacancyErrorType, code)
 
-	return utils.NewError(message, errorCode)
-}
+	serviceErr := utils.NewError(message, errorCode)
+	attributes := []any{"code", errorCode}
 
-func (v *vacancyService) CreateVacancy(vacancy modelVacancy.VacancyRequest) utils.Error {
-	vacancyModel := vacancy.ToModel()
+	for _, cause := range causes {
+		if cause == nil {
+			continue
+		}
 
-	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
-		vacancyId, err := v.vacancyRepo.UpsertVacancy(*vacancyModel, tx)
-		if err.Code != "" {
-			return err
+		if causeErr, ok := cause.(utils.Error); ok && !causeErr.IsError() {
+			continue
 		}
 
-		for _, skill := range vacancy.Skills {
-			skillModel := skill.ToModel()
-			skillModel.VacancyId = vacancyId
+		if serviceErr.Cause == nil {
+			serviceErr.Cause = cause
+		}
 
-			_, err := v.skillsRepo.CreateSkill(*skillModel, tx)
-			if err.Code != "" {
-				return err
-			}
+		// walk the chain so the database error behind a repo error is logged too
+		chain := []string{}
+		for unwrapped := cause; unwrapped != nil; unwrapped = errors.Unwrap(unwrapped) {
+			chain = append(chain, unwrapped.Error())
 		}
 
-		for _, requirement := range vacancy.Requirements {
-			requirementModel := requirement.ToModel()
-			requirementModel.VacancyId = vacancyId
+		attributes = append(attributes, "cause", strings.Join(chain, ": "))
+	}
 
-			_, err := v.requirementsRepo.CreateRequirement(*requirementModel, tx)
-			if err.Code != "" {
-				return err
-			}
-		}
+	logger.FromContext(v.ctx).Error(message, attributes...)
 
-		for _, responsability := range vacancy.Responsabilities {
-			responsabilityModel := responsability.ToModel()
-			responsabilityModel.VacancyId = vacancyId
+	return serviceErr
+}
 
-			_, err := v.responsabilitiesRepo.CreateResponsability(*responsabilityModel, tx)
-			if err.Code != "" {
-				return err
-			}
-		}
+// recordVacancyAudit saves the changes to the audit log in the same transaction, with
+// the authenticated user of the request as the actor
+func (v *vacancyService) recordVacancyAudit(vacancyId int, action enum.AuditAction, changes map[string]model.AuditChange, tx *gorm.DB) utils.Error {
+	var actor *model.User
+	if user, ok := model.CurrentUser(v.ctx); ok {
+		actor = &user
+	}
 
-		for _, disability := range vacancy.Disabilities {
-			disabilityModel := modelVacancy.VacancyDisability{
-				VacancyId:    vacancyId,
-				DisabilityId: int(disability),
-			}
+	auditLog, err := model.NewAuditLog(enum.AuditVacancy, vacancyId, action, actor, changes)
+	if err != nil {
+		return v.serviceError("failed to encode the audit log changes", "63", err)
+	}
 
-			err := v.vacancyDisabilitiesRepo.UpsertVacancyDisability(disabilityModel, tx)
-			if err.Code != "" {
-				return err
-			}
-		}
+	return v.auditLogRepo.CreateAuditLog(auditLog, tx)
+}
 
-		return nil
-	})
+// WithContext returns a copy of the service that logs with the request id of ctx
+func (v *vacancyService) WithContext(ctx context.Context) VacancyService {
+	service := *v
+	service.ctx = ctx
 
-	if errTx != nil {
-		return vacancyServiceError("failed to create the vacancy", "01")
+	return &service
+}
+
+func vacancyNotFoundError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.NotFoundErrorCode, utils.VacancyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+func vacancyForbiddenError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ForbiddenErrorCode, utils.VacancyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
+
+// authorizeVacancyCompany lets admins through and company users only for their own company
+func (v *vacancyService) authorizeVacancyCompany(companyId int, caller model.UserClaims) utils.Error {
+	if caller.Role == string(enum.AdminRole) {
+		return utils.Error{}
+	}
+
+	if caller.Role != string(enum.CompanyRole) {
+		return vacancyForbiddenError("the user can't manage vacancies", "38")
+	}
+
+	company, err := v.companyRepo.GetCompanyByUserId(caller.Id)
+	if err.IsError() {
+		return v.serviceError("failed to get the company", "39", err)
+	}
+
+	if company.Id == 0 || company.Id != companyId {
+		return vacancyForbiddenError("the vacancy belongs to another company", "40")
 	}
 
 	return utils.Error{}
 }
 
-func (v *vacancyService) ListVacancies(perPage int, companyId int, disabilityId int, candidateId int, area string, contractType enum.VacancyContractType, searchText string) ([]modelVacancy.VacancySimpleResponse, utils.Error) {
-	var vacanciesResponse []modelVacancy.VacancySimpleResponse
+func vacancyConflictError(message string, code string) utils.Error {
+	errorCode := utils.NewErrorCode(utils.ConflictErrorCode, utils.VacancyErrorType, code)
+
+	return utils.NewError(message, errorCode)
+}
 
-	vacancies, err := v.vacancyRepo.ListVacancies(companyId, area, contractType, searchText)
-	if err.Code != "" {
-		return []modelVacancy.VacancySimpleResponse{}, vacancyServiceError("failed to list the vacancies", "02")
+// newVacancySlug builds the slug from the title and a short random id, appending an
+// incrementing suffix on the unlikely collision
+func (v *vacancyService) newVacancySlug(title string, tx *gorm.DB) (string, utils.Error) {
+	shortId := make([]byte, 3)
+	if _, err := rand.Read(shortId); err != nil {
+		return "", v.serviceError("failed to generate the vacancy slug", "41", err)
 	}
 
-DisabilityLoop:
-	for _, vacancy := range vacancies {
-		var disabilities []model.DisabilityResponse
+	base := strings.Trim(utils.Slugify(title)+"-"+hex.EncodeToString(shortId), "-")
+	slug := base
 
-		vacancyDisabilities, err := v.vacancyDisabilitiesRepo.GetVacancyDisabilities(vacancy.Id)
-		if err.Code != "" {
-			return []modelVacancy.VacancySimpleResponse{}, vacancyServiceError("failed to get the disabilities", "03")
+	for suffix := 2; ; suffix++ {
+		exists, err := v.vacancyRepo.VacancySlugExists(slug, tx)
+		if err.IsError() {
+			return "", err
 		}
 
-		uniqueDisabilities := map[int]bool{}
-
-		for _, vacancyDisability := range vacancyDisabilities {
-			disabilities = append(disabilities, vacancyDisability.Disability.ToResponse())
-			uniqueDisabilities[vacancyDisability.Disability.Id] = true
+		if !exists {
+			return slug, utils.Error{}
 		}
 
-		if disabilityId != 0 && !uniqueDisabilities[disabilityId] {
-			continue DisabilityLoop
-		}
+		slug = base + "-" + strconv.Itoa(suffix)
+	}
+}
 
-		if candidateId != 0 {
-			vacancyApplies, err := v.vacancyAppliesRepo.ListVacancyAppliesByVacancyId(vacancy.Id)
-			if err.Code != "" {
-				return []modelVacancy.VacancySimpleResponse{}, vacancyServiceError("failed to get the vacancy applies", "04")
-			}
+const (
+	defaultIdempotencyKeyTTL      = 24 * time.Hour
+	defaultDuplicateVacancyWindow = 7 * 24 * time.Hour
+	defaultMaxOpenVacancies       = 50
+)
 
-			var candidateIds []int
+func idempotencyKeyTTL() time.Duration {
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err != nil || vacancyConfig.IdempotencyKeyTTL <= 0 {
+		return defaultIdempotencyKeyTTL
+	}
 
-			for _, vacancyApply := range vacancyApplies {
-				candidateIds = append(candidateIds, vacancyApply.CandidateId)
-			}
+	return vacancyConfig.IdempotencyKeyTTL
+}
 
-			if !slices.Contains(candidateIds, candidateId) {
-				continue DisabilityLoop
-			}
-		}
+// validateDisabilities checks every disability of the request is in the catalog, so
+// an unknown id fails before the transaction instead of on the foreign key
+func (v *vacancyService) validateDisabilities(disabilities []modelVacancy.VacancyDisabilityRequest) utils.Error {
+	ids := []int{}
+	for _, disability := range disabilities {
+		ids = append(ids, int(disability))
+	}
 
-		if len(vacanciesResponse) >= perPage {
-			break
+	existingIds, err := v.disabilityRepo.ListExistingDisabilityIds(ids)
+	if err.IsError() {
+		return v.serviceError("failed to get the disabilities", "82", err)
+	}
+
+	existing := map[int]bool{}
+	for _, id := range existingIds {
+		existing[id] = true
+	}
+
+	invalidIds := []string{}
+	for _, id := range ids {
+		if !existing[id] {
+			invalidIds = append(invalidIds, strconv.Itoa(id))
 		}
+	}
 
-		vacanciesResponse = append(vacanciesResponse, vacancy.ToSimpleResponse(disabilities))
+	if len(invalidIds) > 0 {
+		message := "invalid disabilities: " + strings.Join(invalidIds, ", ")
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "21")
+
+		return utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "disabilities", Message: message}})
 	}
 
-	return vacanciesResponse, utils.Error{}
+	return utils.Error{}
 }
 
-func (v *vacancyService) GetVacancyById(id int, candidateId int) (modelVacancy.VacancyResponse, utils.Error) {
-	vacancy, err := v.vacancyRepo.GetVacancyById(id)
-	if err.Code != "" {
-		return modelVacancy.VacancyResponse{}, vacancyServiceError("failed to get the vacancy", "03")
+// maxOpenVacancies is how many open vacancies the company can have, the override of
+// the company wins over the config. Zero means no cap
+func maxOpenVacancies(company model.Company) int {
+	if company.MaxOpenVacancies != nil {
+		return *company.MaxOpenVacancies
 	}
 
-	skills, err := v.skillsRepo.ListSkillsByVacancyId(id)
-	if err.Code != "" {
-		return modelVacancy.VacancyResponse{}, vacancyServiceError("failed to get the skills", "04")
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err != nil {
+		return defaultMaxOpenVacancies
 	}
 
-	requirements, err := v.requirementsRepo.ListRequirementsByVacancyId(id)
-	if err.Code != "" {
-		return modelVacancy.VacancyResponse{}, vacancyServiceError("failed to get the requirements", "05")
-	}
+	return vacancyConfig.MaxOpenPerCompany
+}
 
-	responsabilities, err := v.responsabilitiesRepo.ListResponsabilitiesByVacancyId(id)
-	if err.Code != "" {
-		return modelVacancy.VacancyResponse{}, vacancyServiceError("failed to get the responsabilities", "06")
+// checkOpenVacanciesLimit rejects opening more vacancies than the cap of the company
+// allows. The company row is locked in the transaction, so the count holds until the
+// vacancies are opened in it
+func (v *vacancyService) checkOpenVacanciesLimit(companyId int, opening int, tx *gorm.DB) utils.Error {
+	company, err := v.companyRepo.GetCompanyByIdForUpdate(companyId, tx)
+	if err.IsError() {
+		return v.serviceError("failed to get the company", "76", err)
 	}
 
-	vacancyDisabilities, err := v.vacancyDisabilitiesRepo.GetVacancyDisabilities(id)
-	if err.Code != "" {
-		return modelVacancy.VacancyResponse{}, vacancyServiceError("failed to get the disabilities", "07")
+	limit := maxOpenVacancies(company)
+	if limit <= 0 {
+		return utils.Error{}
 	}
 
-	disabilities := []model.DisabilityResponse{}
-	for _, vacancyDisability := range vacancyDisabilities {
-		disabilities = append(disabilities, vacancyDisability.Disability.ToResponse())
+	total, err := v.vacancyRepo.CountVacanciesByCompany(companyId, tx)
+	if err.IsError() {
+		return v.serviceError("failed to count the company vacancies", "77", err)
 	}
 
-	vacancyResponse := vacancy.ToResponse(
-		disabilities,
-		skills,
-		responsabilities,
-		requirements,
-	)
+	if total+opening > limit {
+		message := fmt.Sprintf("the company reached the limit of %d open vacancies, close one before opening another", limit)
+		errorCode := utils.NewErrorCode(utils.ConflictErrorCode, utils.VacancyErrorType, "78")
 
-	if candidateId != 0 {
-		vacancyApplies, err := v.vacancyAppliesRepo.ListVacancyAppliesByVacancyIdAndCandidateId(id, candidateId)
-		if err.Code != "" {
-			return modelVacancy.VacancyResponse{}, vacancyServiceError("failed to get the vacancy apply", "08")
-		}
+		return utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "max_open_vacancies", Message: message}})
+	}
 
-		vacancyResponse.CandidateAlreadyApplied = len(vacancyApplies) > 0
+	return utils.Error{}
+}
+
+// duplicateVacancyWindow is how far back an open vacancy blocks an identical one,
+// zero disables the check
+func duplicateVacancyWindow() time.Duration {
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err != nil {
+		return defaultDuplicateVacancyWindow
 	}
 
-	return vacancyResponse, utils.Error{}
+	return vacancyConfig.DuplicateWindow
 }
 
-func (v *vacancyService) UpdateVacancy(vacancy modelVacancy.VacancyRequest, id int) utils.Error {
-	vacancyModel := vacancy.ToModel()
+// CreateVacancy creates the vacancy and returns its id. When an idempotency key
+// is given and was already used before expiring, the id of the vacancy created
+// with it is returned instead of inserting a new one. Unless allow duplicate is set,
+// an open vacancy of the company with the same title and area posted in the
+// duplicate window is a conflict, as is a company over its cap of open vacancies
+func (v *vacancyService) CreateVacancy(vacancy modelVacancy.VacancyRequest, idempotencyKey string) (int, utils.Error) {
+	if err := utils.ValidationFailed(utils.VacancyErrorType, vacancy.Validate()); err.IsError() {
+		return 0, err
+	}
 
-	_, err := v.vacancyRepo.GetVacancyById(id)
-	if err.Code != "" {
-		return vacancyServiceError("failed to get the vacancy", "07")
+	if err := sanitizeVacancyDescription(&vacancy); err.IsError() {
+		return 0, err
 	}
 
-	vacancyModel.Id = id
+	if err := v.validateDisabilities(vacancy.Disabilities); err.IsError() {
+		return 0, err
+	}
+
+	vacancyModel := vacancy.ToModel()
+	vacancyId := 0
+	conflictErr := utils.Error{}
+
+	vacancyModel.Status = enum.VacancyDraft
+	if vacancy.Publish {
+		publishedAt := time.Now()
+
+		vacancyModel.Status = enum.VacancyPublished
+		vacancyModel.PublishedAt = &publishedAt
+	}
 
 	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
-		err := v.vacancyRepo.UpdateVacancy(*vacancyModel, tx)
-		if err.Code != "" {
-			return err
+		if idempotencyKey != "" {
+			idempotencyKeyDb, err := v.idempotencyKeyRepo.GetIdempotencyKey(idempotencyKey, tx)
+			if err.IsError() {
+				return err
+			}
+
+			if idempotencyKeyDb.Id != 0 {
+				vacancyId = idempotencyKeyDb.VacancyId
+				return nil
+			}
+
+			err = v.idempotencyKeyRepo.DeleteExpiredIdempotencyKey(idempotencyKey, tx)
+			if err.IsError() {
+				return err
+			}
 		}
 
-		err = v.skillsRepo.DeleteSkillsByVacancyId(id, tx)
-		if err.Code != "" {
-			return err
+		// drafts are not open, they are checked when published
+		if vacancy.Publish {
+			if err := v.checkOpenVacanciesLimit(vacancyModel.CompanyId, 1, tx); err.IsError() {
+				conflictErr = err
+
+				return err
+			}
 		}
 
-		err = v.requirementsRepo.DeleteRequirementsByVacancyId(id, tx)
-		if err.Code != "" {
-			return err
+		if window := duplicateVacancyWindow(); !vacancy.AllowDuplicate && window > 0 {
+			duplicate, err := v.vacancyRepo.FindRecentDuplicateVacancy(vacancyModel.CompanyId, vacancyModel.Title, vacancyModel.Area, time.Now().Add(-window), tx)
+			if err.IsError() {
+				return err
+			}
+
+			if duplicate.Id != 0 {
+				message := fmt.Sprintf("the company already has the open vacancy %d with the same title and area, update it instead or set allow_duplicate", duplicate.Id)
+				errorCode := utils.NewErrorCode(utils.ConflictErrorCode, utils.VacancyErrorType, "62")
+				conflictErr = utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "vacancy_id", Message: message}})
+
+				return conflictErr
+			}
 		}
 
-		err = v.responsabilitiesRepo.DeleteResponsabilitiesByVacancyId(id, tx)
-		if err.Code != "" {
+		slug, err := v.newVacancySlug(vacancy.Title, tx)
+		if err.IsError() {
 			return err
 		}
 
-		err = v.vacancyDisabilitiesRepo.ClearVacancyDisability(id, tx)
-		if err.Code != "" {
+		vacancyModel.Slug = &slug
+
+		createdVacancyId, err := v.vacancyRepo.UpsertVacancy(*vacancyModel, tx)
+		if err.IsError() {
 			return err
 		}
 
+		vacancyId = createdVacancyId
+
+		skills := []modelVacancy.VacancySkill{}
 		for _, skill := range vacancy.Skills {
 			skillModel := skill.ToModel()
-			skillModel.VacancyId = id
+			skillModel.VacancyId = vacancyId
 
-			_, err := v.skillsRepo.CreateSkill(*skillModel, tx)
-			if err.Code != "" {
-				return err
-			}
+			skills = append(skills, *skillModel)
+		}
+
+		if err := v.skillsRepo.CreateSkills(skills, tx); err.IsError() {
+			return err
 		}
 
+		requirements := []modelVacancy.VacancyRequirement{}
 		for _, requirement := range vacancy.Requirements {
 			requirementModel := requirement.ToModel()
-			requirementModel.VacancyId = id
+			requirementModel.VacancyId = vacancyId
 
-			_, err := v.requirementsRepo.CreateRequirement(*requirementModel, tx)
-			if err.Code != "" {
-				return err
-			}
+			requirements = append(requirements, *requirementModel)
+		}
+
+		if err := v.requirementsRepo.CreateRequirements(requirements, tx); err.IsError() {
+			return err
 		}
 
+		responsabilities := []modelVacancy.VacancyResponsability{}
 		for _, responsability := range vacancy.Responsabilities {
 			responsabilityModel := responsability.ToModel()
-			responsabilityModel.VacancyId = id
+			responsabilityModel.VacancyId = vacancyId
 
-			_, err := v.responsabilitiesRepo.CreateResponsability(*responsabilityModel, tx)
-			if err.Code != "" {
-				return err
-			}
+			responsabilities = append(responsabilities, *responsabilityModel)
+		}
+
+		if err := v.responsabilitiesRepo.CreateResponsabilities(responsabilities, tx); err.IsError() {
+			return err
+		}
+
+		err = v.accommodationsRepo.ReplaceAccommodations(vacancyId, modelVacancy.NormalizeAccommodations(vacancy.Accommodations), tx)
+		if err.IsError() {
+			return err
 		}
 
 		for _, disability := range vacancy.Disabilities {
 			disabilityModel := modelVacancy.VacancyDisability{
-				VacancyId:    id,
+				VacancyId:    vacancyId,
 				DisabilityId: int(disability),
 			}
 
 			err := v.vacancyDisabilitiesRepo.UpsertVacancyDisability(disabilityModel, tx)
-			if err.Code != "" {
+			if err.IsError() {
+				return err
+			}
+		}
+
+		err = v.recordVacancyAudit(vacancyId, enum.AuditCreate, model.DiffAuditFields(nil, vacancyModel.AuditFields()), tx)
+		if err.IsError() {
+			return err
+		}
+
+		if idempotencyKey != "" {
+			idempotencyKeyModel := modelVacancy.VacancyIdempotencyKey{
+				Key:       idempotencyKey,
+				VacancyId: vacancyId,
+				ExpiresAt: time.Now().Add(idempotencyKeyTTL()),
+			}
+
+			err := v.idempotencyKeyRepo.CreateIdempotencyKey(idempotencyKeyModel, tx)
+			if err.IsError() {
 				return err
 			}
 		}
@@ -310,130 +508,1699 @@ func (v *vacancyService) UpdateVacancy(vacancy modelVacancy.VacancyRequest, id i
 		return nil
 	})
 
+	if conflictErr.IsError() {
+		return 0, conflictErr
+	}
+
+	if errTx != nil && idempotencyKey != "" {
+		// a concurrent request with the same key may have committed first
+		idempotencyKeyDb, err := v.idempotencyKeyRepo.GetIdempotencyKey(idempotencyKey, nil)
+		if !err.IsError() && idempotencyKeyDb.Id != 0 {
+			return idempotencyKeyDb.VacancyId, utils.Error{}
+		}
+	}
+
 	if errTx != nil {
-		return vacancyServiceError("failed to update the vacancy", "08")
+		return 0, v.serviceError("failed to create the vacancy", "01", errTx)
+	}
+
+	return vacancyId, utils.Error{}
+}
+
+const (
+	defaultVacanciesPerPage    = 20
+	defaultMaxVacanciesPerPage = 100
+	maxVacanciesByIds          = 100
+	defaultMaxDescription      = 10000
+)
+
+// sanitizeVacancyDescription rejects descriptions over the configured length and keeps
+// only the safe html formatting subset of the rest
+func sanitizeVacancyDescription(vacancy *modelVacancy.VacancyRequest) utils.Error {
+	maxDescription := defaultMaxDescription
+
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err == nil && vacancyConfig.MaxDescription > 0 {
+		maxDescription = vacancyConfig.MaxDescription
+	}
+
+	if utf8.RuneCountInString(vacancy.Description) > maxDescription {
+		message := fmt.Sprintf("description must have at most %d characters", maxDescription)
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "10")
+
+		return utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "description", Message: message}})
 	}
 
+	vacancy.Description = utils.SanitizeHtml(vacancy.Description)
+
 	return utils.Error{}
 }
 
-func (v *vacancyService) DeleteVacancy(id int) utils.Error {
-	_, err := v.vacancyRepo.GetVacancyById(id)
-	if err.Code != "" {
-		return vacancyServiceError("failed to get the vacancy", "07")
+func maxVacanciesPerPage() int {
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err != nil || vacancyConfig.MaxPerPage <= 0 {
+		return defaultMaxVacanciesPerPage
 	}
 
-	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
-		err := v.skillsRepo.DeleteSkillsByVacancyId(id, tx)
-		if err.Code != "" {
-			return err
-		}
+	return vacancyConfig.MaxPerPage
+}
 
-		err = v.requirementsRepo.DeleteRequirementsByVacancyId(id, tx)
-		if err.Code != "" {
-			return err
-		}
+// ListVacancies defaults the page to 1 and the page size to 20, clamping it to the
+// configured maximum. Negative values are rejected
+func (v *vacancyService) ListVacancies(filters modelVacancy.VacancyFilters, page int, perPage int) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error) {
+	var vacanciesResponse []modelVacancy.VacancySimpleResponse
 
-		err = v.responsabilitiesRepo.DeleteResponsabilitiesByVacancyId(id, tx)
-		if err.Code != "" {
-			return err
-		}
+	if page < 0 || perPage < 0 {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "06")
 
-		err = v.vacancyDisabilitiesRepo.ClearVacancyDisability(id, tx)
-		if err.Code != "" {
-			return err
-		}
+		return model.PaginatedResponse[modelVacancy.VacancySimpleResponse]{}, utils.NewError("page and per page must not be negative", errorCode)
+	}
 
-		err = v.vacancyAppliesRepo.DeleteVacancyAppliesByVacancyId(id, tx)
-		if err.Code != "" {
-			return err
-		}
+	if filters.PostedAfter != nil && filters.PostedBefore != nil && filters.PostedAfter.After(*filters.PostedBefore) {
+		message := "posted after must not be later than posted before"
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "11")
 
-		err = v.vacancyRepo.DeleteVacancy(id)
-		if err.Code != "" {
-			return err
+		return model.PaginatedResponse[modelVacancy.VacancySimpleResponse]{}, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "posted_after", Message: message}})
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if perPage == 0 {
+		perPage = defaultVacanciesPerPage
+	}
+
+	if maxPerPage := maxVacanciesPerPage(); perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	vacancies, total, err := v.vacancyRepo.ListVacancies(filters, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.VacancySimpleResponse]{}, v.serviceError("failed to list the vacancies", "02", err)
+	}
+
+	for _, vacancy := range vacancies {
+		var disabilities []model.DisabilityResponse
+
+		for _, disability := range vacancy.Disabilities {
+			disabilities = append(disabilities, disability.ToResponse())
 		}
 
-		return nil
-	})
+		vacanciesResponse = append(vacanciesResponse, vacancy.ToSimpleResponse(disabilities))
+	}
 
-	if errTx != nil {
-		return vacancyServiceError("failed to delete the vacancy", "09")
+	return model.NewPaginatedResponse(vacanciesResponse, total, page, perPage), utils.Error{}
+}
+
+// ListCompanyVacancies lists the vacancies of the company including its drafts, so only the
+// company itself or an admin can see it
+func (v *vacancyService) ListCompanyVacancies(companyId int, filters modelVacancy.VacancyFilters, page int, perPage int, caller model.UserClaims) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error) {
+	if err := v.authorizeVacancyCompany(companyId, caller); err.IsError() {
+		return model.PaginatedResponse[modelVacancy.VacancySimpleResponse]{}, err
 	}
 
-	return utils.Error{}
+	filters.CompanyId = companyId
+	filters.IncludeDrafts = true
+
+	return v.ListVacancies(filters, page, perPage)
 }
 
-func (v *vacancyService) CandidateApplyVacancy(candidateId int, vacancyId int) utils.Error {
-	_, err := v.vacancyRepo.GetVacancyById(vacancyId)
-	if err.Code != "" {
-		return vacancyServiceError("failed to get the vacancy", "10")
+const directoryVacanciesPerCompany = 5
+
+// ListCompaniesWithVacancies pages through the companies having open vacancies for the
+// company directory, each with its newest open vacancies and their total
+func (v *vacancyService) ListCompaniesWithVacancies(page int, perPage int) (model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse], utils.Error) {
+	if page < 0 || perPage < 0 {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "13")
+
+		return model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse]{}, utils.NewError("page and per page must not be negative", errorCode)
 	}
 
-	_, err = v.personRepo.GetPersonById(candidateId, nil)
-	if err.Code != "" {
-		return vacancyServiceError("failed to get the person", "11")
+	if page < 1 {
+		page = 1
 	}
 
-	vacancyApplyDb, _ := v.vacancyAppliesRepo.GetVacancyApply(vacancyId, candidateId)
-	if vacancyApplyDb.Id != 0 {
-		return vacancyServiceError("the candidate already applied to the vacancy", "13")
+	if perPage == 0 {
+		perPage = defaultVacanciesPerPage
 	}
 
-	vacancyApply := modelVacancy.VacancyApply{
-		VacancyId:   vacancyId,
-		CandidateId: candidateId,
-		Status:      enum.VacancyApplyApplied,
+	if maxPerPage := maxVacanciesPerPage(); perPage > maxPerPage {
+		perPage = maxPerPage
 	}
 
-	_, err = v.vacancyAppliesRepo.CreateVacancyApply(vacancyApply)
-	if err.Code != "" {
-		return vacancyServiceError("failed to apply the vacancy", "12")
+	companyIds, total, err := v.vacancyRepo.ListCompanyIdsWithOpenVacancies(page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse]{}, v.serviceError("failed to list the companies with vacancies", "69", err)
 	}
 
-	return utils.Error{}
-}
+	if len(companyIds) == 0 {
+		return model.NewPaginatedResponse([]modelVacancy.CompanyVacanciesResponse{}, total, page, perPage), utils.Error{}
+	}
 
-func (v *vacancyService) GetVacancyAppliesByVacancyId(vacancyId int) ([]modelVacancy.VacancyApplyResponse, utils.Error) {
-	vacancyApplies, err := v.vacancyAppliesRepo.ListVacancyAppliesByVacancyId(vacancyId)
-	if err.Code != "" {
-		return []modelVacancy.VacancyApplyResponse{}, vacancyServiceError("failed to get the vacancy applies", "13")
+	vacancies, err := v.vacancyRepo.ListOpenVacanciesByCompanies(companyIds, directoryVacanciesPerCompany)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse]{}, v.serviceError("failed to list the company vacancies", "70", err)
 	}
 
-	var vacancyAppliesResponse []modelVacancy.VacancyApplyResponse
-	for _, vacancyApply := range vacancyApplies {
-		person, err := v.personRepo.GetPersonById(vacancyApply.CandidateId, nil)
-		if err.Code != "" {
-			return []modelVacancy.VacancyApplyResponse{}, vacancyServiceError("failed to get the person", "14")
-		}
+	totals, err := v.vacancyRepo.CountOpenVacanciesByCompanies(companyIds)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse]{}, v.serviceError("failed to count the company vacancies", "71", err)
+	}
 
-		candidateDisabilities, err := v.personDisabilitiesRepo.GetPersonDisabilities(vacancyApply.CandidateId)
-		if err.Code != "" {
-			return []modelVacancy.VacancyApplyResponse{}, vacancyServiceError("failed to get the candidate disabilities", "15")
+	companies := map[int]*modelVacancy.CompanyVacanciesResponse{}
+	for _, companyId := range companyIds {
+		companies[companyId] = &modelVacancy.CompanyVacanciesResponse{
+			Id:             companyId,
+			TotalVacancies: totals[companyId],
+			Vacancies:      []modelVacancy.VacancySimpleResponse{},
 		}
+	}
 
-		candidateDisabilitiesResponse := []model.DisabilityResponse{}
-		for _, candidateDisability := range candidateDisabilities {
-			candidateDisabilitiesResponse = append(candidateDisabilitiesResponse, candidateDisability.Disability.ToResponse())
-		}
+	for _, vacancy := range vacancies {
+		company := companies[vacancy.CompanyId]
+		company.Name = vacancy.Company.Name
+		company.LogoUrl = vacancy.Company.LogoUrl
+		company.Verified = vacancy.Company.Verified
 
-		vacancyApplyResponse := modelVacancy.VacancyApplyResponse{
-			Candidate: vacancyApply.Candidate.ToCandidateResponse(candidateDisabilitiesResponse, *person.Address),
-			Status:    vacancyApply.Status,
-			Id:        vacancyApply.Id,
+		disabilities := []model.DisabilityResponse{}
+		for _, disability := range vacancy.Disabilities {
+			disabilities = append(disabilities, disability.ToResponse())
 		}
 
-		vacancyAppliesResponse = append(vacancyAppliesResponse, vacancyApplyResponse)
+		company.Vacancies = append(company.Vacancies, vacancy.ToSimpleResponse(disabilities))
 	}
 
-	return vacancyAppliesResponse, utils.Error{}
+	companiesResponse := make([]modelVacancy.CompanyVacanciesResponse, 0, len(companyIds))
+	for _, companyId := range companyIds {
+		companiesResponse = append(companiesResponse, *companies[companyId])
+	}
+
+	return model.NewPaginatedResponse(companiesResponse, total, page, perPage), utils.Error{}
 }
 
-func (v *vacancyService) UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus) utils.Error {
-	err := v.vacancyAppliesRepo.UpdateVacancyApplyStatus(vacancyApplyId, status)
-	if err.Code != "" {
-		return vacancyServiceError("failed to update the vacancy apply status", "14")
+// ListVacanciesForCandidate lists the open vacancies covering any disability category
+// of the candidate
+func (v *vacancyService) ListVacanciesForCandidate(candidateId int, page int, perPage int, excludeApplied bool) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error) {
+	candidate, err := v.personRepo.GetPersonById(candidateId, nil)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.VacancySimpleResponse]{}, v.serviceError("failed to get the person", "36", err)
 	}
 
-	return utils.Error{}
+	if candidate.Id == 0 {
+		return model.PaginatedResponse[modelVacancy.VacancySimpleResponse]{}, vacancyNotFoundError("candidate not found", "37")
+	}
+
+	filters := modelVacancy.VacancyFilters{
+		EligibleCandidateId: candidateId,
+	}
+
+	if excludeApplied {
+		filters.ExcludeAppliedCandidateId = candidateId
+	}
+
+	return v.ListVacancies(filters, page, perPage)
+}
+
+// GetVacanciesByIds returns the vacancies in the order of the ids, skipping the ones that
+// don't exist
+func (v *vacancyService) GetVacanciesByIds(ids []int) ([]modelVacancy.VacancySimpleResponse, utils.Error) {
+	vacanciesResponse := []modelVacancy.VacancySimpleResponse{}
+
+	if len(ids) == 0 {
+		return vacanciesResponse, utils.Error{}
+	}
+
+	if len(ids) > maxVacanciesByIds {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "09")
+
+		return vacanciesResponse, utils.NewError(fmt.Sprintf("at most %d ids are allowed per call", maxVacanciesByIds), errorCode)
+	}
+
+	vacancies, err := v.vacancyRepo.ListVacanciesByIds(ids)
+	if err.IsError() {
+		return vacanciesResponse, v.serviceError("failed to list the vacancies by ids", "49", err)
+	}
+
+	vacanciesById := map[int]modelVacancy.Vacancy{}
+	for _, vacancy := range vacancies {
+		vacanciesById[vacancy.Id] = vacancy
+	}
+
+	for _, id := range ids {
+		vacancy, ok := vacanciesById[id]
+		if !ok {
+			continue
+		}
+
+		var disabilities []model.DisabilityResponse
+		for _, disability := range vacancy.Disabilities {
+			disabilities = append(disabilities, disability.ToResponse())
+		}
+
+		vacanciesResponse = append(vacanciesResponse, vacancy.ToSimpleResponse(disabilities))
+	}
+
+	return vacanciesResponse, utils.Error{}
+}
+
+func (v *vacancyService) GetVacancyBySlug(slug string) (modelVacancy.VacancyResponse, utils.Error) {
+	vacancy, err := v.vacancyRepo.GetVacancyBySlug(slug)
+	if err.IsError() {
+		return modelVacancy.VacancyResponse{}, v.serviceError("failed to get the vacancy", "42", err)
+	}
+
+	if vacancy.Id == 0 {
+		return modelVacancy.VacancyResponse{}, vacancyNotFoundError("vacancy not found", "43")
+	}
+
+	return v.GetVacancyById(vacancy.Id, 0)
+}
+
+func (v *vacancyService) GetVacancyById(id int, candidateId int) (modelVacancy.VacancyResponse, utils.Error) {
+	vacancy, err := v.vacancyRepo.GetVacancyWithAssociationsById(id)
+	if err.IsError() {
+		return modelVacancy.VacancyResponse{}, v.serviceError("failed to get the vacancy", "03", err)
+	}
+
+	if vacancy.Id == 0 {
+		return modelVacancy.VacancyResponse{}, vacancyNotFoundError("vacancy not found", "91")
+	}
+
+	disabilities := []model.DisabilityResponse{}
+	for _, disability := range vacancy.Disabilities {
+		disabilities = append(disabilities, disability.ToResponse())
+	}
+
+	vacancyResponse := vacancy.ToResponse(
+		disabilities,
+		vacancy.Skills,
+		vacancy.Responsabilities,
+		vacancy.Requirements,
+	)
+
+	if candidateId != 0 {
+		vacancyApplies, err := v.vacancyAppliesRepo.ListVacancyAppliesByVacancyIdAndCandidateId(id, candidateId)
+		if err.IsError() {
+			return modelVacancy.VacancyResponse{}, v.serviceError("failed to get the vacancy apply", "08", err)
+		}
+
+		vacancyResponse.CandidateAlreadyApplied = len(vacancyApplies) > 0
+	}
+
+	return vacancyResponse, utils.Error{}
+}
+
+func (v *vacancyService) UpdateVacancy(vacancy modelVacancy.VacancyRequest, id int, caller model.UserClaims) utils.Error {
+	validation := vacancy.Validate()
+
+	// the version the client read is required, an update without it would overwrite the
+	// changes made since
+	if vacancy.Version == 0 {
+		validation.Add("version", "29", "version is required, send the version of the vacancy that was read")
+	}
+
+	if err := utils.ValidationFailed(utils.VacancyErrorType, validation); err.IsError() {
+		return err
+	}
+
+	if err := sanitizeVacancyDescription(&vacancy); err.IsError() {
+		return err
+	}
+
+	if err := v.validateDisabilities(vacancy.Disabilities); err.IsError() {
+		return err
+	}
+
+	vacancyModel := vacancy.ToModel()
+
+	vacancyDb, err := v.vacancyRepo.GetVacancyById(id)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "07", err)
+	}
+
+	if vacancyDb.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "16")
+	}
+
+	if err := v.authorizeVacancyCompany(vacancyDb.CompanyId, caller); err.IsError() {
+		return err
+	}
+
+	// a company can't move its vacancy to another company either
+	if err := v.authorizeVacancyCompany(vacancy.CompanyId, caller); err.IsError() {
+		return err
+	}
+
+	vacancyModel.Id = id
+	vacancyModel.Version = vacancy.Version
+
+	// the vacancy row is written first, so the children are read under its lock and a
+	// concurrent update fails the version check instead of interleaving
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		_, err := v.vacancyRepo.UpsertVacancy(*vacancyModel, tx)
+		if err.IsError() {
+			return err
+		}
+
+		err = v.syncVacancySkills(id, vacancy.Skills, tx)
+		if err.IsError() {
+			return err
+		}
+
+		err = v.syncVacancyRequirements(id, vacancy.Requirements, tx)
+		if err.IsError() {
+			return err
+		}
+
+		err = v.syncVacancyResponsabilities(id, vacancy.Responsabilities, tx)
+		if err.IsError() {
+			return err
+		}
+
+		err = v.syncVacancyDisabilities(id, vacancy.Disabilities, tx)
+		if err.IsError() {
+			return err
+		}
+
+		err = v.accommodationsRepo.ReplaceAccommodations(id, modelVacancy.NormalizeAccommodations(vacancy.Accommodations), tx)
+		if err.IsError() {
+			return err
+		}
+
+		changes := model.DiffAuditFields(vacancyDb.AuditFields(), vacancyDb.AuditFieldsAfterUpdate(vacancyModel))
+
+		err = v.recordVacancyAudit(id, enum.AuditUpdate, changes, tx)
+		if err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		if txError, ok := errTx.(utils.Error); ok && txError.Code == repoVacancy.VacancyModifiedErrorCode {
+			return vacancyConflictError("the vacancy was modified by another request, reload it and try again", "48")
+		}
+
+		if txError, ok := errTx.(utils.Error); ok && utils.HttpStatus(txError) == http.StatusNotFound {
+			return txError
+		}
+
+		return v.serviceError("failed to update the vacancy", "08", errTx)
+	}
+
+	return utils.Error{}
+}
+
+// syncVacancySkills keeps the stored skills that are still requested, the skills have no
+// id in the api so they are matched by their text. The rest of the stored ones are
+// deleted and the requested ones left over are created
+func (v *vacancyService) syncVacancySkills(vacancyId int, skills []modelVacancy.VacancySkillRequest, tx *gorm.DB) utils.Error {
+	current, err := v.skillsRepo.ListSkillsByVacancyId(vacancyId, tx)
+	if err.IsError() {
+		return err
+	}
+
+	stored := map[string][]int{}
+	for _, skill := range current {
+		stored[skill.Skill] = append(stored[skill.Skill], skill.Id)
+	}
+
+	for _, skill := range skills {
+		skillModel := skill.ToModel()
+		skillModel.VacancyId = vacancyId
+
+		if ids := stored[skillModel.Skill]; len(ids) > 0 {
+			stored[skillModel.Skill] = ids[1:]
+			continue
+		}
+
+		if _, err := v.skillsRepo.CreateSkill(*skillModel, tx); err.IsError() {
+			return err
+		}
+	}
+
+	for _, ids := range stored {
+		for _, skillId := range ids {
+			if err := v.skillsRepo.DeleteSkill(skillId, tx); err.IsError() {
+				return err
+			}
+		}
+	}
+
+	return utils.Error{}
+}
+
+// syncVacancyRequirements updates the stored requirements matched by id when they
+// changed, creates the ones without an id and deletes the stored ones no longer sent
+func (v *vacancyService) syncVacancyRequirements(vacancyId int, requirements []modelVacancy.VacancyRequirementRequest, tx *gorm.DB) utils.Error {
+	current, err := v.requirementsRepo.ListRequirementsByVacancyId(vacancyId, tx)
+	if err.IsError() {
+		return err
+	}
+
+	stored := map[int]modelVacancy.VacancyRequirement{}
+	for _, requirement := range current {
+		stored[requirement.Id] = requirement
+	}
+
+	for _, requirement := range requirements {
+		requirementModel := requirement.ToModel()
+		requirementModel.VacancyId = vacancyId
+
+		if requirement.Id == 0 {
+			if _, err := v.requirementsRepo.CreateRequirement(*requirementModel, tx); err.IsError() {
+				return err
+			}
+
+			continue
+		}
+
+		storedRequirement, ok := stored[requirement.Id]
+		if !ok {
+			return vacancyNotFoundError(fmt.Sprintf("requirement %d not found in the vacancy", requirement.Id), "92")
+		}
+
+		delete(stored, requirement.Id)
+
+		if storedRequirement.Requirement != requirementModel.Requirement || storedRequirement.Type != requirementModel.Type {
+			if err := v.requirementsRepo.UpdateRequirement(*requirementModel, requirement.Id, tx); err.IsError() {
+				return err
+			}
+		}
+	}
+
+	for requirementId := range stored {
+		if err := v.requirementsRepo.DeleteRequirement(requirementId, tx); err.IsError() {
+			return err
+		}
+	}
+
+	return utils.Error{}
+}
+
+// syncVacancyResponsabilities matches the responsabilities by their text like the skills,
+// as they have no id in the api either
+func (v *vacancyService) syncVacancyResponsabilities(vacancyId int, responsabilities []modelVacancy.VacancyResponsabilityRequest, tx *gorm.DB) utils.Error {
+	current, err := v.responsabilitiesRepo.ListResponsabilitiesByVacancyId(vacancyId, tx)
+	if err.IsError() {
+		return err
+	}
+
+	stored := map[string][]int{}
+	for _, responsability := range current {
+		stored[responsability.Responsability] = append(stored[responsability.Responsability], responsability.Id)
+	}
+
+	for _, responsability := range responsabilities {
+		responsabilityModel := responsability.ToModel()
+		responsabilityModel.VacancyId = vacancyId
+
+		if ids := stored[responsabilityModel.Responsability]; len(ids) > 0 {
+			stored[responsabilityModel.Responsability] = ids[1:]
+			continue
+		}
+
+		if _, err := v.responsabilitiesRepo.CreateResponsability(*responsabilityModel, tx); err.IsError() {
+			return err
+		}
+	}
+
+	for _, ids := range stored {
+		for _, responsabilityId := range ids {
+			if err := v.responsabilitiesRepo.DeleteResponsability(responsabilityId, tx); err.IsError() {
+				return err
+			}
+		}
+	}
+
+	return utils.Error{}
+}
+
+func (v *vacancyService) syncVacancyDisabilities(vacancyId int, disabilities []modelVacancy.VacancyDisabilityRequest, tx *gorm.DB) utils.Error {
+	current, err := v.vacancyDisabilitiesRepo.GetVacancyDisabilities(vacancyId, tx)
+	if err.IsError() {
+		return err
+	}
+
+	requested := map[int]bool{}
+
+	for _, disability := range disabilities {
+		requested[int(disability)] = true
+
+		disabilityModel := modelVacancy.VacancyDisability{
+			VacancyId:    vacancyId,
+			DisabilityId: int(disability),
+		}
+
+		if err := v.vacancyDisabilitiesRepo.UpsertVacancyDisability(disabilityModel, tx); err.IsError() {
+			return err
+		}
+	}
+
+	for _, vacancyDisability := range current {
+		if requested[vacancyDisability.DisabilityId] {
+			continue
+		}
+
+		if err := v.vacancyDisabilitiesRepo.DeleteVacancyDisability(vacancyId, vacancyDisability.DisabilityId, tx); err.IsError() {
+			return err
+		}
+	}
+
+	return utils.Error{}
+}
+
+// DeleteVacancy soft deletes the vacancy alone. Its skills, requirements, disabilities and
+// applies are kept, so the deleted posting can still be audited and the candidates keep
+// their application history
+func (v *vacancyService) DeleteVacancy(id int, caller model.UserClaims) utils.Error {
+	vacancy, err := v.vacancyRepo.GetVacancyById(id)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "07", err)
+	}
+
+	if vacancy.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "17")
+	}
+
+	if err := v.authorizeVacancyCompany(vacancy.CompanyId, caller); err.IsError() {
+		return err
+	}
+
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		err := v.vacancyRepo.DeleteVacancy(id, tx)
+		if err.IsError() {
+			return err
+		}
+
+		err = v.recordVacancyAudit(id, enum.AuditDelete, model.DiffAuditFields(vacancy.AuditFields(), nil), tx)
+		if err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		return v.serviceError("failed to delete the vacancy", "09", errTx)
+	}
+
+	return utils.Error{}
+}
+
+func (v *vacancyService) CloseVacancy(id int, caller model.UserClaims) utils.Error {
+	vacancy, err := v.vacancyRepo.GetVacancyById(id)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "25", err)
+	}
+
+	if vacancy.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "26")
+	}
+
+	if err := v.authorizeVacancyCompany(vacancy.CompanyId, caller); err.IsError() {
+		return err
+	}
+
+	if vacancy.ClosedAt != nil {
+		return vacancyConflictError("the vacancy is already closed", "27")
+	}
+
+	err = v.vacancyRepo.CloseVacancy(id)
+	if err.IsError() {
+		return v.serviceError("failed to close the vacancy", "28", err)
+	}
+
+	return utils.Error{}
+}
+
+// FeatureVacancy sets or unsets the featured flag, which lists the vacancy first until
+// the featured until passes. Only the admins reach it, for the partner companies
+func (v *vacancyService) FeatureVacancy(id int, request modelVacancy.VacancyFeatureRequest) utils.Error {
+	if err := request.Validate(); err.IsError() {
+		return err
+	}
+
+	vacancy, err := v.vacancyRepo.GetVacancyById(id)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "79", err)
+	}
+
+	if vacancy.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "80")
+	}
+
+	featuredUntil := request.FeaturedUntil
+	if !request.Featured {
+		featuredUntil = nil
+	}
+
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		if err := v.vacancyRepo.FeatureVacancy(id, request.Featured, featuredUntil, tx); err.IsError() {
+			return err
+		}
+
+		before := vacancy.AuditFields()
+
+		vacancy.Featured = request.Featured
+		vacancy.FeaturedUntil = featuredUntil
+
+		if err := v.recordVacancyAudit(id, enum.AuditUpdate, model.DiffAuditFields(before, vacancy.AuditFields()), tx); err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		return v.serviceError("failed to feature the vacancy", "81", errTx)
+	}
+
+	return utils.Error{}
+}
+
+// ReopenVacancy clears the closed date and replaces the expiration, a nil expiration keeps
+// the vacancy open until it is closed again
+func (v *vacancyService) ReopenVacancy(id int, newExpiresAt *time.Time, caller model.UserClaims) utils.Error {
+	vacancy, err := v.vacancyRepo.GetVacancyByIdUnscoped(id)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "44", err)
+	}
+
+	if vacancy.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "45")
+	}
+
+	if vacancy.Model != nil && vacancy.DeletedAt.Valid {
+		return vacancyNotFoundError("the vacancy was deleted", "46")
+	}
+
+	if err := v.authorizeVacancyCompany(vacancy.CompanyId, caller); err.IsError() {
+		return err
+	}
+
+	if vacancy.Status == enum.VacancyDraft {
+		return vacancyConflictError("a draft vacancy must be published instead of reopened", "54")
+	}
+
+	var expiresAt *string
+	if newExpiresAt != nil {
+		if newExpiresAt.Before(time.Now().Truncate(24 * time.Hour)) {
+			errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "08")
+
+			return utils.NewErrorWithFields("expires at must not be in the past", errorCode, []model.Field{{Name: "expires_at", Message: "expires at must not be in the past"}})
+		}
+
+		date := newExpiresAt.Format("2006-01-02")
+		expiresAt = &date
+	}
+
+	limitErr := utils.Error{}
+
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		// an open vacancy only gets a new expiration, it's already counted
+		if !vacancy.IsOpen() {
+			if err := v.checkOpenVacanciesLimit(vacancy.CompanyId, 1, tx); err.IsError() {
+				limitErr = err
+
+				return err
+			}
+		}
+
+		if err := v.vacancyRepo.ReopenVacancy(id, expiresAt, tx); err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if limitErr.IsError() {
+		return limitErr
+	}
+
+	if errTx != nil {
+		return v.serviceError("failed to reopen the vacancy", "47", errTx)
+	}
+
+	return utils.Error{}
+}
+
+// PublishVacancy moves a draft vacancy to published, making it visible in the listings
+func (v *vacancyService) PublishVacancy(id int, caller model.UserClaims) utils.Error {
+	vacancy, err := v.vacancyRepo.GetVacancyById(id)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "51", err)
+	}
+
+	if vacancy.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "52")
+	}
+
+	if err := v.authorizeVacancyCompany(vacancy.CompanyId, caller); err.IsError() {
+		return err
+	}
+
+	if vacancy.Status != enum.VacancyDraft {
+		return vacancyConflictError("only draft vacancies can be published", "53")
+	}
+
+	limitErr := utils.Error{}
+
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		if err := v.checkOpenVacanciesLimit(vacancy.CompanyId, 1, tx); err.IsError() {
+			limitErr = err
+
+			return err
+		}
+
+		if err := v.vacancyRepo.PublishVacancy(id, tx); err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if limitErr.IsError() {
+		return limitErr
+	}
+
+	if errTx != nil {
+		return v.serviceError("failed to publish the vacancy", "55", errTx)
+	}
+
+	return utils.Error{}
+}
+
+// DuplicateVacancy copies the vacancy with its skills, requirements, responsabilities,
+// accommodations and disabilities into a new draft of the same company. The copy gets
+// its own slug and none of the applications
+func (v *vacancyService) DuplicateVacancy(id int, caller model.UserClaims) (int, utils.Error) {
+	vacancy, err := v.vacancyRepo.GetVacancyWithAssociationsById(id)
+	if err.IsError() {
+		return 0, v.serviceError("failed to get the vacancy", "73", err)
+	}
+
+	if vacancy.Id == 0 {
+		return 0, vacancyNotFoundError("vacancy not found", "74")
+	}
+
+	if err := v.authorizeVacancyCompany(vacancy.CompanyId, caller); err.IsError() {
+		return 0, err
+	}
+
+	vacancyModel := vacancy.DraftCopy()
+	vacancyId := 0
+
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		slug, err := v.newVacancySlug(vacancyModel.Title, tx)
+		if err.IsError() {
+			return err
+		}
+
+		vacancyModel.Slug = &slug
+
+		vacancyId, err = v.vacancyRepo.UpsertVacancy(*vacancyModel, tx)
+		if err.IsError() {
+			return err
+		}
+
+		for _, skill := range vacancy.Skills {
+			skillModel := modelVacancy.VacancySkill{Skill: skill.Skill, VacancyId: vacancyId}
+
+			if _, err := v.skillsRepo.CreateSkill(skillModel, tx); err.IsError() {
+				return err
+			}
+		}
+
+		for _, requirement := range vacancy.Requirements {
+			requirementModel := modelVacancy.VacancyRequirement{Requirement: requirement.Requirement, Type: requirement.Type, VacancyId: vacancyId}
+
+			if _, err := v.requirementsRepo.CreateRequirement(requirementModel, tx); err.IsError() {
+				return err
+			}
+		}
+
+		for _, responsability := range vacancy.Responsabilities {
+			responsabilityModel := modelVacancy.VacancyResponsability{Responsability: responsability.Responsability, VacancyId: vacancyId}
+
+			if _, err := v.responsabilitiesRepo.CreateResponsability(responsabilityModel, tx); err.IsError() {
+				return err
+			}
+		}
+
+		accommodations := []string{}
+		for _, accommodation := range vacancy.Accommodations {
+			accommodations = append(accommodations, accommodation.Accommodation)
+		}
+
+		err = v.accommodationsRepo.ReplaceAccommodations(vacancyId, accommodations, tx)
+		if err.IsError() {
+			return err
+		}
+
+		for _, disability := range vacancy.Disabilities {
+			disabilityModel := modelVacancy.VacancyDisability{
+				VacancyId:    vacancyId,
+				DisabilityId: disability.Id,
+			}
+
+			if err := v.vacancyDisabilitiesRepo.UpsertVacancyDisability(disabilityModel, tx); err.IsError() {
+				return err
+			}
+		}
+
+		err = v.recordVacancyAudit(vacancyId, enum.AuditCreate, model.DiffAuditFields(nil, vacancyModel.AuditFields()), tx)
+		if err.IsError() {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		return 0, v.serviceError("failed to duplicate the vacancy", "75", errTx)
+	}
+
+	return vacancyId, utils.Error{}
+}
+
+// TransferVacancies reassigns every vacancy of a company to another one in a single
+// transaction, for merged or corrected company accounts
+func (v *vacancyService) TransferVacancies(fromCompanyId int, toCompanyId int) (int, utils.Error) {
+	if fromCompanyId == toCompanyId {
+		message := "the vacancies must be transferred to another company"
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "12")
+
+		return 0, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "to_company_id", Message: message}})
+	}
+
+	fromCompany, err := v.companyRepo.GetCompanyById(fromCompanyId)
+	if err.IsError() {
+		return 0, v.serviceError("failed to get the company", "64", err)
+	}
+
+	if fromCompany.Id == 0 {
+		return 0, vacancyNotFoundError("the company to transfer the vacancies from was not found", "65")
+	}
+
+	toCompany, err := v.companyRepo.GetCompanyById(toCompanyId)
+	if err.IsError() {
+		return 0, v.serviceError("failed to get the company", "66", err)
+	}
+
+	if toCompany.Id == 0 {
+		return 0, vacancyNotFoundError("the company to transfer the vacancies to was not found", "67")
+	}
+
+	transferred := 0
+	limitErr := utils.Error{}
+
+	errTx := v.vacancyRepo.BeginTransaction(func(tx *gorm.DB) error {
+		opening, err := v.vacancyRepo.CountVacanciesByCompany(fromCompanyId, tx)
+		if err.IsError() {
+			return err
+		}
+
+		// the open vacancies moved count towards the cap of the target company
+		if opening > 0 {
+			if err := v.checkOpenVacanciesLimit(toCompanyId, opening, tx); err.IsError() {
+				limitErr = err
+
+				return err
+			}
+		}
+
+		vacancyIds, err := v.vacancyRepo.ListVacancyIdsByCompany(fromCompanyId, tx)
+		if err.IsError() {
+			return err
+		}
+
+		transferred, err = v.vacancyRepo.TransferVacancies(fromCompanyId, toCompanyId, tx)
+		if err.IsError() {
+			return err
+		}
+
+		changes := model.DiffAuditFields(map[string]any{"company_id": fromCompanyId}, map[string]any{"company_id": toCompanyId})
+
+		for _, vacancyId := range vacancyIds {
+			if err := v.recordVacancyAudit(vacancyId, enum.AuditUpdate, changes, tx); err.IsError() {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if limitErr.IsError() {
+		return 0, limitErr
+	}
+
+	if errTx != nil {
+		return 0, v.serviceError("failed to transfer the vacancies", "68", errTx)
+	}
+
+	return transferred, utils.Error{}
+}
+
+func (v *vacancyService) CountVacanciesByCompany(companyId int) (int, utils.Error) {
+	total, err := v.vacancyRepo.CountVacanciesByCompany(companyId, nil)
+	if err.IsError() {
+		return 0, v.serviceError("failed to count the company vacancies", "23", err)
+	}
+
+	return total, utils.Error{}
+}
+
+func (v *vacancyService) ListVacancyAreas() ([]string, utils.Error) {
+	areas, err := v.vacancyRepo.ListVacancyAreas()
+	if err.IsError() {
+		return []string{}, v.serviceError("failed to list the vacancy areas", "35", err)
+	}
+
+	return areas, utils.Error{}
+}
+
+func (v *vacancyService) ListVacancyCities() ([]string, utils.Error) {
+	cities, err := v.vacancyRepo.ListVacancyCities()
+	if err.IsError() {
+		return []string{}, v.serviceError("failed to list the vacancy cities", "72", err)
+	}
+
+	return cities, utils.Error{}
+}
+
+const (
+	defaultPopularVacancies     = 10
+	maxPopularVacancies         = 50
+	defaultPopularVacancyWindow = 30 * 24 * time.Hour
+)
+
+func popularVacancyWindow() time.Duration {
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err != nil || vacancyConfig.PopularWindow <= 0 {
+		return defaultPopularVacancyWindow
+	}
+
+	return vacancyConfig.PopularWindow
+}
+
+// ListPopularVacancies lists the open vacancies with the most applies in the popular
+// window, each with its applies count
+func (v *vacancyService) ListPopularVacancies(limit int) ([]modelVacancy.VacancySimpleResponse, utils.Error) {
+	vacanciesResponse := []modelVacancy.VacancySimpleResponse{}
+
+	if limit < 0 {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "23")
+
+		return vacanciesResponse, utils.NewError("limit must not be negative", errorCode)
+	}
+
+	if limit == 0 {
+		limit = defaultPopularVacancies
+	}
+
+	if limit > maxPopularVacancies {
+		limit = maxPopularVacancies
+	}
+
+	vacancies, err := v.vacancyRepo.ListPopularVacancies(time.Now().Add(-popularVacancyWindow()), limit)
+	if err.IsError() {
+		return vacanciesResponse, v.serviceError("failed to list the popular vacancies", "88", err)
+	}
+
+	for _, vacancy := range vacancies {
+		var disabilities []model.DisabilityResponse
+
+		for _, disability := range vacancy.Disabilities {
+			disabilities = append(disabilities, disability.ToResponse())
+		}
+
+		vacanciesResponse = append(vacanciesResponse, vacancy.ToSimpleResponse(disabilities))
+	}
+
+	return vacanciesResponse, utils.Error{}
+}
+
+// maxVacancyStatsDays keeps the daily series of a wide range from growing unbounded
+const maxVacancyStatsDays = 366 * 5
+
+// VacancyStats is the time series of the vacancies created and closed between from and
+// to, bucketed by day, week or month
+func (v *vacancyService) VacancyStats(from time.Time, to time.Time, interval string) ([]modelVacancy.VacancyStatsBucket, utils.Error) {
+	statsInterval := enum.VacancyStatsInterval(interval)
+	if !statsInterval.IsValid() {
+		message := "invalid interval. valid values are: 'day', 'week', 'month'"
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "24")
+
+		return []modelVacancy.VacancyStatsBucket{}, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "interval", Message: message}})
+	}
+
+	if !from.Before(to) {
+		message := "from must be before to"
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "25")
+
+		return []modelVacancy.VacancyStatsBucket{}, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "from", Message: message}})
+	}
+
+	if to.Sub(from) > maxVacancyStatsDays*24*time.Hour {
+		message := fmt.Sprintf("the range must be at most %d days", maxVacancyStatsDays)
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "26")
+
+		return []modelVacancy.VacancyStatsBucket{}, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "to", Message: message}})
+	}
+
+	buckets, err := v.vacancyRepo.VacancyStats(from, to, statsInterval)
+	if err.IsError() {
+		return []modelVacancy.VacancyStatsBucket{}, v.serviceError("failed to count the vacancy stats", "89", err)
+	}
+
+	return buckets, utils.Error{}
+}
+
+func (v *vacancyService) CountVacanciesGroupedByArea() (map[string]int, utils.Error) {
+	totals, err := v.vacancyRepo.CountVacanciesGroupedByArea()
+	if err.IsError() {
+		return map[string]int{}, v.serviceError("failed to count the vacancies by area", "24", err)
+	}
+
+	return totals, utils.Error{}
+}
+
+// VacancyFacets counts the vacancies per contract type applying every filter but the
+// contract type itself, so the counts of the other contract types stay visible
+func (v *vacancyService) VacancyFacets(filters modelVacancy.VacancyFilters) (map[enum.VacancyContractType]int, utils.Error) {
+	filters.ContractType = ""
+
+	totals, err := v.vacancyRepo.CountVacanciesGroupedByContractType(filters)
+	if err.IsError() {
+		return map[enum.VacancyContractType]int{}, v.serviceError("failed to count the vacancies by contract type", "50", err)
+	}
+
+	return totals, utils.Error{}
+}
+
+func (v *vacancyService) CandidateApplyVacancy(candidateId int, vacancyId int) utils.Error {
+	vacancy, err := v.vacancyRepo.GetVacancyById(vacancyId)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy", "10", err)
+	}
+
+	if vacancy.Id == 0 {
+		return vacancyNotFoundError("vacancy not found", "93")
+	}
+
+	if !vacancy.IsOpen() {
+		return vacancyConflictError("the vacancy is closed", "29")
+	}
+
+	person, err := v.personRepo.GetPersonById(candidateId, nil)
+	if err.IsError() {
+		return v.serviceError("failed to get the person", "11", err)
+	}
+
+	if person.Id == 0 {
+		return vacancyNotFoundError("candidate not found", "94")
+	}
+
+	vacancyApplyDb, err := v.vacancyAppliesRepo.GetVacancyApply(vacancyId, candidateId)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy apply", "95", err)
+	}
+
+	if vacancyApplyDb.Id != 0 {
+		return vacancyConflictError("the candidate already applied to the vacancy", "18")
+	}
+
+	vacancyApply := modelVacancy.VacancyApply{
+		VacancyId:   vacancyId,
+		CandidateId: candidateId,
+		Status:      enum.VacancyApplyApplied,
+	}
+
+	// a concurrent apply may have passed the check above, the unique index catches it
+	_, err = v.vacancyAppliesRepo.CreateVacancyApply(vacancyApply)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return vacancyConflictError("the candidate already applied to the vacancy", "18")
+	}
+
+	if err.IsError() {
+		return v.serviceError("failed to apply the vacancy", "12", err)
+	}
+
+	go v.notifyCompanyOfApply(vacancy, person)
+
+	return utils.Error{}
+}
+
+// notifyCompanyOfApply emails the owning company about a new application. It is best-effort:
+// failures are only logged so they never fail the application itself
+func (v *vacancyService) notifyCompanyOfApply(vacancy modelVacancy.Vacancy, person model.Person) {
+	log := logger.FromContext(v.ctx).With("vacancy_id", vacancy.Id)
+
+	company, err := v.companyRepo.GetCompanyById(vacancy.CompanyId)
+	if err.IsError() {
+		log.Error("failed to get the company to notify of the apply", "cause", err.Error())
+		return
+	}
+
+	if company.User == nil {
+		log.Warn("the company has no user to notify of the apply", "company_id", vacancy.CompanyId)
+		return
+	}
+
+	subject := fmt.Sprintf("Nova candidatura para a vaga %s", vacancy.Title)
+	body := fmt.Sprintf("Olá, %s!\n\n%s se candidatou à vaga %s.", company.Name, person.Name, vacancy.Title)
+
+	if err := v.mailer.Send(company.User.Email, subject, body); err != nil {
+		log.Error("failed to send the apply notification", "cause", err.Error())
+	}
+}
+
+// applicationsPage validates the status filter and the page of the applications
+// listings, defaulting them like the vacancy listing
+func applicationsPage(status enum.VacancyApplyStatus, page int, perPage int) (int, int, utils.Error) {
+	if status != "" && !status.IsValid() {
+		message := "invalid status. valid values are: 'applied', 'under_review', 'interview', 'rejected', 'accepted', 'withdrawn'"
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "18")
+
+		return 0, 0, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "status", Message: message}})
+	}
+
+	if page < 0 || perPage < 0 {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "19")
+
+		return 0, 0, utils.NewError("page and per page must not be negative", errorCode)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if perPage == 0 {
+		perPage = defaultVacanciesPerPage
+	}
+
+	if maxPerPage := maxVacanciesPerPage(); perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	return page, perPage, utils.Error{}
+}
+
+func (v *vacancyService) GetVacancyAppliesByVacancyId(vacancyId int, status enum.VacancyApplyStatus, page int, perPage int) (model.PaginatedResponse[modelVacancy.VacancyApplyResponse], utils.Error) {
+	page, perPage, err := applicationsPage(status, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.VacancyApplyResponse]{}, err
+	}
+
+	vacancyApplies, total, err := v.vacancyAppliesRepo.ListVacancyAppliesByVacancyId(vacancyId, status, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.VacancyApplyResponse]{}, v.serviceError("failed to get the vacancy applies", "13", err)
+	}
+
+	var vacancyAppliesResponse []modelVacancy.VacancyApplyResponse
+	for _, vacancyApply := range vacancyApplies {
+		person, err := v.personRepo.GetPersonById(vacancyApply.CandidateId, nil)
+		if err.IsError() {
+			return model.PaginatedResponse[modelVacancy.VacancyApplyResponse]{}, v.serviceError("failed to get the person", "14", err)
+		}
+
+		candidateDisabilities, err := v.personDisabilitiesRepo.GetPersonDisabilities(vacancyApply.CandidateId)
+		if err.IsError() {
+			return model.PaginatedResponse[modelVacancy.VacancyApplyResponse]{}, v.serviceError("failed to get the candidate disabilities", "15", err)
+		}
+
+		candidateDisabilitiesResponse := []model.DisabilityResponse{}
+		for _, candidateDisability := range candidateDisabilities {
+			candidateDisabilitiesResponse = append(candidateDisabilitiesResponse, candidateDisability.Disability.ToResponse())
+		}
+
+		vacancyApplyResponse := modelVacancy.VacancyApplyResponse{
+			Candidate:       vacancyApply.Candidate.ToCandidateResponse(candidateDisabilitiesResponse, *person.Address),
+			Status:          vacancyApply.Status,
+			Id:              vacancyApply.Id,
+			CreatedAt:       vacancyApply.CreatedAt,
+			StatusUpdatedAt: vacancyApply.StatusUpdatedAt,
+		}
+
+		vacancyAppliesResponse = append(vacancyAppliesResponse, vacancyApplyResponse)
+	}
+
+	return model.NewPaginatedResponse(vacancyAppliesResponse, total, page, perPage), utils.Error{}
+}
+
+func (v *vacancyService) GetVacancyAppliesByCandidateId(candidateId int) ([]modelVacancy.CandidateApplyResponse, utils.Error) {
+	vacancyApplies, err := v.vacancyAppliesRepo.ListVacancyAppliesByCandidateId(candidateId)
+	if err.IsError() {
+		return []modelVacancy.CandidateApplyResponse{}, v.serviceError("failed to get the candidate applies", "19", err)
+	}
+
+	candidateAppliesResponse := []modelVacancy.CandidateApplyResponse{}
+	for _, vacancyApply := range vacancyApplies {
+		var vacancyResponse modelVacancy.VacancySimpleResponse
+
+		if vacancyApply.Vacancy != nil {
+			var disabilities []model.DisabilityResponse
+
+			for _, disability := range vacancyApply.Vacancy.Disabilities {
+				disabilities = append(disabilities, disability.ToResponse())
+			}
+
+			vacancyResponse = vacancyApply.Vacancy.ToSimpleResponse(disabilities)
+		}
+
+		candidateAppliesResponse = append(candidateAppliesResponse, modelVacancy.CandidateApplyResponse{
+			Id:              vacancyApply.Id,
+			Vacancy:         vacancyResponse,
+			Status:          vacancyApply.Status,
+			CreatedAt:       vacancyApply.CreatedAt,
+			StatusUpdatedAt: vacancyApply.StatusUpdatedAt,
+		})
+	}
+
+	return candidateAppliesResponse, utils.Error{}
+}
+
+func (v *vacancyService) ListApplicationsByCandidate(candidateId int, status enum.VacancyApplyStatus, page int, perPage int) (model.PaginatedResponse[modelVacancy.CandidateApplication], utils.Error) {
+	page, perPage, err := applicationsPage(status, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.CandidateApplication]{}, err
+	}
+
+	applications, total, err := v.vacancyAppliesRepo.ListApplicationsByCandidateId(candidateId, status, page, perPage)
+	if err.IsError() {
+		return model.PaginatedResponse[modelVacancy.CandidateApplication]{}, v.serviceError("failed to list the candidate applications", "56", err)
+	}
+
+	return model.NewPaginatedResponse(applications, total, page, perPage), utils.Error{}
+}
+
+// applyTransitionError is the conflict of a status change the apply can't make, the same
+// for a single apply and for the bulk update
+func applyTransitionError(from enum.VacancyApplyStatus, to enum.VacancyApplyStatus) utils.Error {
+	return vacancyConflictError("cannot change the vacancy apply status from '"+string(from)+"' to '"+string(to)+"'", "22")
+}
+
+func (v *vacancyService) UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus) utils.Error {
+	vacancyApply, err := v.vacancyAppliesRepo.GetVacancyApplyById(vacancyApplyId)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy apply", "20", err)
+	}
+
+	if vacancyApply.Id == 0 {
+		return vacancyNotFoundError("vacancy apply not found", "21")
+	}
+
+	if !vacancyApply.Status.CanTransitionTo(status) {
+		return applyTransitionError(vacancyApply.Status, status)
+	}
+
+	err = v.vacancyAppliesRepo.UpdateVacancyApplyStatus(vacancyApplyId, status, nil)
+	if err.IsError() {
+		return v.serviceError("failed to update the vacancy apply status", "14", err)
+	}
+
+	return utils.Error{}
+}
+
+const maxBulkApplicationStatusIds = 100
+
+// BulkUpdateApplicationStatus moves every apply to the status, checking the transition
+// of each one. The applies are updated in one transaction with a savepoint per apply,
+// so a failed update is rolled back alone and reported with the others that failed.
+// Applies to vacancies of other companies than the caller's are reported as forbidden
+func (v *vacancyService) BulkUpdateApplicationStatus(ids []int, status enum.VacancyApplyStatus, caller model.UserClaims) (modelVacancy.VacancyApplyBulkStatusResult, utils.Error) {
+	result := modelVacancy.VacancyApplyBulkStatusResult{
+		Updated: []int{},
+		Failed:  []modelVacancy.VacancyApplyBulkStatusFailure{},
+	}
+
+	if len(ids) == 0 || len(ids) > maxBulkApplicationStatusIds {
+		message := fmt.Sprintf("between 1 and %d ids are required", maxBulkApplicationStatusIds)
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "22")
+
+		return result, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "ids", Message: message}})
+	}
+
+	fail := func(id int, err utils.Error) {
+		result.Failed = append(result.Failed, modelVacancy.VacancyApplyBulkStatusFailure{Id: id, Code: err.Code, Message: err.Message})
+	}
+
+	errTx := v.vacancyAppliesRepo.BeginTransaction(func(tx *gorm.DB) error {
+		vacancyApplies, err := v.vacancyAppliesRepo.ListVacancyAppliesByIds(ids, tx)
+		if err.IsError() {
+			return err
+		}
+
+		applies := map[int]modelVacancy.VacancyApply{}
+		for _, vacancyApply := range vacancyApplies {
+			applies[vacancyApply.Id] = vacancyApply
+		}
+
+		// the company check is the same for every apply of a company
+		authorized := map[int]utils.Error{}
+
+		seen := map[int]bool{}
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+
+			seen[id] = true
+
+			vacancyApply, found := applies[id]
+			if !found || vacancyApply.Vacancy == nil {
+				fail(id, vacancyNotFoundError("vacancy apply not found", "84"))
+				continue
+			}
+
+			companyId := vacancyApply.Vacancy.CompanyId
+			if _, checked := authorized[companyId]; !checked {
+				authorized[companyId] = v.authorizeVacancyCompany(companyId, caller)
+			}
+
+			if err := authorized[companyId]; err.IsError() {
+				fail(id, err)
+				continue
+			}
+
+			if !vacancyApply.Status.CanTransitionTo(status) {
+				fail(id, applyTransitionError(vacancyApply.Status, status))
+				continue
+			}
+
+			savepoint := "vacancy_apply_" + strconv.Itoa(id)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+
+			if err := v.vacancyAppliesRepo.UpdateVacancyApplyStatus(id, status, tx); err.IsError() {
+				if err := tx.RollbackTo(savepoint).Error; err != nil {
+					return err
+				}
+
+				fail(id, v.serviceError("failed to update the vacancy apply status", "86", err))
+				continue
+			}
+
+			result.Updated = append(result.Updated, id)
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		return modelVacancy.VacancyApplyBulkStatusResult{}, v.serviceError("failed to update the vacancy applies status", "83", errTx)
+	}
+
+	return result, utils.Error{}
+}
+
+// WithdrawApplication lets the candidate give up an apply that is still in progress, the
+// candidate is the person of the logged in user
+func (v *vacancyService) WithdrawApplication(applicationId int, caller model.UserClaims) utils.Error {
+	vacancyApply, err := v.vacancyAppliesRepo.GetVacancyApplyById(applicationId)
+	if err.IsError() {
+		return v.serviceError("failed to get the vacancy apply", "57", err)
+	}
+
+	if vacancyApply.Id == 0 {
+		return vacancyNotFoundError("vacancy apply not found", "58")
+	}
+
+	if caller.Role != string(enum.AdminRole) {
+		candidate, err := v.personRepo.GetPersonByUserId(caller.Id)
+		if err.IsError() {
+			return v.serviceError("failed to get the person", "90", err)
+		}
+
+		if candidate.Id == 0 || vacancyApply.CandidateId != candidate.Id {
+			return vacancyForbiddenError("the vacancy apply belongs to another candidate", "59")
+		}
+	}
+
+	if vacancyApply.Status.IsTerminal() {
+		return vacancyConflictError("the vacancy apply is already '"+string(vacancyApply.Status)+"'", "60")
+	}
+
+	err = v.vacancyAppliesRepo.UpdateVacancyApplyStatus(applicationId, enum.VacancyApplyWithdrawn, nil)
+	if err.IsError() {
+		return v.serviceError("failed to withdraw the vacancy apply", "61", err)
+	}
+
+	return utils.Error{}
+}
+
+var importRequiredColumns = []string{"title", "area", "contract_type", "disabilities"}
+
+// ImportVacancies creates a vacancy for the company from each csv row. Invalid rows
+// are reported in the result instead of aborting the whole import. Expected columns
+// are title, area, contract_type, disabilities (ids separated by semicolons) and the
+// optional salary_min and salary_max
+func (v *vacancyService) ImportVacancies(companyId int, r io.Reader, caller model.UserClaims) (modelVacancy.ImportResult, utils.Error) {
+	result := modelVacancy.ImportResult{Errors: []modelVacancy.ImportRowError{}}
+
+	if err := v.authorizeVacancyCompany(companyId, caller); err.IsError() {
+		return result, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "28")
+
+		return result, utils.NewErrorWithFields("failed to read the csv header", errorCode, []model.Field{{Name: "file", Message: err.Error()}})
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range importRequiredColumns {
+		if _, ok := columns[column]; !ok {
+			message := fmt.Sprintf("missing csv column: %s", column)
+			errorCode := utils.NewErrorCode(utils.ValidationErrorCode, utils.VacancyErrorType, "27")
+
+			return result, utils.NewErrorWithFields(message, errorCode, []model.Field{{Name: "file", Message: message}})
+		}
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			result.AddError(parseError.Line, parseError.Err.Error())
+			continue
+		}
+
+		if err != nil {
+			return result, v.serviceError("failed to read the csv", "32", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		vacancyRequest, rowError := importRowToRequest(record, columns)
+		if rowError != "" {
+			result.AddError(line, rowError)
+			continue
+		}
+
+		vacancyRequest.CompanyId = companyId
+		vacancyRequest.PublishDate = today
+		vacancyRequest.RegistrationDate = today
+		vacancyRequest.Publish = true
+
+		if _, err := v.CreateVacancy(vacancyRequest, ""); err.IsError() {
+			result.AddError(line, err.Message)
+			continue
+		}
+
+		result.Succeeded++
+	}
+
+	return result, utils.Error{}
+}
+
+func importRowToRequest(record []string, columns map[string]int) (modelVacancy.VacancyRequest, string) {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	vacancyRequest := modelVacancy.VacancyRequest{
+		Title:        value("title"),
+		Area:         value("area"),
+		ContractType: enum.VacancyContractType(strings.ToLower(value("contract_type"))),
+	}
+
+	if vacancyRequest.Area == "" {
+		return vacancyRequest, "area is required"
+	}
+
+	for _, disability := range strings.Split(value("disabilities"), ";") {
+		disability = strings.TrimSpace(disability)
+		if disability == "" {
+			continue
+		}
+
+		disabilityId, err := strconv.Atoi(disability)
+		if err != nil {
+			return vacancyRequest, fmt.Sprintf("invalid disability id: %s", disability)
+		}
+
+		vacancyRequest.Disabilities = append(vacancyRequest.Disabilities, modelVacancy.VacancyDisabilityRequest(disabilityId))
+	}
+
+	salaryMin, err := parseImportSalary(value("salary_min"))
+	if err != nil {
+		return vacancyRequest, fmt.Sprintf("invalid salary min: %s", value("salary_min"))
+	}
+
+	salaryMax, err := parseImportSalary(value("salary_max"))
+	if err != nil {
+		return vacancyRequest, fmt.Sprintf("invalid salary max: %s", value("salary_max"))
+	}
+
+	vacancyRequest.SalaryMin = salaryMin
+	vacancyRequest.SalaryMax = salaryMax
+
+	if validation := vacancyRequest.Validate(); validation.HasErrors() {
+		return vacancyRequest, validation.Fields[0].Message
+	}
+
+	if vacancyRequest.SalaryMin != nil && vacancyRequest.SalaryMax != nil && *vacancyRequest.SalaryMin > *vacancyRequest.SalaryMax {
+		return vacancyRequest, "salary min must not be greater than salary max"
+	}
+
+	return vacancyRequest, ""
+}
+
+func parseImportSalary(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	salary, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &salary, nil
+}
+
+var exportColumns = []string{
+	"id", "code", "title", "company", "area", "contract_type",
+	"salary_min", "salary_max", "publish_date", "expires_at", "disabilities",
+}
+
+// exportBatchSize is how many vacancies the export reads from the database at a time
+const exportBatchSize = 500
+
+// ExportVacancies streams every vacancy matching the filters as csv, without pagination.
+// The first batch is read before returning so a database failure is still reported, the
+// rest is read and written in batches while the returned reader is consumed
+func (v *vacancyService) ExportVacancies(filters modelVacancy.VacancyFilters) (io.Reader, utils.Error) {
+	vacancies, err := v.vacancyRepo.ListVacanciesBatch(filters, 0, exportBatchSize)
+	if err.IsError() {
+		return nil, v.serviceError("failed to list the vacancies", "33", err)
+	}
+
+	reader, pipe := io.Pipe()
+
+	go func() {
+		pipe.CloseWithError(v.writeVacanciesCsv(pipe, filters, vacancies))
+	}()
+
+	return reader, utils.Error{}
+}
+
+// writeVacanciesCsv writes the header and the first batch, then keeps reading the next
+// batches until one comes back shorter than the batch size
+func (v *vacancyService) writeVacanciesCsv(output io.Writer, filters modelVacancy.VacancyFilters, vacancies []modelVacancy.Vacancy) error {
+	log := logger.FromContext(v.ctx)
+	writer := csv.NewWriter(output)
+
+	if err := writer.Write(exportColumns); err != nil {
+		log.Error("failed to write the csv", "cause", err.Error())
+		return err
+	}
+
+	for offset := 0; ; offset += exportBatchSize {
+		if offset > 0 {
+			batch, err := v.vacancyRepo.ListVacanciesBatch(filters, offset, exportBatchSize)
+			if err.IsError() {
+				log.Error("failed to list the vacancies batch", "offset", offset, "cause", err.Error())
+				return err
+			}
+
+			vacancies = batch
+		}
+
+		for _, vacancy := range vacancies {
+			if err := writer.Write(exportRecord(vacancy)); err != nil {
+				log.Error("failed to write the csv", "cause", err.Error())
+				return err
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			log.Error("failed to write the csv", "cause", err.Error())
+			return err
+		}
+
+		if len(vacancies) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+func exportRecord(vacancy modelVacancy.Vacancy) []string {
+	disabilities := make([]string, 0, len(vacancy.Disabilities))
+	for _, disability := range vacancy.Disabilities {
+		disabilities = append(disabilities, disability.Description)
+	}
+
+	expiresAt := ""
+	if vacancy.ExpiresAt != nil {
+		expiresAt = *vacancy.ExpiresAt
+	}
+
+	return []string{
+		strconv.Itoa(vacancy.Id),
+		vacancy.Code,
+		vacancy.Title,
+		vacancy.Company.Name,
+		vacancy.Area,
+		string(vacancy.ContractType),
+		formatExportSalary(vacancy.SalaryMin),
+		formatExportSalary(vacancy.SalaryMax),
+		vacancy.PublishDate,
+		expiresAt,
+		strings.Join(disabilities, ";"),
+	}
+}
+
+func formatExportSalary(salary *float64) string {
+	if salary == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*salary, 'f', 2, 64)
 }
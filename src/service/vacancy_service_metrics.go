@@ -0,0 +1,303 @@
+package service
+
+import (
+	"cij_api/src/enum"
+	"cij_api/src/metrics"
+	"cij_api/src/model"
+	modelVacancy "cij_api/src/model/vacancy"
+	"cij_api/src/utils"
+	"context"
+	"io"
+	"time"
+)
+
+// instrumentedVacancyService reports the calls, errors and latency of each vacancy
+// service operation to the metrics registry
+type instrumentedVacancyService struct {
+	next     VacancyService
+	registry *metrics.Registry
+}
+
+// InstrumentVacancyService wraps the vacancy service so each method is measured,
+// labeled by its name
+func InstrumentVacancyService(next VacancyService, registry *metrics.Registry) VacancyService {
+	return &instrumentedVacancyService{
+		next:     next,
+		registry: registry,
+	}
+}
+
+func (s *instrumentedVacancyService) observe(operation string, start time.Time, err utils.Error) {
+	s.registry.Observe(operation, time.Since(start), err.IsError())
+}
+
+func (s *instrumentedVacancyService) WithContext(ctx context.Context) VacancyService {
+	return &instrumentedVacancyService{
+		next:     s.next.WithContext(ctx),
+		registry: s.registry,
+	}
+}
+
+func (s *instrumentedVacancyService) CreateVacancy(vacancy modelVacancy.VacancyRequest, idempotencyKey string) (int, utils.Error) {
+	start := time.Now()
+	result, err := s.next.CreateVacancy(vacancy, idempotencyKey)
+	s.observe("CreateVacancy", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListVacancies(filters modelVacancy.VacancyFilters, page int, perPage int) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListVacancies(filters, page, perPage)
+	s.observe("ListVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListVacanciesForCandidate(candidateId int, page int, perPage int, excludeApplied bool) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListVacanciesForCandidate(candidateId, page, perPage, excludeApplied)
+	s.observe("ListVacanciesForCandidate", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListCompanyVacancies(companyId int, filters modelVacancy.VacancyFilters, page int, perPage int, caller model.UserClaims) (model.PaginatedResponse[modelVacancy.VacancySimpleResponse], utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListCompanyVacancies(companyId, filters, page, perPage, caller)
+	s.observe("ListCompanyVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListCompaniesWithVacancies(page int, perPage int) (model.PaginatedResponse[modelVacancy.CompanyVacanciesResponse], utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListCompaniesWithVacancies(page, perPage)
+	s.observe("ListCompaniesWithVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) GetVacancyById(id int, candidateId int) (modelVacancy.VacancyResponse, utils.Error) {
+	start := time.Now()
+	result, err := s.next.GetVacancyById(id, candidateId)
+	s.observe("GetVacancyById", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) GetVacancyBySlug(slug string) (modelVacancy.VacancyResponse, utils.Error) {
+	start := time.Now()
+	result, err := s.next.GetVacancyBySlug(slug)
+	s.observe("GetVacancyBySlug", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) GetVacanciesByIds(ids []int) ([]modelVacancy.VacancySimpleResponse, utils.Error) {
+	start := time.Now()
+	result, err := s.next.GetVacanciesByIds(ids)
+	s.observe("GetVacanciesByIds", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) UpdateVacancy(vacancy modelVacancy.VacancyRequest, id int, caller model.UserClaims) utils.Error {
+	start := time.Now()
+	err := s.next.UpdateVacancy(vacancy, id, caller)
+	s.observe("UpdateVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) DeleteVacancy(id int, caller model.UserClaims) utils.Error {
+	start := time.Now()
+	err := s.next.DeleteVacancy(id, caller)
+	s.observe("DeleteVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) CloseVacancy(id int, caller model.UserClaims) utils.Error {
+	start := time.Now()
+	err := s.next.CloseVacancy(id, caller)
+	s.observe("CloseVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) ReopenVacancy(id int, newExpiresAt *time.Time, caller model.UserClaims) utils.Error {
+	start := time.Now()
+	err := s.next.ReopenVacancy(id, newExpiresAt, caller)
+	s.observe("ReopenVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) PublishVacancy(id int, caller model.UserClaims) utils.Error {
+	start := time.Now()
+	err := s.next.PublishVacancy(id, caller)
+	s.observe("PublishVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) DuplicateVacancy(id int, caller model.UserClaims) (int, utils.Error) {
+	start := time.Now()
+	result, err := s.next.DuplicateVacancy(id, caller)
+	s.observe("DuplicateVacancy", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) VacancyStats(from time.Time, to time.Time, interval string) ([]modelVacancy.VacancyStatsBucket, utils.Error) {
+	start := time.Now()
+	result, err := s.next.VacancyStats(from, to, interval)
+	s.observe("VacancyStats", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListPopularVacancies(limit int) ([]modelVacancy.VacancySimpleResponse, utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListPopularVacancies(limit)
+	s.observe("ListPopularVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) RecordVacancyView(id int, viewer string) utils.Error {
+	start := time.Now()
+	err := s.next.RecordVacancyView(id, viewer)
+	s.observe("RecordVacancyView", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) FeatureVacancy(id int, request modelVacancy.VacancyFeatureRequest) utils.Error {
+	start := time.Now()
+	err := s.next.FeatureVacancy(id, request)
+	s.observe("FeatureVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) TransferVacancies(fromCompanyId int, toCompanyId int) (int, utils.Error) {
+	start := time.Now()
+	result, err := s.next.TransferVacancies(fromCompanyId, toCompanyId)
+	s.observe("TransferVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) CountVacanciesByCompany(companyId int) (int, utils.Error) {
+	start := time.Now()
+	result, err := s.next.CountVacanciesByCompany(companyId)
+	s.observe("CountVacanciesByCompany", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) CountVacanciesGroupedByArea() (map[string]int, utils.Error) {
+	start := time.Now()
+	result, err := s.next.CountVacanciesGroupedByArea()
+	s.observe("CountVacanciesGroupedByArea", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) VacancyFacets(filters modelVacancy.VacancyFilters) (map[enum.VacancyContractType]int, utils.Error) {
+	start := time.Now()
+	result, err := s.next.VacancyFacets(filters)
+	s.observe("VacancyFacets", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListVacancyAreas() ([]string, utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListVacancyAreas()
+	s.observe("ListVacancyAreas", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListVacancyCities() ([]string, utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListVacancyCities()
+	s.observe("ListVacancyCities", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ImportVacancies(companyId int, r io.Reader, caller model.UserClaims) (modelVacancy.ImportResult, utils.Error) {
+	start := time.Now()
+	result, err := s.next.ImportVacancies(companyId, r, caller)
+	s.observe("ImportVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ExportVacancies(filters modelVacancy.VacancyFilters) (io.Reader, utils.Error) {
+	start := time.Now()
+	result, err := s.next.ExportVacancies(filters)
+	s.observe("ExportVacancies", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) CandidateApplyVacancy(candidateId int, vacancyId int) utils.Error {
+	start := time.Now()
+	err := s.next.CandidateApplyVacancy(candidateId, vacancyId)
+	s.observe("CandidateApplyVacancy", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) GetVacancyAppliesByVacancyId(vacancyId int, status enum.VacancyApplyStatus, page int, perPage int) (model.PaginatedResponse[modelVacancy.VacancyApplyResponse], utils.Error) {
+	start := time.Now()
+	result, err := s.next.GetVacancyAppliesByVacancyId(vacancyId, status, page, perPage)
+	s.observe("GetVacancyAppliesByVacancyId", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) GetVacancyAppliesByCandidateId(candidateId int) ([]modelVacancy.CandidateApplyResponse, utils.Error) {
+	start := time.Now()
+	result, err := s.next.GetVacancyAppliesByCandidateId(candidateId)
+	s.observe("GetVacancyAppliesByCandidateId", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) ListApplicationsByCandidate(candidateId int, status enum.VacancyApplyStatus, page int, perPage int) (model.PaginatedResponse[modelVacancy.CandidateApplication], utils.Error) {
+	start := time.Now()
+	result, err := s.next.ListApplicationsByCandidate(candidateId, status, page, perPage)
+	s.observe("ListApplicationsByCandidate", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) BulkUpdateApplicationStatus(ids []int, status enum.VacancyApplyStatus, caller model.UserClaims) (modelVacancy.VacancyApplyBulkStatusResult, utils.Error) {
+	start := time.Now()
+	result, err := s.next.BulkUpdateApplicationStatus(ids, status, caller)
+	s.observe("BulkUpdateApplicationStatus", start, err)
+
+	return result, err
+}
+
+func (s *instrumentedVacancyService) UpdateVacancyApplyStatus(vacancyApplyId int, status enum.VacancyApplyStatus) utils.Error {
+	start := time.Now()
+	err := s.next.UpdateVacancyApplyStatus(vacancyApplyId, status)
+	s.observe("UpdateVacancyApplyStatus", start, err)
+
+	return err
+}
+
+func (s *instrumentedVacancyService) WithdrawApplication(applicationId int, caller model.UserClaims) utils.Error {
+	start := time.Now()
+	err := s.next.WithdrawApplication(applicationId, caller)
+	s.observe("WithdrawApplication", start, err)
+
+	return err
+}
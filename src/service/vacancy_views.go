@@ -0,0 +1,73 @@
+package service
+
+import (
+	"cij_api/src/config"
+	"cij_api/src/utils"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultVacancyViewWindow = 30 * time.Minute
+	vacancyViewSweepSize     = 10000
+)
+
+// viewThrottle remembers which viewer saw which vacancy in the window, so refreshing
+// the page does not count the view again
+type viewThrottle struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time
+	window time.Duration
+}
+
+func newViewThrottle() *viewThrottle {
+	window := defaultVacancyViewWindow
+
+	vacancyConfig, err := config.LoadVacancyConfig(".")
+	if err == nil && vacancyConfig.ViewThrottleWindow > 0 {
+		window = vacancyConfig.ViewThrottleWindow
+	}
+
+	return &viewThrottle{
+		seen:   map[string]time.Time{},
+		window: window,
+	}
+}
+
+// allow reports whether the view counts, marking the viewer as seen for the window
+func (t *viewThrottle) allow(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	if len(t.seen) >= vacancyViewSweepSize {
+		for seenKey, expiresAt := range t.seen {
+			if now.After(expiresAt) {
+				delete(t.seen, seenKey)
+			}
+		}
+	}
+
+	if expiresAt, ok := t.seen[key]; ok && now.Before(expiresAt) {
+		return false
+	}
+
+	t.seen[key] = now.Add(t.window)
+
+	return true
+}
+
+// RecordVacancyView counts a view of the vacancy, once per viewer in the throttle window
+func (v *vacancyService) RecordVacancyView(id int, viewer string) utils.Error {
+	if !v.viewThrottle.allow(viewer + "|" + strconv.Itoa(id)) {
+		return utils.Error{}
+	}
+
+	if err := v.vacancyRepo.IncrementVacancyView(id); err.IsError() {
+		return v.serviceError("failed to count the vacancy view", "87", err)
+	}
+
+	return utils.Error{}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import "strings"
+
+func NormalizeCnpj(cnpj string) string {
+	var builder strings.Builder
+
+	for _, char := range cnpj {
+		if char >= '0' && char <= '9' {
+			builder.WriteRune(char)
+		}
+	}
+
+	return builder.String()
+}
+
+func ValidateCNPJ(cnpj string) bool {
+	cnpj = NormalizeCnpj(cnpj)
+
+	if len(cnpj) != 14 {
+		return false
+	}
+
+	if strings.Count(cnpj, string(cnpj[0])) == len(cnpj) {
+		return false
+	}
+
+	firstWeights := []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	secondWeights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+	return cnpjDigit(cnpj[:12], firstWeights) == int(cnpj[12]-'0') &&
+		cnpjDigit(cnpj[:13], secondWeights) == int(cnpj[13]-'0')
+}
+
+func cnpjDigit(digits string, weights []int) int {
+	sum := 0
+
+	for i, weight := range weights {
+		sum += int(digits[i]-'0') * weight
+	}
+
+	rest := sum % 11
+	if rest < 2 {
+		return 0
+	}
+
+	return 11 - rest
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"net/mail"
+	"strings"
+)
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func ValidateEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
+
+	return address.Address == email && strings.Contains(address.Address[strings.LastIndex(address.Address, "@"):], ".")
+}
@@ -3,18 +3,37 @@ package utils
 import (
 	"cij_api/src/model"
 	"fmt"
+	"net/http"
+	"strconv"
 )
 
 type Error struct {
 	Message string        `json:"message"`
 	Code    string        `json:"code"`
 	Fields  []model.Field `json:"fields,omitempty"`
+	// Cause keeps the original error for logging, never for the api response
+	Cause error `json:"-"`
 }
 
 func (e Error) Error() string {
 	return e.Message
 }
 
+func (e Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e Error) WithCause(cause error) Error {
+	e.Cause = cause
+
+	return e
+}
+
+// IsError reports whether the error carries a code, meaning something went wrong
+func (e Error) IsError() bool {
+	return e.Code != ""
+}
+
 func (e Error) GetCode() string {
 	return e.Code
 }
@@ -54,8 +73,40 @@ const (
 	DatabaseErrorCode   ErrorType = 2
 	ServiceErrorCode    ErrorType = 3
 	ControllerErrorCode ErrorType = 4
+	NotFoundErrorCode   ErrorType = 5
+	ConflictErrorCode   ErrorType = 6
+	ForbiddenErrorCode  ErrorType = 7
+	// the request carries no valid token
+	UnauthorizedErrorCode ErrorType = 8
 )
 
+// HttpStatus maps the error type encoded in the first digit of the code to an http status
+func HttpStatus(err Error) int {
+	if !err.IsError() {
+		return http.StatusOK
+	}
+
+	errorType, convErr := strconv.Atoi(err.Code[:1])
+	if convErr != nil {
+		return http.StatusInternalServerError
+	}
+
+	switch ErrorType(errorType) {
+	case ValidationErrorCode:
+		return http.StatusBadRequest
+	case NotFoundErrorCode:
+		return http.StatusNotFound
+	case ConflictErrorCode:
+		return http.StatusConflict
+	case ForbiddenErrorCode:
+		return http.StatusForbidden
+	case UnauthorizedErrorCode:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 type ErrorEntity int
 
 const (
@@ -69,4 +120,8 @@ const (
 	ActivityErrorType   ErrorEntity = 8
 	ReportsErrorType    ErrorEntity = 9
 	VacancyErrorType    ErrorEntity = 10
+	CandidateErrorType  ErrorEntity = 11
+	JobAlertErrorType   ErrorEntity = 12
+	AuditLogErrorType   ErrorEntity = 13
+	RequestErrorType    ErrorEntity = 14
 )
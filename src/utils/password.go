@@ -1,11 +1,74 @@
 package utils
 
 import (
+	"cij_api/src/config"
+	"cij_api/src/model"
 	"errors"
+	"fmt"
+	"sync"
+	"unicode"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// passwordPolicy is read once, when the config can't be loaded the defaults apply
+var passwordPolicy = sync.OnceValue(func() config.PasswordConfig {
+	passwordConfig, err := config.LoadPasswordConfig(".")
+	if err != nil {
+		return config.PasswordConfig{MinLength: 8, RequireLower: true, RequireUpper: true, RequireDigit: true}
+	}
+
+	return passwordConfig
+})
+
+// ValidatePasswordStrength checks the password against the configured policy. The
+// error has the code of the first rule violated and a field for each one, so the
+// frontend can show every rule missing at once
+func ValidatePasswordStrength(password string) Error {
+	validation := model.ValidationError{}
+	addPasswordViolations(&validation, password)
+
+	return ValidationFailed(UserErrorType, validation)
+}
+
+func addPasswordViolations(validation *model.ValidationError, password string) {
+	policy := passwordPolicy()
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSymbol = true
+		}
+	}
+
+	if len([]rune(password)) < policy.MinLength {
+		validation.Add("password", "08", fmt.Sprintf("the password must have at least %d characters", policy.MinLength))
+	}
+
+	if policy.RequireLower && !hasLower {
+		validation.Add("password", "09", "the password must have a lowercase letter")
+	}
+
+	if policy.RequireUpper && !hasUpper {
+		validation.Add("password", "10", "the password must have an uppercase letter")
+	}
+
+	if policy.RequireDigit && !hasDigit {
+		validation.Add("password", "11", "the password must have a digit")
+	}
+
+	if policy.RequireSymbol && !hasSymbol {
+		validation.Add("password", "12", "the password must have a symbol")
+	}
+}
+
 func EncryptPassword(password string) (string, error) {
 	passwordBytes := []byte(password)
 
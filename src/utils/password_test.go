@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"cij_api/src/model"
+	"testing"
+)
+
+func TestEncryptPasswordVerifies(t *testing.T) {
+	hashedPassword, err := EncryptPassword("Sup3rSecret")
+	if err != nil {
+		t.Fatalf("EncryptPassword returned an error: %v", err)
+	}
+
+	if hashedPassword == "Sup3rSecret" {
+		t.Fatal("the password was stored in plain text")
+	}
+
+	user := model.User{Password: hashedPassword}
+
+	if !user.ValidatePassword("Sup3rSecret") {
+		t.Error("the stored hash doesn't verify the password")
+	}
+
+	if user.ValidatePassword("wrong password") {
+		t.Error("the stored hash verifies a wrong password")
+	}
+}
+
+func TestEncryptPasswordIsSalted(t *testing.T) {
+	first, err := EncryptPassword("Sup3rSecret")
+	if err != nil {
+		t.Fatalf("EncryptPassword returned an error: %v", err)
+	}
+
+	second, err := EncryptPassword("Sup3rSecret")
+	if err != nil {
+		t.Fatalf("EncryptPassword returned an error: %v", err)
+	}
+
+	if first == second {
+		t.Error("two users with the same password got the same hash")
+	}
+}
@@ -0,0 +1,39 @@
+package utils
+
+import "strings"
+
+const phoneCountryCode = "55"
+
+func NormalizePhone(phone string) (string, Error) {
+	errorCode := NewErrorCode(ValidationErrorCode, CompanyErrorType, "04")
+
+	var builder strings.Builder
+
+	for _, char := range phone {
+		if char >= '0' && char <= '9' {
+			builder.WriteRune(char)
+		}
+	}
+
+	digits := strings.TrimPrefix(builder.String(), "00")
+	digits = strings.TrimLeft(digits, "0")
+
+	if len(digits) == 10 || len(digits) == 11 {
+		digits = phoneCountryCode + digits
+	}
+
+	if !strings.HasPrefix(digits, phoneCountryCode) || len(digits) < 12 {
+		return "", NewError("phone must be a brazilian number with area code", errorCode)
+	}
+
+	areaCode := digits[2:4]
+	if areaCode[0] == '0' || areaCode[1] == '0' {
+		return "", NewError("phone has an invalid area code", errorCode)
+	}
+
+	if len(digits) != 13 || digits[4] != '9' {
+		return "", NewError("phone must be a mobile number with 13 digits including country and area code", errorCode)
+	}
+
+	return digits, Error{}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// allowedHtmlTags is the formatting subset kept by SanitizeHtml, always without attributes
+var allowedHtmlTags = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true,
+	"p": true, "br": true, "ul": true, "ol": true, "li": true,
+}
+
+// droppedHtmlTags have their whole content removed, not only the tags
+var droppedHtmlTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+}
+
+// SanitizeHtml keeps only the allowed formatting tags, dropping every attribute (so event
+// handlers and javascript urls are gone) and escaping the remaining text
+func SanitizeHtml(input string) string {
+	var builder strings.Builder
+
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(input))
+	dropping := ""
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				return html.EscapeString(input)
+			}
+
+			return builder.String()
+		}
+
+		token := tokenizer.Token()
+
+		if dropping != "" {
+			if tokenType == xhtml.EndTagToken && token.Data == dropping {
+				dropping = ""
+			}
+			continue
+		}
+
+		switch tokenType {
+		case xhtml.TextToken:
+			builder.WriteString(html.EscapeString(token.Data))
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if droppedHtmlTags[token.Data] && tokenType == xhtml.StartTagToken {
+				dropping = token.Data
+				continue
+			}
+
+			if allowedHtmlTags[token.Data] {
+				builder.WriteString("<" + token.Data + ">")
+			}
+		case xhtml.EndTagToken:
+			if allowedHtmlTags[token.Data] && token.Data != "br" {
+				builder.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
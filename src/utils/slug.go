@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxSlugLength = 200
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Slugify lowercases the text, strips accents and joins the words with hyphens
+func Slugify(text string) string {
+	text = accentReplacer.Replace(strings.ToLower(text))
+
+	var builder strings.Builder
+	lastHyphen := true
+
+	for _, r := range text {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			builder.WriteRune(r)
+			lastHyphen = false
+			continue
+		}
+
+		if !lastHyphen {
+			builder.WriteRune('-')
+			lastHyphen = true
+		}
+	}
+
+	slug := strings.Trim(builder.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.Trim(slug[:maxSlugLength], "-")
+	}
+
+	return slug
+}
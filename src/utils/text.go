@@ -0,0 +1,9 @@
+package utils
+
+import "strings"
+
+// CollapseSpaces trims the text and replaces every run of whitespace inside it,
+// tabs and newlines included, with a single space
+func CollapseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -2,6 +2,34 @@ package utils
 
 import "cij_api/src/model"
 
+// ValidationFailed turns the collected failures into a validation error of the entity
+// listing every field with its full code. The code and message are the ones of the
+// first failure, an empty validation is no error
+func ValidationFailed(entity ErrorEntity, validation model.ValidationError) Error {
+	return NewValidationError(EntityValidation(entity, validation))
+}
+
+// EntityValidation builds the full codes of the failures of the entity, so the failures
+// of several entities can be merged into one validation
+func EntityValidation(entity ErrorEntity, validation model.ValidationError) model.ValidationError {
+	qualified := model.ValidationError{}
+	for _, field := range validation.Fields {
+		qualified.Add(field.Name, NewErrorCode(ValidationErrorCode, entity, field.Code), field.Message)
+	}
+
+	return qualified
+}
+
+// NewValidationError turns a validation whose codes are already full into an error, the
+// code and message are the ones of the first failure
+func NewValidationError(validation model.ValidationError) Error {
+	if !validation.HasErrors() {
+		return Error{}
+	}
+
+	return NewErrorWithFields(validation.Fields[0].Message, validation.Fields[0].Code, validation.Fields)
+}
+
 func ValidateAddress(addressRequest model.AddressRequest) Error {
 	fieldsWithErrors := []model.Field{}
 
@@ -26,7 +54,7 @@ func ValidateAddress(addressRequest model.AddressRequest) Error {
 	}
 
 	if len(addressRequest.State) != 2 {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "state", Value: "state must have 2 characters"})
+		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "state", Message: "state must have 2 characters"})
 	}
 
 	if addressRequest.ZipCode == "" {
@@ -34,7 +62,7 @@ func ValidateAddress(addressRequest model.AddressRequest) Error {
 	}
 
 	if len(addressRequest.ZipCode) != 8 {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "zip code", Value: "zip code must have 8 digits"})
+		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "zip code", Message: "zip code must have 8 digits"})
 	}
 
 	if addressRequest.Country == "" {
@@ -50,22 +78,24 @@ func ValidateAddress(addressRequest model.AddressRequest) Error {
 	return Error{}
 }
 
+// ValidateUser reports the invalid fields of the user together with every password
+// strength rule violated
 func ValidateUser(user model.UserRequest) Error {
-	fieldsWithErrors := []model.Field{}
+	return NewValidationError(UserValidation(user))
+}
 
-	if user.Email == "" {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "email"})
-	}
+// UserValidation collects the failures of ValidateUser with their full codes, for the
+// requests that validate the user along with other fields
+func UserValidation(user model.UserRequest) model.ValidationError {
+	validation := user.Validate()
 
-	if user.Password == "" {
-		fieldsWithErrors = append(fieldsWithErrors, model.Field{Name: "password"})
+	if user.Email != "" && !ValidateEmail(NormalizeEmail(user.Email)) {
+		validation.Add("email", "02", "email is not valid")
 	}
 
-	if len(fieldsWithErrors) > 0 {
-		errorCode := NewErrorCode(ValidationErrorCode, UserErrorType, "01")
-
-		return NewErrorWithFields("required fields are missing", errorCode, fieldsWithErrors)
+	if user.Password != "" {
+		addPasswordViolations(&validation, user.Password)
 	}
 
-	return Error{}
+	return EntityValidation(UserErrorType, validation)
 }